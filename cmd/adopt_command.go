@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newAdoptCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "adopt <path>",
+		Short: "Manage a worktree created outside <repo>.wt",
+		Long:  "Records an existing git worktree of this repository as managed, without moving it, so wtx can lock and delete it like the worktrees it creates.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runAdopt(os.Stdout, NewWorktreeManager("", NewLockManager()), args[0])
+		},
+	}
+}
+
+func runAdopt(w io.Writer, mgr *WorktreeManager, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	wt, err := mgr.AdoptWorktree(abs)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Adopted %s (%s)\n", wt.Path, wt.Branch)
+	return nil
+}
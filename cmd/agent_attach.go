@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var tmuxPaneLocationFn = tmuxPaneLocation
+
+type agentAttachDoneMsg struct {
+	err error
+}
+
+// tmuxPaneLocation reports the session and window paneID currently lives in;
+// it fails once the pane is gone.
+func tmuxPaneLocation(paneID string) (agentPane, error) {
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", paneID, "#{session_id}\t#{window_id}\t#{pane_id}").Output()
+	if err != nil {
+		return agentPane{}, err
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(fields) != 3 || fields[2] != paneID {
+		return agentPane{}, errors.New("tmux pane not found")
+	}
+	return agentPane{SessionID: fields[0], WindowID: fields[1], PaneID: fields[2]}, nil
+}
+
+// rememberAgentPane records the pane an agent was just started in for the
+// worktree; failures only cost the attach offer later.
+func rememberAgentPane(worktreePath string, paneID string) {
+	repoRoot, err := repoRootForDir(worktreePath, "")
+	if err != nil {
+		return
+	}
+	pane, err := tmuxPaneLocationFn(paneID)
+	if err != nil {
+		return
+	}
+	if err := recordAgentPane(repoRoot, worktreePath, pane); err != nil {
+		logDebug("recording agent pane failed", "path", worktreePath, "err", err)
+	}
+}
+
+// liveAgentPane returns the tmux pane still running the worktree's agent, and
+// forgets panes that have since closed.
+func liveAgentPane(repoRoot string, worktreePath string) (agentPane, bool) {
+	recorded, ok := agentPaneForWorktree(repoRoot, worktreePath)
+	if !ok || tmuxIntegrationDisabled() {
+		return agentPane{}, false
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return agentPane{}, false
+	}
+	pane, err := tmuxPaneLocationFn(recorded.PaneID)
+	if err != nil {
+		_ = clearAgentPane(repoRoot, worktreePath)
+		return agentPane{}, false
+	}
+	return pane, true
+}
+
+// attachAgentPaneCmd focuses pane: inside tmux the client switches to it,
+// otherwise tmux attaches in this terminal until the user detaches.
+func attachAgentPaneCmd(pane agentPane) tea.Cmd {
+	if strings.TrimSpace(os.Getenv("TMUX")) == "" {
+		attach := exec.Command("tmux", "attach-session", "-t", pane.SessionID,
+			";", "select-window", "-t", pane.WindowID,
+			";", "select-pane", "-t", pane.PaneID)
+		return tea.ExecProcess(attach, func(err error) tea.Msg {
+			return agentAttachDoneMsg{err: err}
+		})
+	}
+	return func() tea.Msg {
+		if err := exec.Command("tmux", "select-window", "-t", pane.WindowID).Run(); err != nil {
+			return agentAttachDoneMsg{err: errors.New("agent window is gone")}
+		}
+		_ = exec.Command("tmux", "select-pane", "-t", pane.PaneID).Run()
+		if current, err := currentSessionID(); err != nil || current != pane.SessionID {
+			if err := exec.Command("tmux", "switch-client", "-t", pane.SessionID).Run(); err != nil {
+				return agentAttachDoneMsg{err: err}
+			}
+		}
+		return agentAttachDoneMsg{}
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// useAgentPTY reports whether agent_pty asks for the agent to run behind a
+// proxied PTY. Raw input needs a terminal on stdin; stdout may be a pipe, which
+// is the case the PTY exists for.
+func useAgentPTY(worktreePath string, openShell bool) bool {
+	if openShell || !isInteractiveTerminal(os.Stdin) {
+		return false
+	}
+	cfg, err := loadConfigForDir(worktreePath)
+	return err == nil && cfg.AgentPTY
+}
+
+// runWithAgentPTY runs runCmd on a PTY sized like the controlling terminal and
+// proxies it, so the agent keeps colors and prompts even when wtx's own stdout
+// is redirected. Window size changes are forwarded; signal keys reach the agent
+// through the PTY's line discipline since stdin is put in raw mode.
+func (r *Runner) runWithAgentPTY(worktreePath string, workDir string, branch string, lock *WorktreeLock, runCmd string) (RunResult, error) {
+	started, err := r.runOnPTY(worktreePath, workDir, branch, lock, runCmd, int(os.Stdin.Fd()), ptyView{
+		setSize: func(master *os.File, ws *unix.Winsize) {
+			_ = unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, ws)
+		},
+		copyOutput: func(master io.Reader) { _, _ = io.Copy(os.Stdout, master) },
+	})
+	if !started {
+		return RunResult{}, err
+	}
+	return RunResult{Started: true, Warning: "tmux unavailable; running in current terminal"}, err
+}
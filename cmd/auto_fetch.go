@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	autoFetchInterval = time.Minute
+	autoFetchTimeout  = 30 * time.Second
+)
+
+var (
+	autoFetchMu   sync.Mutex
+	autoFetchLast = map[string]time.Time{}
+	autoFetchFn   = runAutoFetch
+)
+
+type autoFetchDoneMsg struct {
+	repoRoot string
+	err      error
+}
+
+// autoFetchCmd runs a background `git fetch` for repoRoot at most once per
+// autoFetchInterval so ahead/behind and conflict state follow the remote.
+func autoFetchCmd(repoRoot string) tea.Cmd {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" || !claimAutoFetch(repoRoot, time.Now()) {
+		return nil
+	}
+	return func() tea.Msg {
+		return autoFetchDoneMsg{repoRoot: repoRoot, err: autoFetchFn(repoRoot)}
+	}
+}
+
+func claimAutoFetch(repoRoot string, now time.Time) bool {
+	autoFetchMu.Lock()
+	defer autoFetchMu.Unlock()
+	if last, ok := autoFetchLast[repoRoot]; ok && now.Sub(last) < autoFetchInterval {
+		return false
+	}
+	autoFetchLast[repoRoot] = now
+	return true
+}
+
+func runAutoFetch(repoRoot string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), autoFetchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, gitBinary(), "fetch", "--quiet", "--prune")
+	cmd.Dir = repoRoot
+	// Never block on credential prompts in the background.
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return commandErrorWithOutput(err, out)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoFetchCmd_ThrottlesPerRepo(t *testing.T) {
+	var fetched []string
+	prev := autoFetchFn
+	autoFetchFn = func(repoRoot string) error {
+		fetched = append(fetched, repoRoot)
+		return nil
+	}
+	t.Cleanup(func() {
+		autoFetchFn = prev
+		autoFetchMu.Lock()
+		delete(autoFetchLast, "/repo/a")
+		delete(autoFetchLast, "/repo/b")
+		autoFetchMu.Unlock()
+	})
+
+	cmd := autoFetchCmd("/repo/a")
+	if cmd == nil {
+		t.Fatalf("expected first fetch to run")
+	}
+	if msg, ok := cmd().(autoFetchDoneMsg); !ok || msg.repoRoot != "/repo/a" || msg.err != nil {
+		t.Fatalf("unexpected msg %#v", msg)
+	}
+	if autoFetchCmd("/repo/a") != nil {
+		t.Fatalf("expected a second fetch within the interval to be throttled")
+	}
+	if autoFetchCmd("/repo/b") == nil {
+		t.Fatalf("expected another repo to fetch independently")
+	}
+	if !claimAutoFetch("/repo/a", time.Now().Add(autoFetchInterval)) {
+		t.Fatalf("expected fetch to be allowed again after the interval")
+	}
+	if len(fetched) != 1 {
+		t.Fatalf("expected one fetch to run, got %v", fetched)
+	}
+}
@@ -16,3 +16,13 @@ func resolveNewBranchBaseRef(configBaseRef string, statusBaseRef string, hasRemo
 	}
 	return "origin/main"
 }
+
+// newBranchBaseRef is the default base for a new branch named branch: the
+// first matching base_ref_rules entry, otherwise fallback(). Every place that
+// defaults a new branch's base goes through here so the rules apply evenly.
+func newBranchBaseRef(rules []BaseRefRule, branch string, fallback func() string) string {
+	if base, ok := baseRefForBranch(rules, branch); ok {
+		return base
+	}
+	return fallback()
+}
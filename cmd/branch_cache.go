@@ -15,10 +15,19 @@ type recentBranchCache struct {
 	Branches []string `json:"branches"`
 }
 
+// errStateDirUnset is reported up front so minimal container/CI environments
+// don't fail deep inside lock acquisition.
+var errStateDirUnset = errors.New("HOME (or WTX_STATE_DIR) is not set; wtx needs a state directory. Set HOME, or set WTX_STATE_DIR to a writable directory")
+
+// wtxHomeDir is where wtx keeps config, locks and caches: $WTX_STATE_DIR when
+// set, otherwise ~/.wtx.
 func wtxHomeDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(stateDirOverrideEnv)); dir != "" {
+		return dir, nil
+	}
 	home := strings.TrimSpace(os.Getenv("HOME"))
 	if home == "" {
-		return "", errors.New("HOME not set")
+		return "", errStateDirUnset
 	}
 	return filepath.Join(home, ".wtx"), nil
 }
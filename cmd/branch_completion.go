@@ -35,6 +35,56 @@ func branchExistsLocalOrRemote(repoRoot string, gitPath string, branch string) (
 	return false, nil
 }
 
+// branchNameSuggestions lists local then remote-tracking branch names to
+// complete the new-branch inputs with.
+func branchNameSuggestions(repoRoot string) []string {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" {
+		return nil
+	}
+	gitPath := gitBinary()
+	seen := map[string]bool{}
+	var out []string
+	local, _ := listLocalBranchNames(repoRoot, gitPath, 0)
+	remote, _ := listRemoteTrackingBranchNames(repoRoot, gitPath, 0)
+	for _, name := range append(local, remote...) {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "detached" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+// completeBranchName returns the first suggestion that extends value.
+func completeBranchName(value string, suggestions []string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", false
+	}
+	for _, s := range suggestions {
+		if len(s) > len(value) && strings.HasPrefix(strings.ToLower(s), strings.ToLower(value)) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func containsBranch(branches []string, branch string) bool {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return false
+	}
+	for _, b := range branches {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
+
 func completeBranchSuggestions(toComplete string) []string {
 	gitPath, repoRoot, err := requireGitContext("")
 	if err != nil {
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+func configuredExcludeBranchPatterns() []string {
+	if cfg, err := LoadConfig(); err == nil {
+		return cfg.ExcludeBranchPatterns
+	}
+	return nil
+}
+
+// excludeBranches drops branches matching any glob in patterns. Branches in
+// keep (those checked out in a worktree) are never dropped so they stay
+// manageable.
+func excludeBranches(branches []string, patterns []string, keep map[string]bool) []string {
+	matchers := compileGlobs(patterns)
+	if len(matchers) == 0 {
+		return branches
+	}
+	kept := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		name := strings.TrimSpace(branch)
+		if !keep[name] && matchesAnyGlob(matchers, name) {
+			continue
+		}
+		kept = append(kept, branch)
+	}
+	return kept
+}
+
+// compileGlobs turns globs into anchored regexps. Unlike path.Match, * also
+// crosses "/" so dependabot/* covers dependabot/npm/lodash-4.17.21.
+func compileGlobs(patterns []string) []*regexp.Regexp {
+	matchers := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString("^")
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		b.WriteString("$")
+		matchers = append(matchers, regexp.MustCompile(b.String()))
+	}
+	return matchers
+}
+
+func matchesAnyGlob(matchers []*regexp.Regexp, name string) bool {
+	for _, re := range matchers {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtectedBranch reports whether branch is the repo's default branch or
+// matches one of the protected globs. defaultBranch is the base ref, usually
+// on baseRemote (which may be a remote other than origin).
+func isProtectedBranch(branch string, defaultBranch string, baseRemote string, patterns []string) bool {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "detached" {
+		return false
+	}
+	if defaultBranch = strings.TrimSpace(defaultBranch); defaultBranch != "" && branch == baseRefBranchName(defaultBranch, baseRemote) {
+		return true
+	}
+	return matchesAnyGlob(compileGlobs(patterns), branch)
+}
+
+// baseRefBranchName is the local branch name for baseRef, with remote's
+// prefix removed.
+func baseRefBranchName(baseRef string, remote string) string {
+	baseRef = strings.TrimPrefix(strings.TrimSpace(baseRef), "refs/remotes/")
+	if remote = strings.TrimSpace(remote); remote != "" {
+		baseRef = strings.TrimPrefix(baseRef, remote+"/")
+	}
+	return shortBranch(baseRef)
+}
+
+func openSlotBranchSet(slots []openSlotState) map[string]bool {
+	set := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		if name := strings.TrimSpace(slot.Branch); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
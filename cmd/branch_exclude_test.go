@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExcludeBranches(t *testing.T) {
+	branches := []string{"main", "gh-pages", "dependabot/npm/lodash-4.17.21", "feature/a", "release-1.2"}
+	tests := []struct {
+		name     string
+		patterns []string
+		keep     map[string]bool
+		want     []string
+	}{
+		{name: "no patterns", want: branches},
+		{name: "exact and nested glob", patterns: []string{"gh-pages", "dependabot/*"}, want: []string{"main", "feature/a", "release-1.2"}},
+		{name: "question mark", patterns: []string{"release-?.?"}, want: []string{"main", "gh-pages", "dependabot/npm/lodash-4.17.21", "feature/a"}},
+		{name: "worktree branches kept", patterns: []string{"*"}, keep: map[string]bool{"feature/a": true}, want: []string{"feature/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := excludeBranches(branches, tt.patterns, tt.keep)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	tests := []struct {
+		branch   string
+		baseRef  string
+		remote   string
+		patterns []string
+		want     bool
+	}{
+		{branch: "main", want: true},
+		{branch: "feature/a", want: false},
+		{branch: "release/1.2", patterns: []string{"release/*"}, want: true},
+		{branch: "detached", want: false},
+		{branch: "main", baseRef: "upstream/main", remote: "upstream", want: true},
+		{branch: "develop", baseRef: "upstream/develop", remote: "upstream", want: true},
+		{branch: "main", baseRef: "upstream/develop", remote: "upstream", want: false},
+	}
+	for _, tt := range tests {
+		baseRef, remote := tt.baseRef, tt.remote
+		if baseRef == "" {
+			baseRef, remote = "origin/main", "origin"
+		}
+		if got := isProtectedBranch(tt.branch, baseRef, remote, tt.patterns); got != tt.want {
+			t.Fatalf("isProtectedBranch(%q, %v) = %v, want %v", tt.branch, tt.patterns, got, tt.want)
+		}
+	}
+}
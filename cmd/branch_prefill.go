@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+const branchPrefillEnv = "WTX_BRANCH_PREFILL"
+const branchPrefillClipboard = "clipboard"
+const defaultBranchNamePattern = `^([A-Za-z][A-Za-z0-9]+-\d+)[\s:_-]+(.+)$`
+const defaultBranchNameTemplate = "$1-$2"
+const maxPrefillBranchLength = 60
+
+var readClipboardFn = clipboard.ReadAll
+
+var nonBranchCharsRe = regexp.MustCompile(`[^a-z0-9/._-]+`)
+var repeatedDashRe = regexp.MustCompile(`-{2,}`)
+
+// prefillBranchName suggests a new-branch name from WTX_BRANCH_PREFILL (used
+// as-is unless a pattern is configured) or, when enabled, the clipboard.
+func prefillBranchName(cfg Config) string {
+	if source := strings.TrimSpace(os.Getenv(branchPrefillEnv)); source != "" {
+		if cfg.BranchNamePattern == "" {
+			return slugifyBranchName(firstLine(source))
+		}
+		return branchNameFromText(source, cfg.BranchNamePattern, cfg.BranchNameTemplate)
+	}
+	source := ""
+	if cfg.BranchPrefill == branchPrefillClipboard {
+		text, err := readClipboardFn()
+		if err != nil {
+			logDebug("clipboard read failed", "err", err)
+			return ""
+		}
+		source = text
+	}
+	if source == "" {
+		return ""
+	}
+	return branchNameFromText(source, cfg.BranchNamePattern, cfg.BranchNameTemplate)
+}
+
+// withBranchPrefix puts prefix in front of name unless name already starts
+// with it.
+func withBranchPrefix(prefix string, name string) string {
+	name = strings.TrimSpace(name)
+	if prefix == "" || strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + name
+}
+
+// draftBranchNameFor fills a new-branch input holding nothing but the prefix
+// with a draft name; it reports false when the user typed something.
+func draftBranchNameFor(value string, prefix string, now time.Time) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value != "" && value != prefix {
+		return "", false
+	}
+	return withBranchPrefix(prefix, draftBranchName(now)), true
+}
+
+func branchNameFromText(text string, pattern string, template string) string {
+	line := firstLine(text)
+	if pattern == "" {
+		pattern = defaultBranchNamePattern
+		if template == "" {
+			template = defaultBranchNameTemplate
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		logError("invalid branch_name_pattern", "pattern", pattern, "err", err)
+		return ""
+	}
+	match := re.FindStringSubmatchIndex(line)
+	if match == nil {
+		return ""
+	}
+	var name string
+	if template != "" {
+		name = string(re.ExpandString(nil, template, line, match))
+	} else {
+		name = line[match[0]:match[1]]
+	}
+	return slugifyBranchName(name)
+}
+
+func slugifyBranchName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = nonBranchCharsRe.ReplaceAllString(name, "-")
+	name = repeatedDashRe.ReplaceAllString(name, "-")
+	if len(name) > maxPrefillBranchLength {
+		name = name[:maxPrefillBranchLength]
+	}
+	return strings.Trim(name, "-./")
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
+
+// baseRefForBranch returns the base ref of the first rule whose pattern
+// matches branch.
+func baseRefForBranch(rules []BaseRefRule, branch string) (string, bool) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return "", false
+	}
+	for _, rule := range rules {
+		baseRef := strings.TrimSpace(rule.BaseRef)
+		if baseRef == "" {
+			continue
+		}
+		if matchesAnyGlob(compileGlobs([]string{rule.Pattern}), branch) {
+			return baseRef, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestBranchNameFromText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		pattern  string
+		template string
+		want     string
+	}{
+		{name: "default ticket pattern", text: "PROJ-123 Fix login", want: "proj-123-fix-login"},
+		{name: "default pattern no match", text: "just some copied text", want: ""},
+		{name: "multi-line uses first line", text: "ABC-9: Crash on start\nstack trace", want: "abc-9-crash-on-start"},
+		{name: "custom template", text: "PROJ-7 Add retries", pattern: `^(\w+-\d+)\s+(.*)$`, template: "feat/$1", want: "feat/proj-7"},
+		{name: "invalid pattern", text: "PROJ-7 x", pattern: `(`, want: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := branchNameFromText(tc.text, tc.pattern, tc.template); got != tc.want {
+				t.Fatalf("branchNameFromText(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrefillBranchName_Sources(t *testing.T) {
+	oldRead := readClipboardFn
+	t.Cleanup(func() { readClipboardFn = oldRead })
+	readClipboardFn = func() (string, error) { return "PROJ-1 From clipboard", nil }
+
+	t.Setenv(branchPrefillEnv, "")
+	if got := prefillBranchName(Config{}); got != "" {
+		t.Fatalf("expected clipboard ignored when disabled, got %q", got)
+	}
+	if got := prefillBranchName(Config{BranchPrefill: branchPrefillClipboard}); got != "proj-1-from-clipboard" {
+		t.Fatalf("expected clipboard prefill, got %q", got)
+	}
+
+	t.Setenv(branchPrefillEnv, "My Feature")
+	if got := prefillBranchName(Config{BranchPrefill: branchPrefillClipboard}); got != "my-feature" {
+		t.Fatalf("expected env prefill to win, got %q", got)
+	}
+
+	t.Setenv(branchPrefillEnv, "")
+	readClipboardFn = func() (string, error) { return "", errors.New("no clipboard") }
+	if got := prefillBranchName(Config{BranchPrefill: branchPrefillClipboard}); got != "" {
+		t.Fatalf("expected empty prefill on clipboard error, got %q", got)
+	}
+}
+
+func TestBranchPrefix_PrefillsDraftsAndCanBeCleared(t *testing.T) {
+	if got := withBranchPrefix("alice/", "proj-12-login"); got != "alice/proj-12-login" {
+		t.Fatalf("expected prefixed prefill, got %q", got)
+	}
+	if got := withBranchPrefix("alice/", "alice/x"); got != "alice/x" {
+		t.Fatalf("expected prefix not to repeat, got %q", got)
+	}
+	now := time.Unix(1700000000, 0)
+	if got, ok := draftBranchNameFor("alice/", "alice/", now); !ok || got != "alice/draft-1700000000" {
+		t.Fatalf("expected prefixed draft, got %q %v", got, ok)
+	}
+	if _, ok := draftBranchNameFor("alice/fix", "alice/", now); ok {
+		t.Fatalf("expected typed names to be left alone")
+	}
+
+	m := newModel()
+	m.branchPrefix = "alice/"
+	m = m.startBranchNameInput()
+	if m.newBranchInput.Value() != "alice/" || m.newBranchInput.Position() != len("alice/") {
+		t.Fatalf("expected prefix with cursor after it, got %q at %d", m.newBranchInput.Value(), m.newBranchInput.Position())
+	}
+	m.newBranchInput.SetValue("alice/")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg := updated.(model).errMsg; !strings.Contains(msg, "invalid branch name") {
+		t.Fatalf("expected the bare prefix to be rejected, got %q", msg)
+	}
+}
+
+func TestBaseRefRules_PrefillFormBaseUntilEdited(t *testing.T) {
+	rules := []BaseRefRule{
+		{Pattern: "hotfix/*", BaseRef: "origin/main"},
+		{Pattern: "feature/*", BaseRef: "origin/develop"},
+		{Pattern: "*", BaseRef: "origin/next"},
+	}
+	if base, ok := baseRefForBranch(rules[:2], "docs/readme"); ok {
+		t.Fatalf("expected no rule for docs/readme, got %q", base)
+	}
+	if base, _ := baseRefForBranch(rules, "feature/x"); base != "origin/develop" {
+		t.Fatalf("expected first matching rule to win, got %q", base)
+	}
+
+	m := newModel()
+	m.mode = modeOpen
+	m.ready = true
+	m.status = WorktreeStatus{InRepo: true}
+	m.openDefaultBaseRef = "origin/main"
+	m.baseRefRules = rules[:2]
+	branch, base, fetch := "feature/x", "origin/main", true
+	m.openStage = openStageNewBranchConfig
+	m.openFormBranchPtr = &branch
+	m.openFormBaseRefPtr = &base
+	m.openFormFetchPtr = &fetch
+	m.openFormAutoBase = base
+	m.openNewBranchForm = newOpenNewBranchForm(&branch, &base, &fetch, nil)
+	m.openNewBranchForm.Init()
+
+	updatedModel, _ := m.Update(huh.NextField())
+	updated := updatedModel.(model)
+	if base != "origin/develop" || !strings.Contains(updated.openNewBranchForm.View(), "origin/develop") {
+		t.Fatalf("expected feature/* rule to prefill origin/develop, got %q", base)
+	}
+
+	branch, base = "hotfix/y", "upstream/release"
+	if updated.applyOpenBaseRefRule() || base != "upstream/release" {
+		t.Fatalf("expected an edited base to be kept, got %q", base)
+	}
+}
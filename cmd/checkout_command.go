@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 	"time"
@@ -20,6 +18,7 @@ func newCheckoutCommand() *cobra.Command {
 	var baseOverride string
 	var fetch bool
 	var noFetch bool
+	var stash string
 
 	cmd := &cobra.Command{
 		Use:     "checkout <existing_branch>",
@@ -28,12 +27,14 @@ func newCheckoutCommand() *cobra.Command {
 		Long: "Behaves like interactive branch selection.\n\n" +
 			"Without -b, <existing_branch> must already exist.\n" +
 			"With -b, the argument is treated as a new branch name and fails if it exists locally or on any remote.\n" +
-			"--from, --fetch and --no-fetch are only valid with -b.",
+			"--from, --fetch, --no-fetch and --stash are only valid with -b.\n" +
+			"--stash applies the newest stash (or the given one) in the new worktree and keeps it, which moves work started on the wrong branch.",
 		Example: strings.Join([]string{
 			"  wtx checkout feature/auth-flow",
 			"  wtx co bugfix/login-timeout",
 			"  wtx checkout -b feature/new-api",
 			"  wtx checkout -b feature/new-api --from origin/main --fetch",
+			"  git stash && wtx checkout -b feature/wip --stash",
 		}, "\n"),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 1 {
@@ -48,8 +49,8 @@ func newCheckoutCommand() *cobra.Command {
 			if fetch && noFetch {
 				return usageError(cmd, "--fetch and --no-fetch cannot be used together")
 			}
-			if !create && (strings.TrimSpace(baseOverride) != "" || fetch || noFetch) {
-				return usageError(cmd, "--from, --fetch and --no-fetch require -b")
+			if !create && (strings.TrimSpace(baseOverride) != "" || fetch || noFetch || cmd.Flags().Changed("stash")) {
+				return usageError(cmd, "--from, --fetch, --no-fetch and --stash require -b")
 			}
 
 			var fetchOverride *bool
@@ -62,7 +63,7 @@ func newCheckoutCommand() *cobra.Command {
 				fetchOverride = &v
 			}
 
-			return runCheckout(args[0], create, baseOverride, fetchOverride, os.Args)
+			return runCheckout(args[0], create, baseOverride, fetchOverride, stash, os.Args)
 		},
 	}
 
@@ -70,6 +71,8 @@ func newCheckoutCommand() *cobra.Command {
 	cmd.Flags().StringVar(&baseOverride, "from", "", "Base branch/ref for one-time branch creation (requires -b)")
 	cmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch before one-time branch creation (requires -b)")
 	cmd.Flags().BoolVar(&noFetch, "no-fetch", false, "Do not fetch before one-time branch creation (requires -b)")
+	cmd.Flags().StringVar(&stash, "stash", "", "Apply a stash (default stash@{0}) in the new worktree, keeping it (requires -b)")
+	cmd.Flags().Lookup("stash").NoOptDefVal = "stash@{0}"
 	cmd.ValidArgsFunction = checkoutBranchCompletion
 	_ = cmd.RegisterFlagCompletionFunc("from", checkoutFromCompletion)
 	return cmd
@@ -101,7 +104,7 @@ func checkoutFromCompletion(cmd *cobra.Command, _ []string, toComplete string) (
 	return completeBranchSuggestions(toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
-func runCheckout(branch string, create bool, baseOverride string, fetchOverride *bool, args []string) error {
+func runCheckout(branch string, create bool, baseOverride string, fetchOverride *bool, stash string, args []string) error {
 	branch = strings.TrimSpace(branch)
 	if branch == "" {
 		return errors.New("branch name required")
@@ -141,6 +144,11 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 		if err != nil {
 			return err
 		}
+		if cfg, err := LoadConfig(); err == nil {
+			if err := checkAgentInstalled(cfg.AgentCommand); err != nil {
+				return err
+			}
+		}
 		exists, err := branchExistsLocalOrRemote(repoRoot, gitPath, branch)
 		if err != nil {
 			return err
@@ -151,7 +159,7 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 		if !create && !exists {
 			return fmt.Errorf("branch %q does not exist locally or on known remote-tracking refs", branch)
 		}
-		baseRef, doFetch = checkoutDefaults(status)
+		baseRef, doFetch = checkoutDefaults(status, branch)
 		if create {
 			if v := strings.TrimSpace(baseOverride); v != "" {
 				baseRef = v
@@ -162,6 +170,11 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 			if err := validateCreateCheckoutBaseRef(repoRoot, gitPath, baseRef, doFetch); err != nil {
 				return err
 			}
+			if stash != "" {
+				if stash, err = mgr.ResolveStash(stash); err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	}); err != nil {
@@ -228,6 +241,17 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 		return errors.New("checkout did not resolve a worktree")
 	}
 
+	if stash != "" {
+		if err := runCheckoutStep("Applying "+stash, func() error {
+			return mgr.ApplyStash(openResult.path, stash)
+		}); err != nil {
+			if openResult.lock != nil {
+				openResult.lock.Release()
+			}
+			return err
+		}
+	}
+
 	shouldResetTabColor := true
 	defer func() {
 		if shouldResetTabColor {
@@ -248,18 +272,19 @@ func runCheckout(branch string, create bool, baseOverride string, fetchOverride
 	return nil
 }
 
-func checkoutDefaults(status WorktreeStatus) (string, bool) {
-	base := resolveNewBranchBaseRef("", status.BaseRef, status.HasRemote)
+// checkoutDefaults returns the base ref and fetch setting for creating branch
+// when no flags override them.
+func checkoutDefaults(status WorktreeStatus, branch string) (string, bool) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		cfg = Config{}
+	}
+	base := newBranchBaseRef(cfg.BaseRefRules, branch, func() string {
+		return resolveNewBranchBaseRef(cfg.NewBranchBaseRef, status.BaseRef, status.HasRemote)
+	})
 	fetch := true
-	if cfg, err := LoadConfig(); err == nil {
-		if status.HasRemote {
-			if v := strings.TrimSpace(cfg.NewBranchBaseRef); v != "" {
-				base = v
-			}
-		}
-		if cfg.NewBranchFetchFirst != nil {
-			fetch = *cfg.NewBranchFetchFirst
-		}
+	if cfg.NewBranchFetchFirst != nil {
+		fetch = *cfg.NewBranchFetchFirst
 	}
 	return base, fetch
 }
@@ -284,9 +309,10 @@ func loadOpenSlotsForCheckout(orchestrator *WorktreeOrchestrator, status Worktre
 	slots := make([]openSlotState, len(status.Worktrees))
 	for i, wt := range status.Worktrees {
 		slot := openSlotState{
-			Path:   wt.Path,
-			Branch: wt.Branch,
-			Locked: !wt.Available,
+			Path:     wt.Path,
+			Branch:   wt.Branch,
+			Locked:   !wt.Available,
+			GitState: wt.GitState,
 		}
 		if locked, err := worktreeLockedByAny(orchestrator, status.RepoRoot, wt.Path); err == nil && locked {
 			slot.Locked = true
@@ -316,14 +342,7 @@ func promptCreateWorktree(branch string) (bool, error) {
 	if branch == "" {
 		branch = "branch"
 	}
-	fmt.Fprintf(os.Stderr, "Create a new worktree for %s? [y/N]: ", branch)
-	reader := bufio.NewReader(os.Stdin)
-	line, err := reader.ReadString('\n')
-	if err != nil && !errors.Is(err, io.EOF) {
-		return false, err
-	}
-	response := strings.ToLower(strings.TrimSpace(line))
-	return response == "y" || response == "yes", nil
+	return promptYesNo(os.Stdin, os.Stderr, fmt.Sprintf("Create a new worktree for %s?", branch))
 }
 
 func validateCreateCheckoutBaseRef(repoRoot string, gitPath string, baseRef string, doFetch bool) error {
@@ -46,7 +46,7 @@ func TestCheckoutDefaults_UseConfigValues(t *testing.T) {
 		t.Fatalf("save config: %v", err)
 	}
 
-	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "origin/main", HasRemote: true})
+	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "origin/main", HasRemote: true}, "feature/a")
 	if base != "origin/develop" {
 		t.Fatalf("expected config base ref, got %q", base)
 	}
@@ -69,7 +69,7 @@ func TestCheckoutDefaults_NoRemoteAlwaysUsesMain(t *testing.T) {
 		t.Fatalf("save config: %v", err)
 	}
 
-	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "feature/local-only", HasRemote: false})
+	base, doFetch := checkoutDefaults(WorktreeStatus{BaseRef: "feature/local-only", HasRemote: false}, "feature/a")
 	if base != "main" {
 		t.Fatalf("expected main for no-remote repo, got %q", base)
 	}
@@ -77,3 +77,22 @@ func TestCheckoutDefaults_NoRemoteAlwaysUsesMain(t *testing.T) {
 		t.Fatalf("expected fetch true from config, got false")
 	}
 }
+
+func TestCheckoutDefaults_BaseRefRulesWinOverConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := SaveConfig(Config{
+		AgentCommand:          defaultAgentCommand,
+		NewBranchBaseRef:      "origin/develop",
+		MainScreenBranchLimit: defaultMainScreenBranchLimit,
+		BaseRefRules:          []BaseRefRule{{Pattern: "hotfix/*", BaseRef: "origin/main"}},
+	}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	if base, _ := checkoutDefaults(WorktreeStatus{BaseRef: "origin/main", HasRemote: true}, "hotfix/x"); base != "origin/main" {
+		t.Fatalf("expected the hotfix rule's base, got %q", base)
+	}
+	if base, _ := checkoutDefaults(WorktreeStatus{BaseRef: "origin/main", HasRemote: true}, "feature/x"); base != "origin/develop" {
+		t.Fatalf("expected the configured base without a matching rule, got %q", base)
+	}
+}
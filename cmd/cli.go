@@ -29,19 +29,38 @@ func newRootCommand(args []string) *cobra.Command {
 			}
 			return runDefault(args)
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if cmd.Flags().Changed("agent") && strings.TrimSpace(agentOverride) == "" {
+				return usageError(cmd, "--agent requires a non-empty command")
+			}
+			return nil
+		},
 	}
 	root.Flags().BoolVarP(&showVersion, "version", "v", false, "Print wtx version and exit")
+	root.PersistentFlags().BoolVar(&statusHeaderEnabled, "status-header", false, "Without tmux, show a live branch/PR/CI header above the agent")
+	root.PersistentFlags().StringVar(&agentOverride, "agent", "", "Agent command to run for this invocation instead of the configured one")
+	root.PersistentFlags().StringVar(&lockLabel, "label", "", "Label stored in worktree locks taken by this invocation, shown to teammates")
+	root.PersistentFlags().BoolVar(&lockObserve, "observe", false, "Open worktrees already in use under an observer lock that shares them with their agent")
+	root.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Skip delete and unlock confirmations")
+	root.PersistentFlags().BoolVar(&noUpdateCheck, strings.TrimPrefix(noUpdateCheckFlag, "--"), false, "Don't check GitHub for a newer wtx in the background")
 
 	root.AddCommand(
 		newCheckoutCommand(),
+		newSwitchCommand(),
 		newPRCommand(),
+		newUnlockCommand(),
+		newLocksCommand(),
+		newRepairCommand(),
+		newAdoptCommand(),
 		newConfigCommand(),
+		newDoctorCommand(),
 		newCompletionCommand(),
 		newUpdateCommand(),
 		newTmuxStatusCommand(),
 		newTmuxTitleCommand(),
 		newTmuxAgentStartCommand(),
 		newTmuxAgentExitCommand(),
+		newTabTakeoverCommand(),
 		newTmuxActionsCommand(),
 		newShellCommand(),
 		newIDECommand(),
@@ -54,17 +73,6 @@ func newRootCommand(args []string) *cobra.Command {
 	return root
 }
 
-func newConfigCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "config",
-		Short: "Open interactive configuration",
-		Args:  cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return launchConfigUIFn()
-		},
-	}
-}
-
 func newUpdateCommand() *cobra.Command {
 	var checkOnly bool
 	var quiet bool
@@ -126,6 +134,25 @@ func newTmuxAgentStartCommand() *cobra.Command {
 	return cmd
 }
 
+func newTabTakeoverCommand() *cobra.Command {
+	var worktree string
+	var fromOwner string
+	var fromPID int
+	cmd := &cobra.Command{
+		Use:    "tab-takeover",
+		Short:  "Take a worktree lock over from the wtx that opened this tab and run the agent",
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runTabTakeover(worktree, fromOwner, fromPID)
+		},
+	}
+	cmd.Flags().StringVar(&worktree, "worktree", "", "Worktree path")
+	cmd.Flags().StringVar(&fromOwner, "from-owner", "", "Owner ID of the lock being handed over")
+	cmd.Flags().IntVar(&fromPID, "from-pid", 0, "PID of the lock being handed over")
+	return cmd
+}
+
 func newTmuxAgentExitCommand() *cobra.Command {
 	var worktree string
 	var code int
@@ -206,6 +233,11 @@ func runDefault(args []string) error {
 	if err := ensureConfigReady(); err != nil {
 		return err
 	}
+	if cfg, err := LoadConfig(); err == nil {
+		if err := checkAgentInstalled(cfg.AgentCommand); err != nil {
+			return err
+		}
+	}
 
 	handled, err := ensureFreshTmuxSession(args)
 	if err != nil {
@@ -225,7 +257,11 @@ func runDefault(args []string) error {
 		}
 	}()
 
-	p := tea.NewProgram(newModel(), tea.WithMouseCellMotion())
+	opts := []tea.ProgramOption{tea.WithMouseCellMotion()}
+	if cfg, err := LoadConfig(); err == nil && cfg.RefreshOnFocus {
+		opts = append(opts, tea.WithReportFocus())
+	}
+	p := tea.NewProgram(newModel(), opts...)
 	finalModel, err := p.Run()
 	if err != nil {
 		return err
@@ -256,6 +292,9 @@ func runDefault(args []string) error {
 }
 
 func ensureConfigReady() error {
+	if _, err := wtxHomeDir(); err != nil {
+		return err
+	}
 	exists, err := ConfigExists()
 	if err != nil {
 		return err
@@ -287,6 +326,9 @@ func initializeConfig() error {
 func runVersionCommand() error {
 	cur := currentVersion()
 	fmt.Println(cur)
+	if automaticUpdateCheckDisabled(nil) {
+		return nil
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), resolveUpdateTimeout)
 	defer cancel()
@@ -226,3 +226,12 @@ func TestEnsureConfigReady_InitializesWhenMissing(t *testing.T) {
 		t.Fatalf("expected config init to run")
 	}
 }
+
+func TestRootRejectsEmptyAgentFlag(t *testing.T) {
+	t.Cleanup(func() { agentOverride = "" })
+	cmd := newRootCommand([]string{"wtx", "checkout", "foo", "--agent", "  "})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--agent requires") {
+		t.Fatalf("expected empty --agent to be rejected, got %v", err)
+	}
+}
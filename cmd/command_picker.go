@@ -38,6 +38,37 @@ func ensureAgentCommandConfigured(cfg Config) (Config, string, error) {
 	return selected, selected.AgentCommand, nil
 }
 
+var shellBuiltins = map[string]bool{".": true, "builtin": true, "cd": true, "command": true, "eval": true, "exec": true, "export": true, "source": true}
+
+// agentExecutable returns the program an agent command line starts, skipping
+// leading VAR=value assignments. It is empty when the command opens with
+// shell syntax or a builtin that PATH can't answer for.
+func agentExecutable(runCmd string) string {
+	for _, field := range strings.Fields(runCmd) {
+		if name, _, ok := strings.Cut(field, "="); ok && name != "" && !strings.ContainsAny(name, "/") {
+			continue
+		}
+		if shellBuiltins[field] || strings.ContainsAny(field, "$`'\"\\(){};&|<>*?~") {
+			return ""
+		}
+		return field
+	}
+	return ""
+}
+
+// checkAgentInstalled fails before a worktree is locked when the agent's
+// program isn't on PATH, instead of leaving a shell error in the pane.
+func checkAgentInstalled(runCmd string) error {
+	name := agentExecutable(runCmd)
+	if name == "" {
+		return nil
+	}
+	if _, err := lookPathFn(name); err != nil {
+		return fmt.Errorf("agent command '%s' not found in PATH — run `wtx config` to choose an installed agent, or pass --agent", name)
+	}
+	return nil
+}
+
 func ensureIDECommandConfigured(cfg Config) (Config, string, error) {
 	if v := strings.TrimSpace(cfg.IDECommand); v != "" {
 		return cfg, v, nil
@@ -82,13 +113,20 @@ func chooseAndSaveCommand(cfg Config, pickerType commandPickerType) (Config, err
 	if err != nil {
 		return cfg, err
 	}
+	// Persist only the picked field so .wtx.json overrides stay repo-local.
+	global, err := loadGlobalConfig()
+	if err != nil {
+		global = cfg
+	}
 	switch pickerType {
 	case commandPickerAgent:
 		cfg.AgentCommand = selected
+		global.AgentCommand = selected
 	case commandPickerIDE:
 		cfg.IDECommand = selected
+		global.IDECommand = selected
 	}
-	return cfg, SaveConfig(cfg)
+	return cfg, SaveConfig(global)
 }
 
 func detectInstalledCommands(candidates []string, lookPath func(file string) (string, error)) []string {
@@ -64,3 +64,42 @@ func TestChooseAndSaveCommand_NonInteractiveRequiresConfig(t *testing.T) {
 		t.Fatalf("expected IDE command to remain unset, got %q", cfg.IDECommand)
 	}
 }
+
+func TestAgentExecutable(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "claude", want: "claude"},
+		{in: "claude --model sonnet", want: "claude"},
+		{in: "ANTHROPIC_LOG=debug FOO=1 claude", want: "claude"},
+		{in: "/opt/bin/codex", want: "/opt/bin/codex"},
+		{in: "cd sub && claude", want: ""},
+		{in: "$HOME/bin/agent", want: ""},
+		{in: "", want: ""},
+	}
+	for _, tc := range tests {
+		if got := agentExecutable(tc.in); got != tc.want {
+			t.Fatalf("agentExecutable(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCheckAgentInstalled_ReportsMissingAgent(t *testing.T) {
+	prev := lookPathFn
+	t.Cleanup(func() { lookPathFn = prev })
+	lookPathFn = func(file string) (string, error) {
+		if file == "codex" {
+			return "/usr/bin/codex", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	if err := checkAgentInstalled("codex --full-auto"); err != nil {
+		t.Fatalf("expected installed agent to pass, got %v", err)
+	}
+	err := checkAgentInstalled("claude --resume")
+	if err == nil || !strings.Contains(err.Error(), "agent command 'claude' not found in PATH") {
+		t.Fatalf("expected missing-agent error, got %v", err)
+	}
+}
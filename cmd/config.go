@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,19 +11,194 @@ import (
 )
 
 type Config struct {
-	AgentCommand          string `json:"agent_command"`
-	NewBranchBaseRef      string `json:"new_branch_base_ref,omitempty"`
-	NewBranchFetchFirst   *bool  `json:"new_branch_fetch_first,omitempty"`
-	IDECommand            string `json:"ide_command,omitempty"`
+	// AgentCommand runs through /bin/sh -c; {branch}, {path} and {base} are
+	// replaced with the shell-quoted worktree branch, path and base ref.
+	AgentCommand        string `json:"agent_command"`
+	NewBranchBaseRef    string `json:"new_branch_base_ref,omitempty"`
+	NewBranchFetchFirst *bool  `json:"new_branch_fetch_first,omitempty"`
+	IDECommand          string `json:"ide_command,omitempty"`
+	// MainScreenBranchLimit is how many recent branches the open screen and
+	// branch suggestions list, up to maxMainScreenBranchLimit.
 	MainScreenBranchLimit int    `json:"main_screen_branch_limit,omitempty"`
+	GitPath               string `json:"git_path,omitempty"`
+	CreateTimeoutSeconds  int    `json:"create_timeout_seconds,omitempty"`
+	// PostCreateHook runs via /bin/sh in every newly created worktree.
+	PostCreateHook string `json:"post_create_hook,omitempty"`
+	// CopyOnCreate lists repo-relative paths (e.g. .env) copied from the main
+	// checkout into newly created worktrees.
+	CopyOnCreate []string `json:"copy_on_create,omitempty"`
+	// OpenBranchSort orders open-screen branches: "recent" (default) or "alpha".
+	OpenBranchSort string `json:"open_branch_sort,omitempty"`
+	// BranchPrefill set to "clipboard" prefills the new-branch name from the
+	// clipboard when it matches BranchNamePattern (default: a ticket key such
+	// as PROJ-123). BranchNameTemplate expands the pattern's capture groups.
+	BranchPrefill      string `json:"branch_prefill,omitempty"`
+	BranchNamePattern  string `json:"branch_name_pattern,omitempty"`
+	BranchNameTemplate string `json:"branch_name_template,omitempty"`
+	// AgentSubdir starts the agent in this worktree-relative directory (e.g.
+	// packages/web in a monorepo) instead of the worktree root.
+	AgentSubdir string `json:"agent_subdir,omitempty"`
+	// ExcludeBranchPatterns hides matching branches (globs, * crosses "/")
+	// from branch pickers unless they are checked out in a worktree.
+	ExcludeBranchPatterns []string `json:"exclude_branch_patterns,omitempty"`
+	// RequiredCICheckPatterns limits the CI column's pass/fail/total to
+	// checks whose name matches one of these globs. Empty counts every check.
+	RequiredCICheckPatterns []string `json:"required_ci_check_patterns,omitempty"`
+	// LockIdleReleaseSeconds, when positive, releases a tmux-owned worktree
+	// lock after this long without a keypress so teammates can take over.
+	// LockIdleWarnOnly keeps the lock and only shows the idle warning.
+	LockIdleReleaseSeconds int  `json:"lock_idle_release_seconds,omitempty"`
+	LockIdleWarnOnly       bool `json:"lock_idle_warn_only,omitempty"`
+	// RunGCAfterDelete runs `git maintenance run --auto` in the background
+	// after a worktree is deleted to prune cruft from the common .git dir.
+	RunGCAfterDelete bool `json:"run_gc_after_delete,omitempty"`
+	// ConfirmDeleteCleanWorktree set to false deletes clean worktrees on d
+	// without a prompt. Unclean, unpushed, or last worktrees still confirm.
+	ConfirmDeleteCleanWorktree *bool `json:"confirm_delete_clean_worktree,omitempty"`
+	// KeepAgentOutput waits for enter after the agent exits so its final
+	// output stays readable.
+	KeepAgentOutput bool `json:"keep_agent_output,omitempty"`
+	// DisableUpdateCheck stops the background GitHub release checks; `wtx
+	// update` still works when run explicitly.
+	DisableUpdateCheck bool `json:"disable_update_check,omitempty"`
+	// AgentPTY runs the agent behind a wtx-owned PTY outside tmux so it always
+	// sees a terminal. Opt-in because wtx then relays resizes and input.
+	AgentPTY bool `json:"agent_pty,omitempty"`
+	// FetchCommentLinks also fetches the newest comment on each unresolved
+	// review thread so v can open it. Off by default to spare the API.
+	FetchCommentLinks bool `json:"fetch_comment_links,omitempty"`
+	// CompactSelector always uses the one-line-per-worktree list; without it
+	// the list only goes compact when the terminal is too short.
+	CompactSelector bool `json:"compact_selector,omitempty"`
+	// DirenvAllowOnOpen runs `direnv allow` on the worktree's .envrc before
+	// the agent starts.
+	DirenvAllowOnOpen bool `json:"direnv_allow_on_open,omitempty"`
+	// Theme picks the TUI palette: "default", "high-contrast", or "mono".
+	Theme string `json:"theme,omitempty"`
+	// RelativePaths shows worktree paths relative to the managed <repo>.wt
+	// root; A toggles back to absolute paths.
+	RelativePaths bool `json:"relative_paths,omitempty"`
+	// AutoFetch runs a background `git fetch` alongside the GitHub poll, at
+	// most once a minute per repo, so local ahead/behind state stays fresh.
+	AutoFetch bool `json:"auto_fetch,omitempty"`
+	// FetchReviewers looks up each open PR's reviewers so w can re-request
+	// their review. Off by default to spare the API.
+	FetchReviewers bool `json:"fetch_reviewers,omitempty"`
+	// WarnProtectedBranch set to false skips the confirm before using a
+	// worktree on the default branch or one matching ProtectedBranches.
+	WarnProtectedBranch *bool `json:"warn_protected_branch,omitempty"`
+	// ProtectedBranches adds globs (* crosses "/") for branches agents
+	// shouldn't commit to directly, e.g. release/*.
+	ProtectedBranches []string `json:"protected_branches,omitempty"`
+	// BaseRemote is the remote new branches are based on (e.g. upstream on a
+	// fork). Unset or missing falls back to origin, then the first remote.
+	BaseRemote string `json:"base_remote,omitempty"`
+	// ConfirmBeforeCreate shows a summary of the branch, base, fetch, path,
+	// and agent before the open screen creates a new worktree.
+	ConfirmBeforeCreate bool `json:"confirm_before_create,omitempty"`
+	// ShowExactTimes shows last-used times as exact timestamps instead of
+	// relative ages like "3d ago".
+	ShowExactTimes bool `json:"show_exact_times,omitempty"`
+	// TmuxStatusFormat replaces the tmux-status layout, e.g. "{branch} {pr}
+	// {ci}". Placeholders: {branch} {path} {pr} {ci} {gh} {review} {agent} {idle}.
+	TmuxStatusFormat string `json:"tmux_status_format,omitempty"`
+	// ActionOrder lists action menu entries to show first: use, new_branch,
+	// existing_branch, shell, log, detached, pr. Unlisted ones follow as usual.
+	ActionOrder []string `json:"action_order,omitempty"`
+	// OpenInNewTab opens the agent in a new iTerm tab (or Terminal window) on
+	// macOS when running without tmux, leaving the current shell alone.
+	OpenInNewTab bool `json:"open_in_new_tab,omitempty"`
+	// DirtyLineCounts adds +/- line totals to the changed-file counts of
+	// unclean worktrees on the open screen; it runs git diff --shortstat.
+	DirtyLineCounts bool `json:"dirty_line_counts,omitempty"`
+	// GHEnv adds environment variables, such as GH_TOKEN or GH_HOST, to every
+	// gh subprocess. Values may reference other variables as $NAME.
+	GHEnv map[string]string `json:"gh_env,omitempty"`
+	// RefreshOnFocus asks the terminal for focus events and reloads worktree
+	// status when wtx regains focus; not every terminal reports focus.
+	RefreshOnFocus bool `json:"refresh_on_focus,omitempty"`
+	// DefaultWorktreeAction is what enter does on a worktree row: "menu" (the
+	// default), "use" or "shell". a always opens the action menu.
+	DefaultWorktreeAction string `json:"default_worktree_action,omitempty"`
+	// BranchPrefix prefills new-branch names, e.g. "alice/". Clearing it in
+	// the input creates the branch without a prefix.
+	BranchPrefix string `json:"branch_prefix,omitempty"`
+	// NotifyOnComplete announces creates that take a while: "bell" rings the
+	// terminal bell, "osc9" or "osc777" post a desktop notification.
+	NotifyOnComplete string `json:"notify_on_complete,omitempty"`
+	// CountSkippedChecks counts skipped and neutral checks as passed in the CI
+	// ratio (the default). Set it to false to leave them out of both numbers.
+	CountSkippedChecks *bool `json:"count_skipped_checks,omitempty"`
+	// BaseRefRules picks a new branch's default base by branch name, wherever
+	// wtx creates one; the first rule whose glob matches wins.
+	BaseRefRules []BaseRefRule `json:"base_ref_rules,omitempty"`
+	// EnrichOnlyWorktreeBranches fetches PR data on the open screen only for
+	// branches checked out in a worktree; other recent branches show dashes.
+	EnrichOnlyWorktreeBranches bool `json:"enrich_only_worktree_branches,omitempty"`
+	// LinkedRepos lists other repositories (paths, ~/ allowed) whose worktrees
+	// the list screen shows alongside this repo's, grouped by repo name.
+	LinkedRepos []string `json:"linked_repos,omitempty"`
+}
+
+// BaseRefRule maps a branch glob such as "hotfix/*" to the ref new branches
+// matching it are created from.
+type BaseRefRule struct {
+	Pattern string `json:"pattern"`
+	BaseRef string `json:"base_ref"`
 }
 
 const defaultAgentCommand = "claude"
 const defaultIDECommand = "code"
 const defaultMainScreenBranchLimit = 5
+
+// maxMainScreenBranchLimit keeps the open screen's branch list fast to load
+// and render.
+const maxMainScreenBranchLimit = 100
+const defaultCreateTimeoutSeconds = 120
+const openBranchSortRecent = "recent"
+const openBranchSortAlpha = "alpha"
 const configDirOverrideEnv = "WTX_CONFIG_DIR"
+const stateDirOverrideEnv = "WTX_STATE_DIR"
+
+// agentOverride is set by --agent and replaces the agent command for this
+// invocation only; it wins over both the global and per-repo config.
+var agentOverride string
 
+// LoadConfig reads the global config and overlays the .wtx.json of the repo
+// containing the working directory, if any.
 func LoadConfig() (Config, error) {
+	return loadConfigForDir("")
+}
+
+// loadConfigForDir is LoadConfig with the repo override resolved from dir.
+// Precedence: --agent, then .wtx.json, then ~/.wtx/config.json, then built-in
+// defaults.
+func loadConfigForDir(dir string) (Config, error) {
+	cfg, err := loadGlobalConfig()
+	if errors.Is(err, os.ErrNotExist) {
+		// No global config yet; the repo file and --agent still apply.
+		cfg, err = Config{}, nil
+		normalizeConfig(&cfg)
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	if root, ok := findRepoConfigRoot(dir); ok {
+		rc, found, err := readRepoConfig(root)
+		if err != nil {
+			return Config{}, err
+		}
+		if found {
+			applyRepoConfig(&cfg, rc)
+		}
+	}
+	if v := strings.TrimSpace(agentOverride); v != "" {
+		cfg.AgentCommand = v
+	}
+	return cfg, nil
+}
+
+func loadGlobalConfig() (Config, error) {
 	path, err := configPath()
 	if err != nil {
 		return Config{}, err
@@ -35,13 +211,38 @@ func LoadConfig() (Config, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return Config{}, err
 	}
+	normalizeConfig(&cfg)
+	return cfg, nil
+}
+
+// normalizeConfig trims string settings and fills in defaults for unset or
+// out-of-range limits.
+func normalizeConfig(cfg *Config) {
 	cfg.AgentCommand = strings.TrimSpace(cfg.AgentCommand)
 	cfg.IDECommand = strings.TrimSpace(cfg.IDECommand)
 	cfg.NewBranchBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
+	cfg.GitPath = strings.TrimSpace(cfg.GitPath)
+	cfg.PostCreateHook = strings.TrimSpace(cfg.PostCreateHook)
+	cfg.OpenBranchSort = normalizeOpenBranchSort(cfg.OpenBranchSort)
+	cfg.BranchPrefill = strings.ToLower(strings.TrimSpace(cfg.BranchPrefill))
+	cfg.BranchNamePattern = strings.TrimSpace(cfg.BranchNamePattern)
+	cfg.BranchPrefix = strings.TrimSpace(cfg.BranchPrefix)
+	cfg.BranchNameTemplate = strings.TrimSpace(cfg.BranchNameTemplate)
+	cfg.AgentSubdir = strings.TrimSpace(cfg.AgentSubdir)
 	if cfg.MainScreenBranchLimit <= 0 {
 		cfg.MainScreenBranchLimit = defaultMainScreenBranchLimit
 	}
-	return cfg, nil
+	cfg.MainScreenBranchLimit = min(cfg.MainScreenBranchLimit, maxMainScreenBranchLimit)
+	if cfg.CreateTimeoutSeconds <= 0 {
+		cfg.CreateTimeoutSeconds = defaultCreateTimeoutSeconds
+	}
+}
+
+func normalizeOpenBranchSort(value string) string {
+	if strings.EqualFold(strings.TrimSpace(value), openBranchSortAlpha) {
+		return openBranchSortAlpha
+	}
+	return openBranchSortRecent
 }
 
 func normalizeMainScreenBranchLimit(input string) (int, error) {
@@ -53,9 +254,19 @@ func normalizeMainScreenBranchLimit(input string) (int, error) {
 	if err != nil || limit <= 0 {
 		return 0, errors.New("main screen branch count must be a positive number")
 	}
+	if limit > maxMainScreenBranchLimit {
+		return 0, fmt.Errorf("main screen branch count must be at most %d", maxMainScreenBranchLimit)
+	}
 	return limit, nil
 }
 
+func configuredMainScreenBranchLimit() int {
+	if cfg, err := LoadConfig(); err == nil && cfg.MainScreenBranchLimit > 0 {
+		return cfg.MainScreenBranchLimit
+	}
+	return defaultMainScreenBranchLimit
+}
+
 func ConfigExists() (bool, error) {
 	path, err := configPath()
 	if err != nil {
@@ -83,6 +294,23 @@ func SaveConfig(cfg Config) error {
 	if err != nil {
 		return err
 	}
+	if existing, err := os.ReadFile(path); err == nil {
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(existing, &raw) == nil {
+			if unknown := unknownConfigKeys(raw); len(unknown) > 0 {
+				var merged map[string]json.RawMessage
+				if err := json.Unmarshal(data, &merged); err != nil {
+					return err
+				}
+				for _, key := range unknown {
+					merged[key] = raw[key]
+				}
+				if data, err = json.MarshalIndent(merged, "", "  "); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	data = append(data, '\n')
 	return os.WriteFile(path, data, 0o644)
 }
@@ -91,9 +319,9 @@ func configPath() (string, error) {
 	if dir := strings.TrimSpace(os.Getenv(configDirOverrideEnv)); dir != "" {
 		return filepath.Join(dir, "config.json"), nil
 	}
-	home := os.Getenv("HOME")
-	if strings.TrimSpace(home) == "" {
-		return "", errors.New("HOME not set")
+	dir, err := wtxHomeDir()
+	if err != nil {
+		return "", err
 	}
-	return filepath.Join(home, ".wtx", "config.json"), nil
+	return filepath.Join(dir, "config.json"), nil
 }
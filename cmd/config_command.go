@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Open interactive configuration",
+		Long:  "Without a subcommand, opens the interactive configuration. Use get, set, and path to script ~/.wtx/config.json, and export/import to copy it to another machine.",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return launchConfigUIFn()
+		},
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:       "get <key>",
+			Short:     "Print a config value",
+			Args:      cobra.ExactArgs(1),
+			ValidArgs: configKeys(),
+			RunE: func(_ *cobra.Command, cmdArgs []string) error {
+				return runConfigGet(os.Stdout, cmdArgs[0])
+			},
+		},
+		&cobra.Command{
+			Use:       "set <key> <value>",
+			Short:     "Set a config value",
+			Long:      "Sets a config value. Lists take a comma-separated value; an empty value clears the key.",
+			Args:      cobra.ExactArgs(2),
+			ValidArgs: configKeys(),
+			RunE: func(_ *cobra.Command, cmdArgs []string) error {
+				return runConfigSet(cmdArgs[0], cmdArgs[1])
+			},
+		},
+		&cobra.Command{
+			Use:   "export",
+			Short: "Print the config as JSON",
+			Args:  cobra.NoArgs,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				return runConfigExport(os.Stdout)
+			},
+		},
+		&cobra.Command{
+			Use:   "import <file>",
+			Short: "Validate a config exported elsewhere and save it",
+			Long:  "Replaces ~/.wtx/config.json with file (- reads stdin), creating the config directory when needed.",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, cmdArgs []string) error {
+				return runConfigImport(os.Stdin, cmdArgs[0])
+			},
+		},
+		&cobra.Command{
+			Use:   "path",
+			Short: "Print the config file path",
+			Args:  cobra.NoArgs,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				path, err := configPath()
+				if err != nil {
+					return err
+				}
+				fmt.Println(path)
+				return nil
+			},
+		},
+	)
+	return cmd
+}
+
+// configKeys lists the JSON keys of Config in declaration order.
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key := configFieldKey(t.Field(i)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func configFieldKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func configFieldByKey(cfg *Config, key string) (reflect.Value, error) {
+	key = strings.TrimSpace(key)
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if configFieldKey(v.Type().Field(i)) == key {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %q (valid keys: %s)", key, strings.Join(configKeys(), ", "))
+}
+
+func runConfigGet(w io.Writer, key string) error {
+	cfg, err := loadGlobalConfig()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			cfg = Config{}
+		} else {
+			return err
+		}
+	}
+	field, err := configFieldByKey(&cfg, key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, formatConfigValue(field))
+	return nil
+}
+
+func runConfigSet(key string, value string) error {
+	cfg, err := loadGlobalConfig()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	field, err := configFieldByKey(&cfg, key)
+	if err != nil {
+		return err
+	}
+	if err := setConfigValue(field, value); err != nil {
+		return fmt.Errorf("%s: %w", strings.TrimSpace(key), err)
+	}
+	return SaveConfig(cfg)
+}
+
+// runConfigExport prints the global config with defaults filled in; repo
+// .wtx.json overrides stay with the repo.
+func runConfigExport(w io.Writer) error {
+	cfg, err := loadGlobalConfig()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errors.New("no config to export yet; run wtx config first")
+		}
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+func runConfigImport(stdin io.Reader, path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	cfg, err := parseImportedConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return SaveConfig(cfg)
+}
+
+// parseImportedConfig rejects keys this version doesn't know and values wtx
+// would refuse at run time, so a bad file never replaces a working config.
+func parseImportedConfig(data []byte) (Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	if strings.TrimSpace(cfg.AgentCommand) == "" {
+		return Config{}, errors.New("agent_command must not be empty")
+	}
+	for _, entry := range cfg.CopyOnCreate {
+		if err := validateCopyOnCreateEntry(entry); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := validateAgentSubdir(cfg.AgentSubdir); err != nil {
+		return Config{}, err
+	}
+	for key := range cfg.GHEnv {
+		if err := validateGHEnvKey(key); err != nil {
+			return Config{}, err
+		}
+	}
+	if cfg.BranchNamePattern != "" {
+		if _, err := regexp.Compile(cfg.BranchNamePattern); err != nil {
+			return Config{}, fmt.Errorf("branch_name_pattern: %w", err)
+		}
+	}
+	for i, rule := range cfg.BaseRefRules {
+		if strings.TrimSpace(rule.Pattern) == "" || strings.TrimSpace(rule.BaseRef) == "" {
+			return Config{}, fmt.Errorf("base_ref_rules[%d]: pattern and base_ref are required", i)
+		}
+	}
+	return cfg, nil
+}
+
+func formatConfigValue(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.Pointer:
+		if field.IsNil() {
+			return ""
+		}
+		return formatConfigValue(field.Elem())
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			data, _ := json.Marshal(field.Interface())
+			return string(data)
+		}
+		parts := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			parts = append(parts, field.Index(i).String())
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+func setConfigValue(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := parseConfigBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("expected a non-negative number, got %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Pointer:
+		if value == "" {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := setConfigValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", field.Type())
+		}
+		var items []string
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, part)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+	return nil
+}
+
+func parseConfigBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "off", "0":
+		return false, nil
+	case "true", "yes", "on", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("expected true or false, got %q", value)
+}
+
+// unknownConfigKeys returns the entries of raw that Config doesn't declare, so
+// saving keeps settings written by newer wtx versions or by hand.
+func unknownConfigKeys(raw map[string]json.RawMessage) []string {
+	known := make(map[string]bool)
+	for _, key := range configKeys() {
+		known[key] = true
+	}
+	var unknown []string
+	for key := range raw {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
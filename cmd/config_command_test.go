@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigSet_ValidatesTypesAndPreservesUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(configDirOverrideEnv, dir)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"agent_command":"claude","future_setting":{"a":1}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if err := runConfigSet("new_branch_fetch_first", "false"); err != nil {
+		t.Fatalf("set bool: %v", err)
+	}
+	if err := runConfigSet("copy_on_create", ".env, .env.local"); err != nil {
+		t.Fatalf("set list: %v", err)
+	}
+	if err := runConfigSet("create_timeout_seconds", "soon"); err == nil || !strings.Contains(err.Error(), "create_timeout_seconds") {
+		t.Fatalf("expected int validation error, got %v", err)
+	}
+	if err := runConfigSet("no_such_key", "x"); err == nil || !strings.Contains(err.Error(), "unknown config key") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw["future_setting"]); err != nil || compact.String() != `{"a":1}` {
+		t.Fatalf("expected unknown key preserved, got %s", data)
+	}
+
+	for key, want := range map[string]string{
+		"agent_command":          "claude",
+		"new_branch_fetch_first": "false",
+		"copy_on_create":         ".env,.env.local",
+	} {
+		var out bytes.Buffer
+		if err := runConfigGet(&out, key); err != nil {
+			t.Fatalf("get %s: %v", key, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != want {
+			t.Fatalf("get %s: expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestConfigExportImport_RoundTripsIntoMissingDir(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	fetch := false
+	want := Config{
+		AgentCommand:        "codex --context {branch}",
+		NewBranchFetchFirst: &fetch,
+		CopyOnCreate:        []string{".env"},
+		GHEnv:               map[string]string{"GH_HOST": "ghe.example.com"},
+		BranchPrefix:        "alice/",
+		ActionOrder:         []string{"shell", "use"},
+		RefreshOnFocus:      true,
+	}
+	if err := SaveConfig(want); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	want, err := loadGlobalConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var exported bytes.Buffer
+	if err := runConfigExport(&exported); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	t.Setenv(configDirOverrideEnv, filepath.Join(t.TempDir(), "new", "machine"))
+	if err := runConfigImport(bytes.NewReader(exported.Bytes()), "-"); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	got, err := loadGlobalConfig()
+	if err != nil {
+		t.Fatalf("load imported: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("config did not round-trip\n got: %+v\nwant: %+v", got, want)
+	}
+
+	for _, bad := range []string{`{"agent_command":"claude","no_such_key":1}`, `{"agent_command":""}`, `{"agent_command":"claude","copy_on_create":["../x"]}`} {
+		if err := runConfigImport(strings.NewReader(bad), "-"); err == nil {
+			t.Fatalf("expected %s to be rejected", bad)
+		}
+	}
+	if after, _ := loadGlobalConfig(); !reflect.DeepEqual(after, want) {
+		t.Fatalf("expected rejected imports to leave the config alone")
+	}
+}
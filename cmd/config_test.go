@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +37,217 @@ func TestConfigPath_UsesHomeByDefault(t *testing.T) {
 		t.Fatalf("expected %q, got %q", want, path)
 	}
 }
+
+func TestStateDir_UsesOverrideEnvAndReportsMissingHome(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv(configDirOverrideEnv, "")
+	t.Setenv(stateDirOverrideEnv, state)
+	t.Setenv("HOME", "")
+
+	path, err := configPath()
+	if err != nil {
+		t.Fatalf("configPath with state dir: %v", err)
+	}
+	if want := filepath.Join(state, "config.json"); path != want {
+		t.Fatalf("expected %q, got %q", want, path)
+	}
+	if dir, err := locksDir(); err != nil || dir != filepath.Join(state, "locks") {
+		t.Fatalf("expected locks under state dir, got %q (%v)", dir, err)
+	}
+
+	t.Setenv(stateDirOverrideEnv, "")
+	err = ensureConfigReady()
+	if err == nil || !strings.Contains(err.Error(), "WTX_STATE_DIR") {
+		t.Fatalf("expected state dir error, got %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadConfigForDir_RepoFileOverridesGlobal(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if err := SaveConfig(Config{AgentCommand: "claude", NewBranchBaseRef: "origin/main", IDECommand: "code"}); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, repoConfigFileName), `{"agent_command":"codex","copy_on_create":[".env"]}`)
+	sub := filepath.Join(repo, "pkg", "inner")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("mkdir sub: %v", err)
+	}
+
+	cfg, err := loadConfigForDir(sub)
+	if err != nil {
+		t.Fatalf("loadConfigForDir: %v", err)
+	}
+	if cfg.AgentCommand != "codex" {
+		t.Fatalf("expected repo agent command, got %q", cfg.AgentCommand)
+	}
+	if cfg.NewBranchBaseRef != "origin/main" || cfg.IDECommand != "code" {
+		t.Fatalf("expected global fields to be kept, got %+v", cfg)
+	}
+	if len(cfg.CopyOnCreate) != 1 || cfg.CopyOnCreate[0] != ".env" {
+		t.Fatalf("expected copy_on_create from repo, got %#v", cfg.CopyOnCreate)
+	}
+}
+
+func TestLoadConfigForDir_AgentFlagOverridesRepoFile(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if err := SaveConfig(Config{AgentCommand: "claude"}); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, repoConfigFileName), `{"agent_command":"codex"}`)
+	prev := agentOverride
+	agentOverride = " gemini --yolo "
+	t.Cleanup(func() { agentOverride = prev })
+
+	cfg, err := loadConfigForDir(repo)
+	if err != nil {
+		t.Fatalf("loadConfigForDir: %v", err)
+	}
+	if cfg.AgentCommand != "gemini --yolo" {
+		t.Fatalf("expected --agent to win, got %q", cfg.AgentCommand)
+	}
+	global, err := loadGlobalConfig()
+	if err != nil {
+		t.Fatalf("loadGlobalConfig: %v", err)
+	}
+	if global.AgentCommand != "claude" {
+		t.Fatalf("expected --agent not to leak into global config, got %q", global.AgentCommand)
+	}
+}
+
+func TestLoadConfigForDir_AppliesRepoFileWithoutGlobalConfig(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, repoConfigFileName), `{"agent_command":"codex"}`)
+
+	cfg, err := loadConfigForDir(repo)
+	if err != nil {
+		t.Fatalf("loadConfigForDir: %v", err)
+	}
+	if cfg.AgentCommand != "codex" || cfg.MainScreenBranchLimit != defaultMainScreenBranchLimit {
+		t.Fatalf("expected defaults plus repo file, got agent=%q limit=%d", cfg.AgentCommand, cfg.MainScreenBranchLimit)
+	}
+	if _, err := loadGlobalConfig(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the global config to stay absent, got %v", err)
+	}
+}
+
+func TestReadRepoConfig_Validates(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty agent", content: `{"agent_command":"  "}`, wantErr: "agent_command"},
+		{name: "absolute copy", content: `{"copy_on_create":["/etc/passwd"]}`, wantErr: "relative"},
+		{name: "escaping copy", content: `{"copy_on_create":["../secrets"]}`, wantErr: "escapes"},
+		{name: "escaping agent subdir", content: `{"agent_subdir":"../web"}`, wantErr: "agent_subdir"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := t.TempDir()
+			writeTestFile(t, filepath.Join(repo, repoConfigFileName), tc.content)
+			_, _, err := readRepoConfig(repo)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReadRepoConfig_IgnoresUnknownKeys(t *testing.T) {
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, repoConfigFileName), `{"agent_command":"codex","some_future_key":true}`)
+	rc, found, err := readRepoConfig(repo)
+	if err != nil || !found {
+		t.Fatalf("expected repo config despite unknown key, got found=%v err=%v", found, err)
+	}
+	if rc.AgentCommand == nil || *rc.AgentCommand != "codex" {
+		t.Fatalf("expected known fields applied, got %#v", rc.AgentCommand)
+	}
+	if got := unknownRepoConfigKeys([]byte(`{"gh_env":{},"zeta":1,"alpha":2}`)); strings.Join(got, ",") != "alpha,zeta" {
+		t.Fatalf("expected unknown keys alpha,zeta, got %v", got)
+	}
+}
+
+func TestCopyIntoWorktree_CopiesMissingFilesOnly(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "config", "a.env"), "A=1")
+	writeTestFile(t, filepath.Join(src, "config", "b.env"), "B=1")
+	writeTestFile(t, filepath.Join(dst, "config", "b.env"), "B=keep")
+
+	if err := copyIntoWorktree(filepath.Join(src, "config"), filepath.Join(dst, "config")); err != nil {
+		t.Fatalf("copyIntoWorktree: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "config", "a.env")); string(data) != "A=1" {
+		t.Fatalf("expected a.env copied, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "config", "b.env")); string(data) != "B=keep" {
+		t.Fatalf("expected existing b.env untouched, got %q", data)
+	}
+	if err := copyIntoWorktree(filepath.Join(src, "missing"), filepath.Join(dst, "missing")); err != nil {
+		t.Fatalf("expected missing source to be skipped, got %v", err)
+	}
+}
+
+func TestNormalizeThemeName(t *testing.T) {
+	tests := map[string]string{
+		"":              defaultThemeName,
+		"High-Contrast": "high-contrast",
+		" mono ":        "mono",
+		"solarized":     defaultThemeName,
+	}
+	for input, want := range tests {
+		if got := normalizeThemeName(input); got != want {
+			t.Fatalf("normalizeThemeName(%q) = %q, want %q", input, got, want)
+		}
+	}
+	for name, theme := range uiThemes {
+		if theme.Accent == nil {
+			t.Fatalf("theme %q has no accent color", name)
+		}
+	}
+}
+
+func TestMainScreenBranchLimit_Bounded(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if got := configuredMainScreenBranchLimit(); got != defaultMainScreenBranchLimit {
+		t.Fatalf("expected default limit without config, got %d", got)
+	}
+	if err := SaveConfig(Config{AgentCommand: "claude", MainScreenBranchLimit: 40}); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	if got := configuredMainScreenBranchLimit(); got != 40 {
+		t.Fatalf("expected configured limit 40, got %d", got)
+	}
+	if err := SaveConfig(Config{AgentCommand: "claude", MainScreenBranchLimit: 5000}); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	if got := configuredMainScreenBranchLimit(); got != maxMainScreenBranchLimit {
+		t.Fatalf("expected limit clamped to %d, got %d", maxMainScreenBranchLimit, got)
+	}
+	if _, err := normalizeMainScreenBranchLimit("5000"); err == nil {
+		t.Fatalf("expected an over-limit count to be rejected")
+	}
+}
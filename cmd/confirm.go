@@ -1,10 +1,20 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// assumeYes is bound to the global --yes flag and auto-accepts destructive
+// confirmations in both the CLI prompts and the TUI.
+var assumeYes bool
+
 type confirmKind int
 
 const (
@@ -15,14 +25,21 @@ const (
 	confirmUnlock
 	confirmOpenDebugDelete
 	confirmOpenDebugUnlock
+	confirmOpenDebugArchive
+	confirmResetRemote
 	confirmOpenPickLocked
 	confirmOpenBaseDefault
 	confirmOpenFetchDefault
+	confirmAttachAgent
+	confirmUseProtected
+	confirmCreateWorktree
+	confirmOpenDebugDiscard
 )
 
 func wtxHuhTheme() *huh.Theme {
 	t := *huh.ThemeCharm()
-	t.Focused.FocusedButton = t.Focused.FocusedButton.Background(lipgloss.Color("#7D56F4"))
+	theme := currentTheme()
+	t.Focused.FocusedButton = t.Focused.FocusedButton.Background(theme.Accent).Reverse(theme.ReverseSelection)
 	t.Focused.Next = t.Focused.FocusedButton
 	// Keep placeholder text fully readable on first paint by avoiding a block-style cursor.
 	t.Focused.TextInput.Cursor = lipgloss.NewStyle()
@@ -30,6 +47,33 @@ func wtxHuhTheme() *huh.Theme {
 	return &t
 }
 
+// confirmSkippedByYes reports whether kind is a delete/unlock confirmation that
+// --yes should accept without showing the form.
+func confirmSkippedByYes(kind confirmKind) bool {
+	if !assumeYes {
+		return false
+	}
+	switch kind {
+	case confirmDelete, confirmUnlock, confirmOpenDebugDelete, confirmOpenDebugUnlock, confirmOpenDebugArchive, confirmOpenPickLocked, confirmResetRemote:
+		return true
+	default:
+		return false
+	}
+}
+
+func promptYesNo(r io.Reader, w io.Writer, question string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	fmt.Fprintf(w, "%s [y/N]: ", question)
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	response := strings.ToLower(strings.TrimSpace(line))
+	return response == "y" || response == "yes", nil
+}
+
 func newConfirmForm(title string, description string, result *bool) *huh.Form {
 	confirm := huh.NewConfirm().
 		Key(confirmFieldKey).
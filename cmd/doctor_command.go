@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment wtx runs in",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runDoctor(os.Stdout)
+		},
+	}
+}
+
+func runDoctor(w io.Writer) error {
+	gitPath, err := requireGitPath()
+	if err != nil {
+		return err
+	}
+	v, err := gitVersion(gitPath)
+	if err != nil {
+		return fmt.Errorf("git %s: %w", gitPath, err)
+	}
+	fmt.Fprintf(w, "git: %s (%s)\n", gitPath, formatGitVersion(v))
+	if warning := gitVersionWarning(v); warning != "" {
+		fmt.Fprintln(w, "warning: "+warning)
+	}
+	return nil
+}
+
+// gitVersionWarning explains what an old git costs; empty when v is recent
+// enough.
+func gitVersionWarning(v parsedVersion) string {
+	if compareReleaseVersions(v, minPathFormatGit) >= 0 {
+		return ""
+	}
+	return fmt.Sprintf("git %s predates %s and lacks rev-parse --path-format; wtx resolves the common git dir itself, but upgrading is recommended", formatGitVersion(v), formatGitVersion(minPathFormatGit))
+}
+
+func formatGitVersion(v parsedVersion) string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   parsedVersion
+		ok     bool
+	}{
+		{output: "git version 2.39.3 (Apple Git-145)\n", want: parsedVersion{Major: 2, Minor: 39, Patch: 3}, ok: true},
+		{output: "git version 2.30.1.windows.1", want: parsedVersion{Major: 2, Minor: 30, Patch: 1}, ok: true},
+		{output: "git version 2.45", want: parsedVersion{Major: 2, Minor: 45}, ok: true},
+		{output: "hub version 2.14.2", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGitVersion(tt.output)
+		if ok != tt.ok || got != tt.want {
+			t.Fatalf("parseGitVersion(%q) = %+v, %v; want %+v, %v", tt.output, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGitVersionWarning(t *testing.T) {
+	if got := gitVersionWarning(parsedVersion{Major: 2, Minor: 31}); got != "" {
+		t.Fatalf("expected no warning for 2.31, got %q", got)
+	}
+	if got := gitVersionWarning(parsedVersion{Major: 2, Minor: 25, Patch: 1}); !strings.Contains(got, "2.25.1") {
+		t.Fatalf("expected warning naming the old version, got %q", got)
+	}
+}
+
+func TestGitCommonDir_ResolvesWithoutPathFormat(t *testing.T) {
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q")
+	sub := filepath.Join(repo, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	fakeGit := filepath.Join(t.TempDir(), "git")
+	// Report an old version and reject --path-format like git < 2.31 does.
+	script := "#!/bin/sh\ncase \"$*\" in\n--version) echo 'git version 2.25.1' ;;\n*--path-format*) exit 129 ;;\n*) exec git \"$@\" ;;\nesac\n"
+	if err := os.WriteFile(fakeGit, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake git: %v", err)
+	}
+	got, err := gitCommonDir(sub, fakeGit)
+	if err != nil {
+		t.Fatalf("gitCommonDir: %v", err)
+	}
+	if !filepath.IsAbs(got) || !sameRealPath(got, filepath.Join(repo, ".git")) {
+		t.Fatalf("expected absolute common dir %s, got %q", filepath.Join(repo, ".git"), got)
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventsFileEnv opts in to an append-only JSONL log of wtx actions. Events
+// carry only the repository name and branch, never paths or commands.
+const eventsFileEnv = "WTX_EVENTS_FILE"
+
+const (
+	eventWorktreeOpened  = "worktree_opened"
+	eventWorktreeCreated = "worktree_created"
+	eventWorktreeDeleted = "worktree_deleted"
+	eventWorktreeUnlock  = "worktree_unlocked"
+	eventAgentLaunched   = "agent_launched"
+	eventAgentExited     = "agent_exited"
+)
+
+var eventsMu sync.Mutex
+
+type wtxEvent struct {
+	Time       string `json:"time"`
+	Event      string `json:"event"`
+	Repo       string `json:"repo,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+}
+
+// recordEvent appends event to $WTX_EVENTS_FILE. dir is any path inside the
+// repository; only its name is written. Failures are logged and dropped.
+func recordEvent(dir string, event wtxEvent) {
+	path := strings.TrimSpace(os.Getenv(eventsFileEnv))
+	if path == "" {
+		return
+	}
+	if event.Time == "" {
+		event.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	if event.Repo == "" {
+		event.Repo = eventRepoName(dir)
+	}
+	event.Branch = strings.TrimSpace(event.Branch)
+	if err := appendEvent(path, event); err != nil {
+		logDebug("recording event failed", "event", event.Event, "err", err)
+	}
+}
+
+func appendEvent(path string, event wtxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func eventRepoName(dir string) string {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return ""
+	}
+	if repoRoot, err := repoRootForDir(dir, ""); err == nil && strings.TrimSpace(repoRoot) != "" {
+		dir = repoRoot
+	}
+	return filepath.Base(dir)
+}
+
+// recordAgentLaunched logs an agent start and returns the start time for the
+// matching recordAgentExited.
+func recordAgentLaunched(worktreePath string, branch string) time.Time {
+	recordEvent(worktreePath, wtxEvent{Event: eventAgentLaunched, Branch: branch})
+	return time.Now()
+}
+
+func recordAgentExited(worktreePath string, branch string, started time.Time, exitCode int) {
+	event := wtxEvent{Event: eventAgentExited, Branch: branch, ExitCode: &exitCode}
+	if !started.IsZero() {
+		event.DurationMS = time.Since(started).Milliseconds()
+	}
+	recordEvent(worktreePath, event)
+}
+
+// agentExitCode maps a cmd.Wait error to the process exit code.
+func agentExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
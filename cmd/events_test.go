@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordEvent_AppendsRepoNameAndBranchOnly(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "myrepo")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	runTestGit(t, repo, "init", "-q")
+	path := filepath.Join(t.TempDir(), "events", "wtx.jsonl")
+	t.Setenv(eventsFileEnv, path)
+
+	recordEvent(repo, wtxEvent{Event: eventWorktreeCreated, Branch: "feature/x"})
+	recordAgentExited(repo, "feature/x", time.Now().Add(-2*time.Second), 3)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if strings.Contains(string(data), repo) {
+		t.Fatalf("expected no paths in events, got %q", data)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 events, got %d: %q", len(lines), data)
+	}
+	var created, exited wtxEvent
+	if err := json.Unmarshal([]byte(lines[0]), &created); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &exited); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if created.Event != eventWorktreeCreated || created.Repo != "myrepo" || created.Branch != "feature/x" || created.ExitCode != nil {
+		t.Fatalf("unexpected created event %+v", created)
+	}
+	if exited.Event != eventAgentExited || exited.ExitCode == nil || *exited.ExitCode != 3 || exited.DurationMS < 2000 {
+		t.Fatalf("unexpected exited event %+v", exited)
+	}
+}
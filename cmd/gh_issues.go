@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	ghIssueListTimeout   = 10 * time.Second
+	ghIssueListLimit     = 100
+	maxIssueBranchLength = 60
+)
+
+type ghIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+type openIssuesLoadedMsg struct {
+	issues []ghIssue
+	err    error
+}
+
+func loadOpenIssuesCmd(repoRoot string) tea.Cmd {
+	return func() tea.Msg {
+		issues, err := listOpenIssues(repoRoot)
+		return openIssuesLoadedMsg{issues: issues, err: err}
+	}
+}
+
+func listOpenIssues(repoRoot string) ([]ghIssue, error) {
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return nil, errors.New("gh not installed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghIssueListTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ghPath, "issue", "list", "--state", "open", "--limit", fmt.Sprint(ghIssueListLimit), "--json", "number,title")
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return nil, fmt.Errorf("gh issue list: %s", msg)
+			}
+		}
+		return nil, fmt.Errorf("gh issue list: %w", err)
+	}
+	var issues []ghIssue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func filterIssues(issues []ghIssue, query string) []ghIssue {
+	q := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(query), "#")))
+	if q == "" {
+		return issues
+	}
+	out := make([]ghIssue, 0, len(issues))
+	for _, issue := range issues {
+		if strings.Contains(fmt.Sprint(issue.Number), q) || strings.Contains(strings.ToLower(issue.Title), q) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// issueBranchName builds 123-issue-slug from an issue, keeping the result a
+// valid git branch name.
+func issueBranchName(issue ghIssue) (string, error) {
+	if issue.Number <= 0 {
+		return "", errors.New("issue number required")
+	}
+	name := fmt.Sprintf("%d", issue.Number)
+	if slug := slugifyBranchName(strings.ReplaceAll(issue.Title, "/", "-")); slug != "" {
+		name += "-" + slug
+	}
+	if len(name) > maxIssueBranchLength {
+		name = strings.TrimRight(name[:maxIssueBranchLength], "-.")
+	}
+	if err := validateBranchName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func validateBranchName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("branch name required")
+	}
+	if out, err := exec.Command(gitBinary(), "check-ref-format", "--branch", name).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("invalid branch name %q: %s", name, msg)
+		}
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
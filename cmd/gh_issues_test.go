@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIssueBranchName(t *testing.T) {
+	tests := []struct {
+		name    string
+		issue   ghIssue
+		want    string
+		wantErr bool
+	}{
+		{name: "slugged title", issue: ghIssue{Number: 123, Title: "Fix login: crash on empty password!"}, want: "123-fix-login-crash-on-empty-password"},
+		{name: "slashes flattened", issue: ghIssue{Number: 7, Title: "api/v2 timeouts"}, want: "7-api-v2-timeouts"},
+		{name: "no usable title", issue: ghIssue{Number: 9, Title: "🔥🔥"}, want: "9"},
+		{name: "dot dot rejected", issue: ghIssue{Number: 4, Title: "a..b"}, wantErr: true},
+		{name: "long title truncated", issue: ghIssue{Number: 1, Title: strings.Repeat("word ", 30)}, want: strings.TrimRight(("1-" + strings.Repeat("word-", 30))[:maxIssueBranchLength], "-")},
+		{name: "missing number", issue: ghIssue{Title: "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := issueBranchName(tt.issue)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("issueBranchName: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpenPickIssueFillsBranchName(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	m.ready = true
+	m.status = WorktreeStatus{InRepo: true}
+	branch, base, fetch := "", "origin/main", true
+	m.openStage = openStagePickIssue
+	m.openFormBranchPtr = &branch
+	m.openFormBaseRefPtr = &base
+	m.openFormFetchPtr = &fetch
+
+	updatedModel, _ := m.Update(openIssuesLoadedMsg{issues: []ghIssue{{Number: 12, Title: "Add dark mode"}, {Number: 34, Title: "Fix crash"}}})
+	updated := updatedModel.(model)
+	if len(updated.openIssuesFiltered) != 2 {
+		t.Fatalf("expected issues to load, got %d", len(updated.openIssuesFiltered))
+	}
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updatedModel, _ = updatedModel.(model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated = updatedModel.(model)
+	if branch != "34-fix-crash" {
+		t.Fatalf("expected branch from issue, got %q", branch)
+	}
+	if updated.openStage != openStageNewBranchConfig || updated.openNewBranchForm == nil {
+		t.Fatalf("expected to return to the new-branch form")
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +33,7 @@ const (
 	fullPRListFields       = "number,url,headRefName,baseRefName,title,isDraft,state,mergeStateStatus,updatedAt,mergedAt,reviewDecision,statusCheckRollup"
 	fallbackPRListFields   = "number,url,headRefName,baseRefName,title,isDraft,state,mergeStateStatus,updatedAt,mergedAt,reviewDecision"
 	maxBranchFetchParallel = 6
+	ghPRSearchQueryMax     = 256
 )
 
 type PRData struct {
@@ -51,9 +54,16 @@ type PRData struct {
 	CICompleted         int
 	CITotal             int
 	CIFailingNames      string
-	CommentsRequired    bool
-	CommentsKnown       bool
-	BaseStatus          string
+	CIFailingURL        string
+	// LatestCommentURL links the newest comment on an unresolved review
+	// thread; only fetched with fetch_comment_links.
+	LatestCommentURL string
+	// Reviewers can be asked to review again; only fetched with
+	// fetch_reviewers.
+	Reviewers        []string
+	CommentsRequired bool
+	CommentsKnown    bool
+	BaseStatus       string
 }
 
 type GHManager struct {
@@ -88,6 +98,10 @@ type ghCheck struct {
 	Status     string `json:"status"`
 	Name       string `json:"name"`
 	Context    string `json:"context"`
+	// DetailsURL is set on check runs, TargetURL on commit statuses.
+	DetailsURL  string `json:"detailsUrl"`
+	TargetURL   string `json:"targetUrl"`
+	CompletedAt string `json:"completedAt"`
 }
 
 type ghReviewThreadsResp struct {
@@ -102,6 +116,12 @@ type ghReviewThreadsResp struct {
 					} `json:"pageInfo"`
 					Nodes []struct {
 						IsResolved bool `json:"isResolved"`
+						Comments   struct {
+							Nodes []struct {
+								URL       string `json:"url"`
+								CreatedAt string `json:"createdAt"`
+							} `json:"nodes"`
+						} `json:"comments"`
 					} `json:"nodes"`
 				} `json:"reviewThreads"`
 			} `json:"pullRequest"`
@@ -189,6 +209,7 @@ func (m *GHManager) prDataByBranch(repoRoot string, branches []string, force boo
 		toFetch = append(toFetch, b)
 	}
 	m.mu.Unlock()
+	logDebug("gh pr data lookup", "repo", repoRoot, "branches", len(needed), "to_fetch", len(toFetch), "force", force)
 
 	var fetchErr error
 	if len(toFetch) > 0 {
@@ -243,26 +264,49 @@ func (m *GHManager) fetchPRDataForBranches(repoRoot string, branches []string) (
 	if err != nil {
 		owner, name = "", ""
 	}
+	opts := loadPRFetchOptions(repoRoot)
 	type branchResult struct {
 		branch string
 		data   PRData
 		found  bool
 		err    error
 	}
-	results := make(chan branchResult, len(branches))
-	sem := make(chan struct{}, maxBranchFetchParallel)
-	var wg sync.WaitGroup
+	valid := make([]string, 0, len(branches))
 	for _, branch := range branches {
-		b := strings.TrimSpace(branch)
-		if b == "" || b == "detached" {
+		if b := strings.TrimSpace(branch); b != "" && b != "detached" {
+			valid = append(valid, b)
+		}
+	}
+	// One `gh pr list` per batch of branches; only branches it doesn't
+	// resolve fall back to a `gh pr view` each.
+	batched := make(map[string]ghPR, len(valid))
+	for _, batch := range headSearchBatches(valid) {
+		prs, err := ghPRListByHeads(ghPath, repoRoot, batch)
+		if err != nil {
+			logDebug("gh pr list batch failed; falling back per branch", "repo", repoRoot, "branches", len(batch), "err", err)
 			continue
 		}
+		for b, pr := range prs {
+			batched[b] = pr
+		}
+	}
+	results := make(chan branchResult, len(valid))
+	sem := make(chan struct{}, maxBranchFetchParallel)
+	var wg sync.WaitGroup
+	for _, b := range valid {
 		wg.Add(1)
 		go func(branchName string) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			data, found, fetchErr := ghPRDataForBranch(ghPath, repoRoot, owner, name, branchName)
+			var data PRData
+			found := false
+			var fetchErr error
+			if pr, ok := batched[branchName]; ok {
+				data, found = ghPRDataFromPR(ghPath, repoRoot, owner, name, branchName, pr, opts), true
+			} else {
+				data, found, fetchErr = ghPRDataForBranch(ghPath, repoRoot, owner, name, branchName, opts)
+			}
 			results <- branchResult{
 				branch: branchName,
 				data:   data,
@@ -288,7 +332,7 @@ func (m *GHManager) fetchPRDataForBranches(repoRoot string, branches []string) (
 	return out, firstErr
 }
 
-func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string, branch string) (PRData, bool, error) {
+func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string, branch string, opts prFetchOptions) (PRData, bool, error) {
 	pr, found, err := ghPRViewByBranch(ghPath, repoRoot, branch, fullPRListFields, ghPRHeadFullTimeout)
 	if err != nil {
 		pr, found, err = ghPRViewByBranch(ghPath, repoRoot, branch, fallbackPRListFields, ghPRHeadFallbackTimeout)
@@ -299,7 +343,39 @@ func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string
 	if !found {
 		return PRData{}, false, nil
 	}
-	ciState, ciDone, ciTotal, failingNames := summarizeCI(pr.StatusCheckRollup)
+	return ghPRDataFromPR(ghPath, repoRoot, owner, name, branch, pr, opts), true, nil
+}
+
+// prFetchOptions is the config that shapes PR enrichment, read once per
+// fetch rather than once per PR.
+type prFetchOptions struct {
+	requiredCIPatterns []string
+	countSkippedChecks bool
+	commentLinks       bool
+	reviewers          bool
+}
+
+// loadPRFetchOptions reads prFetchOptions for repoRoot; count_skipped_checks
+// defaults to true.
+func loadPRFetchOptions(repoRoot string) prFetchOptions {
+	opts := prFetchOptions{countSkippedChecks: true}
+	cfg, err := loadConfigForDir(repoRoot)
+	if err != nil {
+		return opts
+	}
+	opts.requiredCIPatterns = cfg.RequiredCICheckPatterns
+	if cfg.CountSkippedChecks != nil {
+		opts.countSkippedChecks = *cfg.CountSkippedChecks
+	}
+	opts.commentLinks = cfg.FetchCommentLinks
+	opts.reviewers = cfg.FetchReviewers
+	return opts
+}
+
+// ghPRDataFromPR enriches a PR from `gh pr view`/`gh pr list` with review,
+// branch protection, and comment-thread data.
+func ghPRDataFromPR(ghPath string, repoRoot string, owner string, name string, branch string, pr ghPR, opts prFetchOptions) PRData {
+	ciState, ciDone, ciTotal, failingNames := summarizeCI(pr.StatusCheckRollup, opts.requiredCIPatterns, opts.countSkippedChecks)
 	reviewApproved, reviewRequired, reviewKnown := reviewProgressForPR(ghPath, repoRoot, owner, name, pr.Number, pr.BaseRefName, pr.ReviewDecision, strings.EqualFold(strings.TrimSpace(pr.ReviewDecision), "approved"))
 	ciRequired := false
 	commentsRequired := false
@@ -326,16 +402,23 @@ func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string
 		CICompleted:      ciDone,
 		CITotal:          ciTotal,
 		CIFailingNames:   failingNames,
+		CIFailingURL:     failingCheckURL(pr.StatusCheckRollup),
 		CommentsRequired: commentsRequired,
 	}
 	baseStatus := normalizePRStatus(pr.State, pr.MergedAt, pr.IsDraft)
 	if owner != "" && name != "" && pr.Number > 0 && (baseStatus == "open" || baseStatus == "draft") {
-		if counts, uerr := reviewThreadCountsForPR(ghPath, repoRoot, owner, name, pr.Number); uerr == nil {
+		if counts, uerr := reviewThreadCountsForPR(ghPath, repoRoot, owner, name, pr.Number, opts.commentLinks); uerr == nil {
+			data.LatestCommentURL = counts.LatestUnresolvedURL
 			data.UnresolvedComments = counts.Unresolved
 			data.ResolvedComments = counts.Resolved
 			data.CommentThreadsTotal = counts.Total
 			data.CommentsKnown = true
 		}
+		if opts.reviewers {
+			if reviewers, rerr := reviewersForPR(ghPath, repoRoot, owner, name, pr.Number); rerr == nil {
+				data.Reviewers = reviewers
+			}
+		}
 	}
 	data.Status = computePRStatus(
 		pr.State,
@@ -354,10 +437,130 @@ func ghPRDataForBranch(ghPath string, repoRoot string, owner string, name string
 	if strings.TrimSpace(data.Branch) == "" {
 		data.Branch = branch
 	}
-	return data, true, nil
+	return data
+}
+
+// headSearchBatches groups branches into `head:` search queries that stay
+// under GitHub's search query length limit.
+func headSearchBatches(branches []string) [][]string {
+	var batches [][]string
+	var current []string
+	length := 0
+	for _, branch := range branches {
+		term := len("head:") + len(branch) + 1
+		if len(current) > 0 && length+term > ghPRSearchQueryMax {
+			batches = append(batches, current)
+			current, length = nil, 0
+		}
+		current = append(current, branch)
+		length += term
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// ghEnvPassthroughPrefix marks variables forwarded to gh without the prefix,
+// e.g. WTX_GH_TOKEN=... wtx points one invocation at another account.
+const ghEnvPassthroughPrefix = "WTX_"
+
+// ghCommandEnv is the environment for gh run in dir: the parent environment
+// plus gh_env from config and any WTX_GH_* overrides. It returns nil, which
+// exec treats as "inherit", when there is nothing to add.
+func ghCommandEnv(dir string) []string {
+	extra := map[string]string{}
+	if cfg, err := loadConfigForDir(dir); err == nil {
+		for key, value := range cfg.GHEnv {
+			extra[key] = os.ExpandEnv(value)
+		}
+	}
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(key, ghEnvPassthroughPrefix+"GH_") {
+			extra[strings.TrimPrefix(key, ghEnvPassthroughPrefix)] = value
+		}
+	}
+	if len(extra) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	env := os.Environ()
+	for _, key := range keys {
+		env = append(env, key+"="+extra[key])
+	}
+	return env
+}
+
+func ghPRListByHeads(ghPath string, repoRoot string, branches []string) (map[string]ghPR, error) {
+	terms := make([]string, 0, len(branches))
+	for _, b := range branches {
+		terms = append(terms, "head:"+b)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghPRHeadFullTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(
+		ctx,
+		ghPath,
+		"pr",
+		"list",
+		"--state", "all",
+		"--search", strings.Join(terms, " "),
+		"--limit", strconv.Itoa(4*len(branches)),
+		"--json", fullPRListFields,
+	)
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("gh pr list timed out after %s", ghPRHeadFullTimeout.Round(time.Second))
+		}
+		return nil, commandErrorWithOutput(err, out)
+	}
+	var prs []ghPR
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, err
+	}
+	return pickBranchPRs(branches, prs), nil
+}
+
+// pickBranchPRs maps each branch to the PR `gh pr view <branch>` would show:
+// an open PR when there is one, otherwise the most recent.
+func pickBranchPRs(branches []string, prs []ghPR) map[string]ghPR {
+	wanted := make(map[string]bool, len(branches))
+	for _, b := range branches {
+		wanted[b] = true
+	}
+	out := make(map[string]ghPR, len(branches))
+	for _, pr := range prs {
+		head := strings.TrimSpace(pr.HeadRefName)
+		if !wanted[head] {
+			continue
+		}
+		current, ok := out[head]
+		if !ok {
+			out[head] = pr
+			continue
+		}
+		prOpen := strings.EqualFold(pr.State, "OPEN")
+		currentOpen := strings.EqualFold(current.State, "OPEN")
+		if (prOpen && !currentOpen) || (prOpen == currentOpen && pr.Number > current.Number) {
+			out[head] = pr
+		}
+	}
+	return out
 }
 
 func ghPRViewByBranch(ghPath string, repoRoot string, branch string, fields string, timeout time.Duration) (ghPR, bool, error) {
+	start := time.Now()
+	defer func() {
+		logDebug("gh pr view finished", "repo", repoRoot, "branch", branch, "duration", time.Since(start))
+	}()
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	cmd := exec.CommandContext(
@@ -369,9 +572,11 @@ func ghPRViewByBranch(ghPath string, repoRoot string, branch string, fields stri
 		"--json", fields,
 	)
 	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logError("gh pr view timed out", "repo", repoRoot, "branch", branch, "timeout", timeout)
 			return ghPR{}, false, fmt.Errorf("gh pr view timed out after %s", timeout.Round(time.Second))
 		}
 		msg := strings.TrimSpace(string(out))
@@ -381,6 +586,7 @@ func ghPRViewByBranch(ghPath string, repoRoot string, branch string, fields stri
 		if strings.Contains(strings.ToLower(msg), "not found") && strings.Contains(strings.ToLower(msg), "pull request") {
 			return ghPR{}, false, nil
 		}
+		logError("gh pr view failed", "repo", repoRoot, "branch", branch, "err", err, "output", msg)
 		if msg == "" {
 			return ghPR{}, false, err
 		}
@@ -446,6 +652,7 @@ func requiredChecksForBaseBranch(ghPath string, repoRoot string, owner string, n
 	defer cancel()
 	cmd := exec.CommandContext(ctx, ghPath, "api", endpoint)
 	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
@@ -498,6 +705,7 @@ func approvedReviewsCount(ghPath string, repoRoot string, owner string, name str
 	defer cancel()
 	cmd := exec.CommandContext(ctx, ghPath, "api", endpoint)
 	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
@@ -598,10 +806,15 @@ func computePRStatus(state string, mergedAt string, isDraft bool, mergeStateStat
 	return base
 }
 
-func summarizeCI(checks []ghCheck) (PRCIState, int, int, string) {
+// summarizeCI folds checks into the CI column. With required patterns only
+// matching checks decide the state and counts, but every failing check is
+// still named so optional failures stay visible. Without countSkipped,
+// skipped and neutral checks are left out of the counts entirely.
+func summarizeCI(checks []ghCheck, required []string, countSkipped bool) (PRCIState, int, int, string) {
 	if len(checks) == 0 {
 		return PRCINone, 0, 0, ""
 	}
+	requiredMatchers := compileGlobs(required)
 	total := 0
 	completed := 0
 	inProgress := false
@@ -614,6 +827,24 @@ func summarizeCI(checks []ghCheck) (PRCIState, int, int, string) {
 		if status == "" && conclusion == "" {
 			continue
 		}
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			name = strings.TrimSpace(c.Context)
+		}
+		switch conclusion {
+		case "", "SUCCESS", "SKIPPED", "NEUTRAL":
+		default:
+			if name != "" && !failingNamesSet[name] {
+				failingNamesSet[name] = true
+				failingNames = append(failingNames, name)
+			}
+		}
+		if len(requiredMatchers) > 0 && !matchesAnyGlob(requiredMatchers, name) {
+			continue
+		}
+		if !countSkipped && (conclusion == "SKIPPED" || conclusion == "NEUTRAL") {
+			continue
+		}
 		total++
 		if conclusion != "" {
 			completed++
@@ -621,14 +852,6 @@ func summarizeCI(checks []ghCheck) (PRCIState, int, int, string) {
 			case "SUCCESS", "SKIPPED", "NEUTRAL":
 			default:
 				failed = true
-				name := strings.TrimSpace(c.Name)
-				if name == "" {
-					name = strings.TrimSpace(c.Context)
-				}
-				if name != "" && !failingNamesSet[name] {
-					failingNamesSet[name] = true
-					failingNames = append(failingNames, name)
-				}
 			}
 		}
 		if status != "" && status != "COMPLETED" {
@@ -647,27 +870,90 @@ func summarizeCI(checks []ghCheck) (PRCIState, int, int, string) {
 		return PRCIFail, completed, total, failingLabel
 	}
 	if inProgress || completed < total {
-		return PRCIInProgress, completed, total, ""
+		return PRCIInProgress, completed, total, failingLabel
+	}
+	return PRCISuccess, completed, total, failingLabel
+}
+
+// failingCheckURL returns the details link of the most recently completed
+// failing check, or "" when no failing check has one.
+func failingCheckURL(checks []ghCheck) string {
+	best := ""
+	bestAt := ""
+	for _, c := range checks {
+		switch strings.ToUpper(strings.TrimSpace(c.Conclusion)) {
+		case "", "SUCCESS", "SKIPPED", "NEUTRAL":
+			continue
+		}
+		link := strings.TrimSpace(c.DetailsURL)
+		if link == "" {
+			link = strings.TrimSpace(c.TargetURL)
+		}
+		if link == "" {
+			continue
+		}
+		// completedAt is RFC 3339 in UTC, so it orders as a string.
+		at := strings.TrimSpace(c.CompletedAt)
+		if best == "" || at > bestAt {
+			best = link
+			bestAt = at
+		}
 	}
-	return PRCISuccess, completed, total, ""
+	return best
+}
+
+// actionsURLForPR turns a PR URL into its repository's Actions tab.
+func actionsURLForPR(prURL string) string {
+	prURL = strings.TrimSpace(prURL)
+	i := strings.LastIndex(prURL, "/pull/")
+	if i <= 0 {
+		return ""
+	}
+	return prURL[:i] + "/actions"
+}
+
+// compareURL is the GitHub compare view of branch against base.
+func compareURL(owner string, name string, base string, branch string) string {
+	base = shortBranch(base)
+	branch = strings.TrimSpace(branch)
+	if owner == "" || name == "" || base == "detached" || branch == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", owner, name, base, branch)
 }
 
 type reviewThreadCounts struct {
 	Resolved   int
 	Unresolved int
 	Total      int
+	// LatestUnresolvedURL is only set when comment links were requested.
+	LatestUnresolvedURL string
 }
 
-func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name string, number int) (reviewThreadCounts, error) {
+const (
+	reviewThreadsQueryFormat = `query($owner:String!,$name:String!,$number:Int!,$after:String){repository(owner:$owner,name:$name){pullRequest(number:$number){reviewThreads(first:100,after:$after){totalCount pageInfo{hasNextPage endCursor} nodes{isResolved%s}}}}}`
+	reviewThreadCommentField = ` comments(last:1){nodes{url createdAt}}`
+)
+
+// reviewThreadCountsForPR counts resolved and unresolved review threads. With
+// withComments it also asks for each thread's last comment, which costs more
+// GraphQL rate limit, to find the newest unresolved one.
+func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name string, number int, withComments bool) (reviewThreadCounts, error) {
 	if owner == "" || name == "" || number <= 0 {
 		return reviewThreadCounts{}, errors.New("repo/number required")
 	}
-	query := `query($owner:String!,$name:String!,$number:Int!,$after:String){repository(owner:$owner,name:$name){pullRequest(number:$number){reviewThreads(first:100,after:$after){totalCount pageInfo{hasNextPage endCursor} nodes{isResolved}}}}}`
+	commentField := ""
+	if withComments {
+		commentField = reviewThreadCommentField
+	}
+	query := fmt.Sprintf(reviewThreadsQueryFormat, commentField)
 	ctx, cancel := context.WithTimeout(context.Background(), ghUnresolvedPRTimeout)
 	defer cancel()
 	after := ""
 	total := 0
 	unresolved := 0
+	latestURL := ""
+	latestAt := ""
 	seenTotal := false
 	for {
 		args := []string{"api", "graphql", "-f", "query=" + query, "-F", "owner=" + owner, "-F", "name=" + name, "-F", fmt.Sprintf("number=%d", number)}
@@ -676,6 +962,7 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 		}
 		cmd := exec.CommandContext(ctx, ghPath, args...)
 		cmd.Dir = repoRoot
+		cmd.Env = ghCommandEnv(repoRoot)
 		out, err := cmd.Output()
 		if err != nil {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
@@ -693,8 +980,15 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 			seenTotal = true
 		}
 		for _, t := range rt.Nodes {
-			if !t.IsResolved {
-				unresolved++
+			if t.IsResolved {
+				continue
+			}
+			unresolved++
+			for _, c := range t.Comments.Nodes {
+				if url := strings.TrimSpace(c.URL); url != "" && (latestURL == "" || c.CreatedAt > latestAt) {
+					latestURL = url
+					latestAt = c.CreatedAt
+				}
 			}
 		}
 		if !rt.PageInfo.HasNextPage || strings.TrimSpace(rt.PageInfo.EndCursor) == "" {
@@ -710,14 +1004,15 @@ func reviewThreadCountsForPR(ghPath string, repoRoot string, owner string, name
 		resolved = 0
 	}
 	return reviewThreadCounts{
-		Resolved:   resolved,
-		Unresolved: unresolved,
-		Total:      total,
+		Resolved:            resolved,
+		Unresolved:          unresolved,
+		Total:               total,
+		LatestUnresolvedURL: latestURL,
 	}, nil
 }
 
 func resolveGitHubRepo(repoRoot string) (string, string, error) {
-	remote, err := gitOutputInDir(repoRoot, "git", "remote", "get-url", "origin")
+	remote, err := gitOutputInDir(repoRoot, gitBinary(), "remote", "get-url", "origin")
 	if err != nil {
 		return "", "", err
 	}
@@ -1,6 +1,12 @@
 package cmd
 
-import "testing"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestEnsureRequiredAtLeastApproved_UsesActualApprovalCount(t *testing.T) {
 	required, known := ensureRequiredAtLeastApproved(2, true, 1, true)
@@ -62,3 +68,221 @@ func TestComputePRStatus_Priority(t *testing.T) {
 		})
 	}
 }
+
+func TestSummarizeCI_RequiredPatterns(t *testing.T) {
+	checks := []ghCheck{
+		{Name: "build", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		{Name: "test / unit", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		{Name: "flaky-e2e", Status: "COMPLETED", Conclusion: "FAILURE"},
+	}
+	tests := []struct {
+		name      string
+		required  []string
+		wantState PRCIState
+		wantDone  int
+		wantTotal int
+		wantNames string
+	}{
+		{name: "all checks count by default", wantState: PRCIFail, wantDone: 3, wantTotal: 3, wantNames: "flaky-e2e"},
+		{name: "optional failure ignored", required: []string{"build", "test/*", "test *"}, wantState: PRCISuccess, wantDone: 2, wantTotal: 2, wantNames: "flaky-e2e"},
+		{name: "required failure fails", required: []string{"flaky-*"}, wantState: PRCIFail, wantDone: 1, wantTotal: 1, wantNames: "flaky-e2e"},
+		{name: "no matching checks", required: []string{"deploy"}, wantState: PRCINone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, done, total, names := summarizeCI(checks, tt.required, true)
+			if state != tt.wantState || done != tt.wantDone || total != tt.wantTotal || names != tt.wantNames {
+				t.Fatalf("got (%v, %d, %d, %q), want (%v, %d, %d, %q)", state, done, total, names, tt.wantState, tt.wantDone, tt.wantTotal, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestSummarizeCI_SkippedChecks(t *testing.T) {
+	checks := []ghCheck{
+		{Name: "build", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		{Name: "deploy", Status: "COMPLETED", Conclusion: "SKIPPED"},
+		{Name: "lint", Status: "COMPLETED", Conclusion: "NEUTRAL"},
+		{Name: "test", Status: "IN_PROGRESS"},
+	}
+	tests := []struct {
+		name         string
+		countSkipped bool
+		wantDone     int
+		wantTotal    int
+	}{
+		{name: "counted as passed by default", countSkipped: true, wantDone: 3, wantTotal: 4},
+		{name: "left out when disabled", countSkipped: false, wantDone: 1, wantTotal: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, done, total, _ := summarizeCI(checks, nil, tt.countSkipped)
+			if state != PRCIInProgress || done != tt.wantDone || total != tt.wantTotal {
+				t.Fatalf("got (%v, %d, %d), want (%v, %d, %d)", state, done, total, PRCIInProgress, tt.wantDone, tt.wantTotal)
+			}
+		})
+	}
+	if state, _, total, _ := summarizeCI(checks[1:3], nil, false); state != PRCINone || total != 0 {
+		t.Fatalf("expected only-skipped checks to show no CI, got %v %d", state, total)
+	}
+}
+
+func TestHeadSearchBatches_StaysUnderQueryLimit(t *testing.T) {
+	branches := make([]string, 0, 30)
+	for i := 0; i < 30; i++ {
+		branches = append(branches, fmt.Sprintf("feature/branch-%02d", i))
+	}
+	batches := headSearchBatches(branches)
+	if len(batches) < 2 {
+		t.Fatalf("expected branches split across batches, got %d", len(batches))
+	}
+	total := 0
+	for _, batch := range batches {
+		query := "head:" + strings.Join(batch, " head:")
+		if len(query) > ghPRSearchQueryMax {
+			t.Fatalf("query too long (%d): %s", len(query), query)
+		}
+		total += len(batch)
+	}
+	if total != len(branches) {
+		t.Fatalf("expected every branch batched once, got %d", total)
+	}
+}
+
+func TestPickBranchPRs_PrefersOpenThenNewest(t *testing.T) {
+	prs := []ghPR{
+		{Number: 1, HeadRefName: "feature/a", State: "OPEN"},
+		{Number: 5, HeadRefName: "feature/a", State: "CLOSED"},
+		{Number: 2, HeadRefName: "feature/b", State: "MERGED"},
+		{Number: 3, HeadRefName: "feature/b", State: "CLOSED"},
+		{Number: 4, HeadRefName: "feature/a-other", State: "OPEN"},
+	}
+	got := pickBranchPRs([]string{"feature/a", "feature/b", "feature/c"}, prs)
+	if got["feature/a"].Number != 1 || got["feature/b"].Number != 3 {
+		t.Fatalf("unexpected picks: %+v", got)
+	}
+	if _, ok := got["feature/c"]; ok || len(got) != 2 {
+		t.Fatalf("expected unresolved branch left for per-branch fallback, got %+v", got)
+	}
+}
+
+func TestFailingCheckURL_PicksMostRecentFailure(t *testing.T) {
+	checks := []ghCheck{
+		{Name: "lint", Status: "COMPLETED", Conclusion: "FAILURE", DetailsURL: "https://github.com/o/r/actions/runs/1/job/1", CompletedAt: "2026-01-02T10:00:00Z"},
+		{Name: "test", Status: "COMPLETED", Conclusion: "SUCCESS", DetailsURL: "https://github.com/o/r/actions/runs/2/job/2", CompletedAt: "2026-01-02T12:00:00Z"},
+		{Context: "ci/legacy", Conclusion: "FAILURE", TargetURL: "https://ci.example.com/build/9", CompletedAt: "2026-01-02T11:00:00Z"},
+		{Name: "build", Status: "COMPLETED", Conclusion: "FAILURE", CompletedAt: "2026-01-02T13:00:00Z"},
+	}
+	if got := failingCheckURL(checks); got != "https://ci.example.com/build/9" {
+		t.Fatalf("unexpected failing check URL %q", got)
+	}
+	if got := failingCheckURL(checks[1:2]); got != "" {
+		t.Fatalf("expected no URL without failures, got %q", got)
+	}
+}
+
+func TestActionsURLForPR(t *testing.T) {
+	if got := actionsURLForPR("https://github.com/o/r/pull/42"); got != "https://github.com/o/r/actions" {
+		t.Fatalf("unexpected actions URL %q", got)
+	}
+	if got := actionsURLForPR(""); got != "" {
+		t.Fatalf("expected empty URL, got %q", got)
+	}
+}
+
+func TestCompareURL(t *testing.T) {
+	if got := compareURL("o", "r", "origin/main", "feature/x"); got != "https://github.com/o/r/compare/main...feature/x" {
+		t.Fatalf("unexpected compare URL %q", got)
+	}
+	if got := compareURL("o", "r", "", "feature/x"); got != "" {
+		t.Fatalf("expected empty URL without a base, got %q", got)
+	}
+}
+
+func TestReviewThreadCountsForPR_LatestUnresolvedComment(t *testing.T) {
+	dir := t.TempDir()
+	fakeGH := filepath.Join(dir, "gh")
+	resp := `{"data":{"repository":{"pullRequest":{"reviewThreads":{"totalCount":3,"pageInfo":{"hasNextPage":false},"nodes":[` +
+		`{"isResolved":false,"comments":{"nodes":[{"url":"https://github.com/o/r/pull/1#discussion_r1","createdAt":"2026-01-01T10:00:00Z"}]}},` +
+		`{"isResolved":true,"comments":{"nodes":[{"url":"https://github.com/o/r/pull/1#discussion_r2","createdAt":"2026-01-03T10:00:00Z"}]}},` +
+		`{"isResolved":false,"comments":{"nodes":[{"url":"https://github.com/o/r/pull/1#discussion_r3","createdAt":"2026-01-02T10:00:00Z"}]}}]}}}}}`
+	script := "#!/bin/sh\ncase \"$*\" in *'comments(last:1)'*) printf '%s' '" + resp + "' ;; *) echo '{}' ;; esac\n"
+	if err := os.WriteFile(fakeGH, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake gh: %v", err)
+	}
+
+	counts, err := reviewThreadCountsForPR(fakeGH, dir, "o", "r", 1, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts.Unresolved != 2 || counts.Resolved != 1 {
+		t.Fatalf("unexpected counts %+v", counts)
+	}
+	if counts.LatestUnresolvedURL != "https://github.com/o/r/pull/1#discussion_r3" {
+		t.Fatalf("unexpected latest comment %q", counts.LatestUnresolvedURL)
+	}
+
+	counts, err = reviewThreadCountsForPR(fakeGH, dir, "o", "r", 1, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts.LatestUnresolvedURL != "" {
+		t.Fatalf("expected no comment link without fetch_comment_links, got %q", counts.LatestUnresolvedURL)
+	}
+}
+
+func TestGHCommandEnv_MergesConfigAndPassthrough(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	t.Setenv("WORK_TOKEN", "from-shell")
+	t.Setenv("WTX_GH_HOST", "ghe.example.com")
+	if err := SaveConfig(Config{GHEnv: map[string]string{"GH_TOKEN": "global", "GH_HOST": "github.com"}}); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, repoConfigFileName), `{"gh_env":{"GH_TOKEN":"$WORK_TOKEN"}}`)
+
+	env := ghCommandEnv(repo)
+	got := map[string]string{}
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		got[key] = value
+	}
+	if got["GH_TOKEN"] != "from-shell" {
+		t.Fatalf("expected repo gh_env to expand $WORK_TOKEN, got %q", got["GH_TOKEN"])
+	}
+	if got["GH_HOST"] != "ghe.example.com" {
+		t.Fatalf("expected WTX_GH_HOST to override gh_env, got %q", got["GH_HOST"])
+	}
+}
+
+func TestGHCommandEnv_InheritsWhenNothingConfigured(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if env := ghCommandEnv(t.TempDir()); env != nil {
+		t.Fatalf("expected nil env to inherit the parent, got %d entries", len(env))
+	}
+}
+
+func TestLoadPRFetchOptions_DefaultsAndConfig(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	if got := loadPRFetchOptions(repo); !got.countSkippedChecks || got.commentLinks || got.reviewers || got.requiredCIPatterns != nil {
+		t.Fatalf("expected defaults without config, got %+v", got)
+	}
+	countSkipped := false
+	cfg := Config{
+		RequiredCICheckPatterns: []string{"build"},
+		CountSkippedChecks:      &countSkipped,
+		FetchCommentLinks:       true,
+		FetchReviewers:          true,
+	}
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig: %v", err)
+	}
+	got := loadPRFetchOptions(repo)
+	if got.countSkippedChecks || !got.commentLinks || !got.reviewers || len(got.requiredCIPatterns) != 1 {
+		t.Fatalf("expected configured options, got %+v", got)
+	}
+}
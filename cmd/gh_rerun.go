@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ghRerunTimeout = 20 * time.Second
+
+var actionsRunIDPattern = regexp.MustCompile(`/actions/runs/(\d+)`)
+
+type ghPRCheck struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket"`
+	Link   string `json:"link"`
+}
+
+// rerunFailedPRChecks re-runs the failed jobs of every GitHub Actions run
+// with a failing check on the PR and returns how many runs were restarted.
+func rerunFailedPRChecks(repoRoot string, prNumber int) (int, error) {
+	if prNumber <= 0 {
+		return 0, errors.New("pull request number required")
+	}
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return 0, errors.New("gh not installed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghRerunTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ghPath, "pr", "checks", strconv.Itoa(prNumber), "--json", "name,bucket,link")
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	// gh pr checks exits non-zero while checks fail or are pending, so only
+	// give up when stdout isn't the JSON we asked for.
+	out, runErr := cmd.Output()
+	runIDs, err := failedRunIDsFromChecks(out)
+	if err != nil {
+		if runErr != nil {
+			return 0, fmt.Errorf("gh pr checks failed: %w", runErr)
+		}
+		return 0, err
+	}
+	if len(runIDs) == 0 {
+		return 0, errors.New("no failed GitHub Actions runs to re-run")
+	}
+	for _, id := range runIDs {
+		rerun := exec.CommandContext(ctx, ghPath, "run", "rerun", id, "--failed")
+		rerun.Dir = repoRoot
+		rerun.Env = ghCommandEnv(repoRoot)
+		if out, err := rerun.CombinedOutput(); err != nil {
+			logError("gh run rerun failed", "repo", repoRoot, "run", id, "err", err, "output", strings.TrimSpace(string(out)))
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return 0, fmt.Errorf("gh run rerun %s: %s", id, msg)
+			}
+			return 0, fmt.Errorf("gh run rerun %s: %w", id, err)
+		}
+	}
+	return len(runIDs), nil
+}
+
+func failedRunIDsFromChecks(data []byte) ([]string, error) {
+	var checks []ghPRCheck
+	if err := json.Unmarshal(data, &checks); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	ids := make([]string, 0, len(checks))
+	for _, check := range checks {
+		if !strings.EqualFold(strings.TrimSpace(check.Bucket), "fail") {
+			continue
+		}
+		match := actionsRunIDPattern.FindStringSubmatch(check.Link)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		ids = append(ids, match[1])
+	}
+	return ids, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFailedRunIDsFromChecks(t *testing.T) {
+	data := []byte(`[
+		{"name":"build","bucket":"pass","link":"https://github.com/o/r/actions/runs/11/job/1"},
+		{"name":"unit","bucket":"fail","link":"https://github.com/o/r/actions/runs/22/job/2"},
+		{"name":"lint","bucket":"fail","link":"https://github.com/o/r/actions/runs/22/job/3"},
+		{"name":"e2e","bucket":"fail","link":"https://github.com/o/r/actions/runs/33/job/4"},
+		{"name":"vercel","bucket":"fail","link":"https://vercel.com/o/r/deployments/abc"}
+	]`)
+	ids, err := failedRunIDsFromChecks(data)
+	if err != nil {
+		t.Fatalf("failedRunIDsFromChecks: %v", err)
+	}
+	if got := strings.Join(ids, ","); got != "22,33" {
+		t.Fatalf("expected failed run ids 22,33, got %q", got)
+	}
+	if _, err := failedRunIDsFromChecks([]byte("no checks reported")); err == nil {
+		t.Fatalf("expected non-JSON output to fail")
+	}
+}
+
+func TestListModeRerunRequiresFailingCI(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/a", Available: true, HasPR: true, PRNumber: 7, CIState: PRCISuccess},
+		},
+	}
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	updated := updatedModel.(model)
+	if cmd != nil || !strings.Contains(updated.errMsg, "No failing CI") {
+		t.Fatalf("expected re-run to be refused without failing CI, got %q", updated.errMsg)
+	}
+
+	m.status.Worktrees[0].CIState = PRCIFail
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	updated = updatedModel.(model)
+	if cmd == nil || !strings.Contains(updated.warnMsg, "Re-running failed checks") {
+		t.Fatalf("expected re-run command for failing CI, got %q", updated.warnMsg)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	ghReviewersTimeout = 10 * time.Second
+	prReviewersQuery   = `query($owner:String!,$name:String!,$number:Int!){repository(owner:$owner,name:$name){pullRequest(number:$number){author{login} reviewRequests(first:50){nodes{requestedReviewer{... on User{login} ... on Team{combinedSlug}}}} latestReviews(first:50){nodes{author{login}}}}}}`
+)
+
+type ghPRReviewersResp struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				Author struct {
+					Login string `json:"login"`
+				} `json:"author"`
+				ReviewRequests struct {
+					Nodes []struct {
+						RequestedReviewer struct {
+							Login        string `json:"login"`
+							CombinedSlug string `json:"combinedSlug"`
+						} `json:"requestedReviewer"`
+					} `json:"nodes"`
+				} `json:"reviewRequests"`
+				LatestReviews struct {
+					Nodes []struct {
+						Author struct {
+							Login string `json:"login"`
+						} `json:"author"`
+					} `json:"nodes"`
+				} `json:"latestReviews"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+}
+
+func fetchReviewersEnabled(repoRoot string) bool {
+	cfg, err := loadConfigForDir(repoRoot)
+	return err == nil && cfg.FetchReviewers
+}
+
+// reviewersForPR lists who review can be re-requested from: everyone who has
+// reviewed plus anyone still requested, without the PR's author.
+func reviewersForPR(ghPath string, repoRoot string, owner string, name string, number int) ([]string, error) {
+	if owner == "" || name == "" || number <= 0 {
+		return nil, errors.New("repo/number required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghReviewersTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ghPath, "api", "graphql", "-f", "query="+prReviewersQuery, "-F", "owner="+owner, "-F", "name="+name, "-F", fmt.Sprintf("number=%d", number))
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("gh api graphql timed out after %s", ghReviewersTimeout.Round(time.Second))
+		}
+		return nil, err
+	}
+	return parsePRReviewers(out)
+}
+
+func parsePRReviewers(data []byte) ([]string, error) {
+	var resp ghPRReviewersResp
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	pr := resp.Data.Repository.PullRequest
+	author := strings.ToLower(strings.TrimSpace(pr.Author.Login))
+	seen := map[string]bool{}
+	var reviewers []string
+	add := func(login string) {
+		login = strings.TrimSpace(login)
+		key := strings.ToLower(login)
+		if login == "" || key == author || seen[key] {
+			return
+		}
+		seen[key] = true
+		reviewers = append(reviewers, login)
+	}
+	for _, review := range pr.LatestReviews.Nodes {
+		add(review.Author.Login)
+	}
+	for _, request := range pr.ReviewRequests.Nodes {
+		add(request.RequestedReviewer.Login)
+		add(request.RequestedReviewer.CombinedSlug)
+	}
+	return reviewers, nil
+}
+
+// rerequestReview asks reviewers for another review; GitHub treats adding a
+// reviewer who already reviewed as a re-request.
+func rerequestReview(repoRoot string, prNumber int, reviewers []string) error {
+	if prNumber <= 0 {
+		return errors.New("pull request number required")
+	}
+	if len(reviewers) == 0 {
+		return errors.New("no reviewers to re-request")
+	}
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return errors.New("gh not installed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghReviewersTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ghPath, "pr", "edit", strconv.Itoa(prNumber), "--add-reviewer", strings.Join(reviewers, ","))
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("gh pr edit: %s", msg)
+		}
+		return fmt.Errorf("gh pr edit: %w", err)
+	}
+	return nil
+}
+
+type reviewRequestDoneMsg struct {
+	branch    string
+	reviewers []string
+	err       error
+}
+
+func rerequestReviewCmd(repoRoot string, branch string, prNumber int, reviewers []string) tea.Cmd {
+	return func() tea.Msg {
+		err := rerequestReview(repoRoot, prNumber, reviewers)
+		return reviewRequestDoneMsg{branch: branch, reviewers: reviewers, err: err}
+	}
+}
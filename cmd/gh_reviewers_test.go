@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParsePRReviewers(t *testing.T) {
+	data := []byte(`{"data":{"repository":{"pullRequest":{
+		"author":{"login":"me"},
+		"reviewRequests":{"nodes":[
+			{"requestedReviewer":{"login":"carol"}},
+			{"requestedReviewer":{"combinedSlug":"org/core"}},
+			{"requestedReviewer":{"login":"Alice"}}
+		]},
+		"latestReviews":{"nodes":[
+			{"author":{"login":"alice"}},
+			{"author":{"login":"me"}},
+			{"author":{"login":"bob"}}
+		]}
+	}}}}`)
+	reviewers, err := parsePRReviewers(data)
+	if err != nil {
+		t.Fatalf("parsePRReviewers: %v", err)
+	}
+	if got := strings.Join(reviewers, ","); got != "alice,bob,carol,org/core" {
+		t.Fatalf("expected alice,bob,carol,org/core, got %q", got)
+	}
+}
+
+func TestListModeRerequestReviewRequiresReviewers(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	m := newModel()
+	m.mode = modeList
+	m.ready = true
+	m.status = WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/a", Available: true, HasPR: true, PRNumber: 7},
+		},
+	}
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	updated := updatedModel.(model)
+	if cmd != nil || !strings.Contains(updated.errMsg, "fetch_reviewers") {
+		t.Fatalf("expected a hint to enable fetch_reviewers, got %q", updated.errMsg)
+	}
+
+	m.status.Worktrees[0].Reviewers = []string{"alice"}
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	updated = updatedModel.(model)
+	if cmd == nil || !strings.Contains(updated.warnMsg, "Re-requesting review") {
+		t.Fatalf("expected re-request command, got %q", updated.warnMsg)
+	}
+}
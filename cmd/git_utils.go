@@ -1,27 +1,178 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+const gitPathEnv = "WTX_GIT"
+const gitVersionCheckTimeout = 5 * time.Second
+
 var errGitNotInstalled = errors.New("git not installed")
 var errNotInGitRepository = errors.New("not in a git repository")
 
+var (
+	validatedGitMu    sync.Mutex
+	validatedGitPaths = map[string]error{}
+)
+
+// minPathFormatGit is the first git with `rev-parse --path-format`.
+var minPathFormatGit = parsedVersion{Major: 2, Minor: 31}
+
+var gitVersionPattern = regexp.MustCompile(`git version (\d+)\.(\d+)(?:\.(\d+))?`)
+
+var (
+	gitVersionMu    sync.Mutex
+	gitVersionCache = map[string]parsedVersion{}
+)
+
 func gitPath() (string, error) {
+	if configured := configuredGitPath(); configured != "" {
+		if err := validateGitBinary(configured); err != nil {
+			return "", err
+		}
+		return configured, nil
+	}
 	return exec.LookPath("git")
 }
 
 func requireGitPath() (string, error) {
 	path, err := gitPath()
 	if err != nil {
+		if configuredGitPath() != "" {
+			return "", err
+		}
 		return "", errGitNotInstalled
 	}
 	return path, nil
 }
 
+// gitBinary returns the git executable to invoke, falling back to a PATH lookup
+// by name when no override is configured.
+func gitBinary() string {
+	if configured := configuredGitPath(); configured != "" {
+		return configured
+	}
+	return "git"
+}
+
+var (
+	configGitPathOnce sync.Once
+	configGitPath     string
+)
+
+// configuredGitPath returns $WTX_GIT or git_path. git_path only lives in the
+// global config, so it is read once per process rather than on every git call.
+func configuredGitPath() string {
+	if path := strings.TrimSpace(os.Getenv(gitPathEnv)); path != "" {
+		return path
+	}
+	configGitPathOnce.Do(func() {
+		cfg, err := loadGlobalConfig()
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				logError("git_path not read from config", "err", err)
+			}
+			return
+		}
+		configGitPath = cfg.GitPath
+	})
+	return configGitPath
+}
+
+func validateGitBinary(path string) error {
+	validatedGitMu.Lock()
+	defer validatedGitMu.Unlock()
+	if err, ok := validatedGitPaths[path]; ok {
+		return err
+	}
+	err := checkGitBinary(path)
+	validatedGitPaths[path] = err
+	return err
+}
+
+func checkGitBinary(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("configured git %q not found: %w", path, err)
+	}
+	if info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("configured git %q is not executable", path)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitVersionCheckTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("configured git %q failed to report a version: %w", path, commandErrorWithOutput(err, out))
+	}
+	if !strings.HasPrefix(strings.TrimSpace(string(out)), "git version") {
+		return fmt.Errorf("configured git %q reported unexpected version output: %s", path, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// gitVersion runs `git --version` once per binary and parses the result.
+func gitVersion(gitPath string) (parsedVersion, error) {
+	gitVersionMu.Lock()
+	defer gitVersionMu.Unlock()
+	if v, ok := gitVersionCache[gitPath]; ok {
+		return v, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitVersionCheckTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, gitPath, "--version").Output()
+	if err != nil {
+		return parsedVersion{}, err
+	}
+	v, ok := parseGitVersion(string(out))
+	if !ok {
+		return parsedVersion{}, fmt.Errorf("unrecognized git version output: %s", strings.TrimSpace(string(out)))
+	}
+	gitVersionCache[gitPath] = v
+	return v, nil
+}
+
+func parseGitVersion(output string) (parsedVersion, bool) {
+	match := gitVersionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return parsedVersion{}, false
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+	return parsedVersion{Major: major, Minor: minor, Patch: patch}, true
+}
+
+func gitSupportsPathFormat(gitPath string) bool {
+	v, err := gitVersion(gitPath)
+	return err == nil && compareReleaseVersions(v, minPathFormatGit) >= 0
+}
+
+// gitCommonDir returns the absolute shared .git dir for dir. Gits older than
+// 2.31 lack --path-format and print a path relative to dir, resolved here.
+func gitCommonDir(dir string, gitPath string) (string, error) {
+	if gitSupportsPathFormat(gitPath) {
+		return gitOutputInDir(dir, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	}
+	commonDir, err := gitOutputInDir(dir, gitPath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(dir, commonDir)
+	}
+	return filepath.Clean(commonDir), nil
+}
+
 func repoRootForDir(dir string, gitBin string) (string, error) {
 	_ = gitBin
 	if dir == "" {
@@ -50,9 +201,16 @@ func repoRootForDir(dir string, gitBin string) (string, error) {
 }
 
 func requireGitContext(dir string) (string, string, error) {
-	repoRoot, err := repoRootForDir(dir, "git")
+	repoRoot, err := repoRootForDir(dir, "")
 	if err != nil {
 		return "", "", err
 	}
+	if configuredGitPath() != "" {
+		path, err := requireGitPath()
+		if err != nil {
+			return "", "", err
+		}
+		return path, repoRoot, nil
+	}
 	return "git", repoRoot, nil
 }
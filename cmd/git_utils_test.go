@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitPath_UsesEnvOverride(t *testing.T) {
+	realGit, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv(gitPathEnv, realGit)
+
+	got, err := gitPath()
+	if err != nil {
+		t.Fatalf("gitPath with override: %v", err)
+	}
+	if got != realGit {
+		t.Fatalf("expected %q, got %q", realGit, got)
+	}
+	if gitBinary() != realGit {
+		t.Fatalf("expected gitBinary to use override, got %q", gitBinary())
+	}
+}
+
+func TestGitPath_RejectsMissingOverride(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "git")
+	t.Setenv(gitPathEnv, missing)
+
+	_, err := requireGitPath()
+	if err == nil {
+		t.Fatalf("expected error for missing git override")
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Fatalf("expected error to mention configured path, got %q", err.Error())
+	}
+}
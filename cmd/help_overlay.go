@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type helpBinding struct {
+	key  string
+	desc string
+}
+
+var (
+	helpNavBindings = []helpBinding{
+		{"up/down, k/j", "move the selection"},
+	}
+	helpCloseBinding = helpBinding{"?", "toggle this help"}
+)
+
+// helpAvailable reports whether ? opens the help overlay; screens that take
+// free text (notes, lock labels, issue search) keep ? as input.
+func (m model) helpAvailable() bool {
+	if m.confirmForm != nil {
+		return false
+	}
+	switch m.mode {
+	case modeNote, modeLockLabel, modeTitleLabel, modeDelete, modeUnlock:
+		return false
+	case modeOpen:
+		return m.openStage != openStagePickIssue
+	}
+	return true
+}
+
+// updateHelpOverlay handles keys while the overlay is open: ? or esc closes
+// it, q quits, and everything else is swallowed.
+func (m model) updateHelpOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "?", "esc":
+		m.showHelp = false
+	}
+	return m, nil
+}
+
+// helpBindings lists every key for the current screen, not just the ones the
+// footer hint fits for the selected row.
+func (m model) helpBindings() (string, []helpBinding) {
+	switch m.mode {
+	case modeOpen:
+		return m.openHelpBindings()
+	case modeAction:
+		return "Worktree actions", append(append([]helpBinding{}, helpNavBindings...),
+			helpBinding{"enter", "run the selected action"},
+			helpBinding{"esc", "back to the worktree list"},
+		)
+	case modeBranchName:
+		bindings := []helpBinding{}
+		switch {
+		case m.actionPR:
+			bindings = append(bindings, helpBinding{"enter", "check out the pull request"})
+		case m.actionDetach:
+			bindings = append(bindings, helpBinding{"enter", "create a detached worktree"})
+		default:
+			bindings = append(bindings,
+				helpBinding{"tab", "complete a branch name, or generate draft-<ts> when empty"},
+				helpBinding{"enter", "create the branch, or check out an existing one"},
+			)
+		}
+		return "Branch name", append(bindings, helpBinding{"esc", "back to actions"})
+	case modeBranchPick:
+		return "Choose a branch", []helpBinding{
+			{"type", "filter branches"},
+			{"up/down", "move the selection"},
+			{"enter", "use the selected branch"},
+			{"esc", "back to actions"},
+		}
+	case modeCreating:
+		return "Creating worktree", []helpBinding{{"q", "quit"}}
+	case modeLog:
+		return "Commit log", []helpBinding{
+			{"up/down, k/j", "scroll a line"},
+			{"pgup/pgdn", "scroll a page"},
+			{"q, esc", "back to the worktree list"},
+		}
+	}
+	enter := helpBinding{"enter", "actions for the selected row"}
+	switch m.defaultAction {
+	case actionUse:
+		enter.desc = "use the selected worktree"
+	case actionShell:
+		enter.desc = "open a shell in the selected worktree"
+	}
+	return "Worktree list", append(append([]helpBinding{}, helpNavBindings...),
+		enter,
+		helpBinding{"a", "actions for the selected row"},
+		helpBinding{"n", "new worktree"},
+		helpBinding{"s", "open a shell in the worktree"},
+		helpBinding{"e", "edit the worktree note"},
+		helpBinding{"L", "label your lock"},
+		helpBinding{"T", "label this tab's title"},
+		helpBinding{"space", "mark the worktree"},
+		helpBinding{"o", "open marked worktrees in tmux windows"},
+		helpBinding{"esc", "clear marks"},
+		helpBinding{"d", "delete the worktree"},
+		helpBinding{"u", "unlock the worktree"},
+		helpBinding{"p", "open the PR"},
+		helpBinding{"P", "copy the PR URL"},
+		helpBinding{"b", "open the compare view"},
+		helpBinding{"v", "open the newest unresolved comment"},
+		helpBinding{"w", "re-request review"},
+		helpBinding{"c", "re-run failed checks"},
+		helpBinding{"C", "open the failing run"},
+		helpBinding{"t", "track the remote branch"},
+		helpBinding{"R", "reset to the remote branch"},
+		helpBinding{"f", "refresh this PR"},
+		helpBinding{"r", "refresh everything"},
+		helpBinding{"g", "group by PR status"},
+		helpBinding{"A", "toggle absolute/relative paths"},
+		helpBinding{"q", "quit"},
+	)
+}
+
+func (m model) openHelpBindings() (string, []helpBinding) {
+	refresh := []helpBinding{
+		{"ctrl+r", "refresh"},
+		{"ctrl+l", "refresh clean/dirty only"},
+	}
+	if m.openShowDebug {
+		bindings := append([]helpBinding{}, helpNavBindings...)
+		bindings = append(bindings,
+			helpBinding{"d", "delete the worktree"},
+			helpBinding{"a", "archive the worktree, keeping its branch"},
+			helpBinding{"x", "discard uncommitted changes"},
+			helpBinding{"u", "unlock the worktree"},
+			helpBinding{"n", "new worktree"},
+			helpBinding{"R", "repair worktree links"},
+			helpBinding{"A", "toggle absolute/relative paths"},
+		)
+		bindings = append(bindings, refresh...)
+		return "Worktree debug", append(bindings,
+			helpBinding{"esc, ctrl+d", "back"},
+			helpBinding{"q", "quit"},
+		)
+	}
+	switch m.openStage {
+	case openStageNewBranchConfig:
+		return "New branch", []helpBinding{
+			{"tab", "complete a branch name, or next field"},
+			{"up/down", "move between fields"},
+			{"ctrl+b", "pick the base ref from remote branches"},
+			{"ctrl+g", "name the branch after a GitHub issue"},
+			{"enter", "create the worktree"},
+			{"esc", "back"},
+		}
+	case openStagePickBaseRef:
+		return "Pick base ref", []helpBinding{
+			{"type", "filter refs"},
+			{"up/down", "move the selection"},
+			{"enter", "use the selected or typed ref"},
+			{"esc", "back to the new branch form"},
+		}
+	case openStagePickWorktree:
+		bindings := append([]helpBinding{}, helpNavBindings...)
+		bindings = append(bindings, helpBinding{"enter", "open the branch in the selected worktree"})
+		bindings = append(bindings, refresh...)
+		return "Choose a worktree", append(bindings, helpBinding{"esc", "back"})
+	}
+	bindings := []helpBinding{
+		{"up/down", "move the selection"},
+		{"type", "search by branch or PR"},
+		{"enter", "open the selected branch, or create a new one"},
+		{"ctrl+y", "copy the git worktree add command"},
+	}
+	bindings = append(bindings, refresh...)
+	return "Open a branch", append(bindings,
+		helpBinding{"ctrl+d", "worktree debug view"},
+		helpBinding{"q", "quit"},
+	)
+}
+
+func (m model) renderHelpOverlay() string {
+	title, bindings := m.helpBindings()
+	bindings = append(bindings, helpCloseBinding)
+	keyWidth := 0
+	for _, binding := range bindings {
+		keyWidth = max(keyWidth, lipgloss.Width(binding.key))
+	}
+	var b strings.Builder
+	b.WriteString(selectorHeaderStyle.Render(title + " keys"))
+	b.WriteString("\n\n")
+	for _, binding := range bindings {
+		b.WriteString(branchInlineStyle.Render(fmt.Sprintf("%-*s", keyWidth, binding.key)))
+		b.WriteString("  ")
+		b.WriteString(binding.desc)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(secondaryStyle.Render("Press ? or esc to close."))
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(currentTheme().Accent).
+		Padding(1, 2).
+		Render(b.String())
+	if m.width <= 0 || m.height <= 0 {
+		return box + "\n"
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -148,10 +148,11 @@ func (m dirPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m dirPickerModel) View() string {
 	var b strings.Builder
 
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	theme := currentTheme()
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	selectedStyle := theme.selectedStyle()
+	normalStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Dim)
 
 	b.WriteString(titleStyle.Render("Open in \"" + m.ideCmd + "\""))
 	b.WriteString("\n")
@@ -34,7 +34,7 @@ func newConfigModel() configModel {
 	inputs := make([]textinput.Model, fieldCount)
 
 	var cfg Config
-	if loaded, err := LoadConfig(); err == nil {
+	if loaded, err := loadGlobalConfig(); err == nil {
 		cfg = loaded
 	}
 
@@ -155,13 +155,15 @@ func (m configModel) save() error {
 
 	ide := strings.TrimSpace(m.inputs[fieldIDECommand].Value())
 
-	cfg := Config{
-		AgentCommand:          agent,
-		NewBranchBaseRef:      branch,
-		NewBranchFetchFirst:   &m.fetchToggle,
-		IDECommand:            ide,
-		MainScreenBranchLimit: branchLimit,
+	cfg, err := loadGlobalConfig()
+	if err != nil {
+		cfg = Config{}
 	}
+	cfg.AgentCommand = agent
+	cfg.NewBranchBaseRef = branch
+	cfg.NewBranchFetchFirst = &m.fetchToggle
+	cfg.IDECommand = ide
+	cfg.MainScreenBranchLimit = branchLimit
 	return SaveConfig(cfg)
 }
 
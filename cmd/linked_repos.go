@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configuredLinkedRepos resolves linked_repos to repository roots, dropping
+// entries that aren't repos, repeat entries, and the current repo itself.
+func configuredLinkedRepos(currentRoot string) []string {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil
+	}
+	roots := make([]string, 0, len(cfg.LinkedRepos))
+	for _, entry := range cfg.LinkedRepos {
+		dir := strings.TrimSpace(entry)
+		if dir == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, rest)
+			}
+		}
+		root, err := repoRootForDir(dir, "")
+		if err != nil {
+			logError("linked repo skipped", "path", entry, "err", err)
+			continue
+		}
+		duplicate := sameRealPath(root, currentRoot)
+		for _, seen := range roots {
+			duplicate = duplicate || sameRealPath(root, seen)
+		}
+		if !duplicate {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// StatusWithLinked is Status plus the worktrees of every linked repo, each
+// tagged with its repository root. Orphaned linked worktrees are left out;
+// they can only be cleaned up from their own repo.
+func (o *WorktreeOrchestrator) StatusWithLinked() WorktreeStatus {
+	status := o.Status()
+	if status.Err != nil || !status.InRepo || o.lockMgr == nil {
+		return status
+	}
+	for _, root := range configuredLinkedRepos(status.RepoRoot) {
+		linked := NewWorktreeOrchestrator(NewWorktreeManager(root, o.lockMgr), o.lockMgr, o.prMgr).Status()
+		if linked.Err != nil || !linked.InRepo {
+			logError("linked repo status failed", "repo", root, "err", linked.Err)
+			continue
+		}
+		for _, wt := range linked.Worktrees {
+			if isOrphanedPath(linked, wt.Path) {
+				continue
+			}
+			wt.LinkedRepo = linked.RepoRoot
+			status.Linked = append(status.Linked, wt)
+		}
+	}
+	return status
+}
+
+// prDataKey keys a worktree's PR data by branch, prefixed with the repo root
+// for linked rows so same-named branches in different repos don't collide.
+func prDataKey(wt WorktreeInfo) string {
+	branch := strings.TrimSpace(wt.Branch)
+	if wt.LinkedRepo == "" || branch == "" {
+		return branch
+	}
+	return wt.LinkedRepo + "\x00" + branch
+}
+
+// worktreeRepoRoot is the repository wt belongs to.
+func worktreeRepoRoot(status WorktreeStatus, wt WorktreeInfo) string {
+	if wt.LinkedRepo != "" {
+		return wt.LinkedRepo
+	}
+	return status.RepoRoot
+}
+
+// linkedRowKey reports whether key works on a linked repo's row; the rest
+// change worktree state and are left to a wtx running in that repo.
+func linkedRowKey(key string) bool {
+	switch key {
+	case "q", "ctrl+c", "r", "esc", "g", "A", "up", "k", "down", "j", "enter", "a", "n", "s", "p", "P", "v", "C", "T":
+		return true
+	}
+	return false
+}
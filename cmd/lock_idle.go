@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const lockIdleMaxWarnLead = time.Minute
+
+// enforceLockIdle applies lock_idle_release_seconds to the worktree. It runs
+// on every tmux-status tick, which tmux drives periodically, whatever the
+// status format shows. Idle time is wall time since the last keypress from any
+// client attached to the lock owner's session. It returns a status-line label
+// while a warning or release applies.
+func enforceLockIdle(worktreePath string) string {
+	cfg, err := loadConfigForDir(worktreePath)
+	if err != nil || cfg.LockIdleReleaseSeconds <= 0 {
+		return ""
+	}
+	repoRoot, err := repoRootForDir(worktreePath, "")
+	if err != nil {
+		return ""
+	}
+	lockPath, err := NewLockManager().lockPath(repoRoot, worktreePath)
+	if err != nil {
+		return ""
+	}
+	payload, err := readLockPayload(lockPath)
+	if err != nil {
+		return ""
+	}
+	sessionID, _, ok := parseTmuxOwnerID(payload.OwnerID)
+	if !ok {
+		return ""
+	}
+	out, err := exec.Command("tmux", "list-clients", "-t", sessionID, "-F", "#{client_activity}").Output()
+	if err != nil {
+		return ""
+	}
+	idle, ok := idleFromClientActivity(string(out), time.Now())
+	if !ok {
+		return ""
+	}
+	window := time.Duration(cfg.LockIdleReleaseSeconds) * time.Second
+	label, release := lockIdleDecision(idle, window, cfg.LockIdleWarnOnly)
+	if release {
+		// Only drop the lock if it still belongs to the same owner/pid.
+		removed, err := removeLockIfHeldBy(lockPath, payload.OwnerID, payload.PID)
+		if err != nil {
+			return ""
+		}
+		if removed {
+			_ = writeWorktreeLastUsed(repoRoot, worktreePath)
+			logInfo("released idle worktree lock", "worktree", worktreePath, "idle", idle.Round(time.Second))
+		}
+	}
+	return label
+}
+
+// idleFromClientActivity parses `tmux list-clients -F '#{client_activity}'`
+// and returns the time since the most recent keypress across clients.
+func idleFromClientActivity(out string, now time.Time) (time.Duration, bool) {
+	latest := int64(0)
+	for _, line := range strings.Split(out, "\n") {
+		value, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		if value > latest {
+			latest = value
+		}
+	}
+	if latest == 0 {
+		return 0, false
+	}
+	idle := now.Sub(time.Unix(latest, 0))
+	if idle < 0 {
+		idle = 0
+	}
+	return idle, true
+}
+
+// lockIdleDecision warns during the last stretch of the idle window (a
+// quarter of it, at most a minute) and releases once the window has passed,
+// unless warnOnly is set.
+func lockIdleDecision(idle time.Duration, window time.Duration, warnOnly bool) (string, bool) {
+	if window <= 0 {
+		return "", false
+	}
+	if warnOnly {
+		if idle < window {
+			return "", false
+		}
+		return fmt.Sprintf("idle %s; run wtx locks to free this worktree", formatIdleDuration(idle)), false
+	}
+	if idle >= window {
+		return fmt.Sprintf("idle %s; lock released", formatIdleDuration(idle)), true
+	}
+	lead := window / 4
+	if lead > lockIdleMaxWarnLead {
+		lead = lockIdleMaxWarnLead
+	}
+	if remaining := window - idle; remaining <= lead {
+		return fmt.Sprintf("idle; releasing lock in %s", formatIdleDuration(remaining)), false
+	}
+	return "", false
+}
+
+func formatIdleDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+	return fmt.Sprintf("%ds", int(d.Round(time.Second)/time.Second))
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleFromClientActivity(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	idle, ok := idleFromClientActivity("999000\n999900\n\n", now)
+	if !ok || idle != 100*time.Second {
+		t.Fatalf("expected 100s idle from the latest client, got %s (%v)", idle, ok)
+	}
+	if _, ok := idleFromClientActivity("", now); ok {
+		t.Fatalf("expected no idle time without clients")
+	}
+}
+
+func TestLockIdleDecision(t *testing.T) {
+	window := 10 * time.Minute
+	tests := []struct {
+		name        string
+		idle        time.Duration
+		warnOnly    bool
+		wantLabel   string
+		wantRelease bool
+	}{
+		{name: "active", idle: 2 * time.Minute},
+		{name: "final minute warns", idle: 9*time.Minute + 30*time.Second, wantLabel: "idle; releasing lock in 30s"},
+		{name: "window passed releases", idle: 12 * time.Minute, wantLabel: "idle 12m; lock released", wantRelease: true},
+		{name: "warn only keeps lock", idle: 12 * time.Minute, warnOnly: true, wantLabel: "idle 12m; run wtx locks to free this worktree"},
+		{name: "warn only before window", idle: 9*time.Minute + 30*time.Second, warnOnly: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, release := lockIdleDecision(tt.idle, window, tt.warnOnly)
+			if label != tt.wantLabel || release != tt.wantRelease {
+				t.Fatalf("got (%q, %v), want (%q, %v)", label, release, tt.wantLabel, tt.wantRelease)
+			}
+		})
+	}
+}
@@ -32,8 +32,24 @@ type WorktreeLock struct {
 	repoRoot     string
 	ownerID      string
 	pid          int
+	label        string
+	// observer locks sit beside the worktree's writer lock; see AcquireObserver.
+	observer bool
 }
 
+// lockLabel is set by --label and stored in every lock this invocation takes
+// so teammates can see what a held worktree is for.
+var lockLabel string
+
+// lockObserve is set by --observe: opening a worktree another agent holds
+// launches this agent under an observer lock instead of refusing. Every other
+// lock this invocation takes is still a writer lock.
+var lockObserve bool
+
+// beforeLockTakeoverFn runs between the stale check and the rename; tests use
+// it to widen the race window.
+var beforeLockTakeoverFn = func() {}
+
 var (
 	ownerIDOnce   sync.Once
 	cachedOwnerID string
@@ -69,7 +85,8 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 	}
 
 	ownerID := buildOwnerID()
-	payload, err := lockPayload(repoRoot, worktreePath, ownerID, pid)
+	label := strings.TrimSpace(lockLabel)
+	payload, err := lockPayload(repoRoot, worktreePath, ownerID, pid, label)
 	if err != nil {
 		return nil, err
 	}
@@ -83,32 +100,33 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 		}
 		_ = file.Close()
 		_ = writeWorktreeLastUsed(repoRoot, worktreePath)
-		return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid}, nil
+		return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid, label: label}, nil
 	}
 	if !errors.Is(err, os.ErrExist) {
 		return nil, err
 	}
 
+	// Hold the guard from the stale check through the rename so two acquirers
+	// that both saw the same stale lock can't each replace it.
+	release, err := lockTakeoverGuard(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	info, statErr := os.Stat(lockPath)
 	if statErr != nil {
 		return nil, statErr
 	}
 	current, readErr := readLockPayload(lockPath)
-	ownerActive := false
-	if readErr == nil {
-		ownerActive = lockOwnerStillActive(current.OwnerID, current.PID)
-	}
-	if readErr == nil && ownerActive {
-		if current.OwnerID != ownerID {
-			return nil, errors.New("worktree locked")
-		}
+	if readErr == nil && current.OwnerID != ownerID && m.lockStillHeld(current, info.ModTime(), time.Now()) {
+		return nil, errors.New("worktree locked")
 	}
-	if time.Since(info.ModTime()) < m.staleAfter {
-		if readErr != nil || (ownerActive && current.OwnerID != ownerID) {
-			return nil, errors.New("worktree locked")
-		}
+	if readErr != nil && time.Since(info.ModTime()) < m.staleAfter {
+		return nil, errors.New("worktree locked")
 	}
 
+	beforeLockTakeoverFn()
 	tmpPath := lockPath + "." + randomToken() + ".tmp"
 	if err := os.WriteFile(tmpPath, payload, 0o644); err != nil {
 		return nil, err
@@ -126,7 +144,103 @@ func (m *LockManager) acquireWithPID(repoRoot string, worktreePath string, pid i
 		return nil, errors.New("worktree locked")
 	}
 	_ = writeWorktreeLastUsed(repoRoot, worktreePath)
-	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid}, nil
+	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid, label: label}, nil
+}
+
+// AcquireObserver adds an observer lock on the worktree. Observers share the
+// worktree with its writer and with each other, and leave its last-used stamp
+// alone; each gets its own file, so none of them contend.
+func (m *LockManager) AcquireObserver(repoRoot string, worktreePath string) (*WorktreeLock, error) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	if repoRoot == "" {
+		return nil, errors.New("repo root required")
+	}
+	if worktreePath == "" {
+		return nil, errors.New("worktree path required")
+	}
+	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	dir := observerLockDir(lockPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	m.reapObservers(dir)
+	pid := os.Getpid()
+	ownerID := buildOwnerID()
+	label := strings.TrimSpace(lockLabel)
+	payload, err := lockPayload(repoRoot, worktreePath, ownerID, pid, label)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, randomToken()+".lock")
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		return nil, err
+	}
+	return &WorktreeLock{path: path, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid, label: label, observer: true}, nil
+}
+
+func observerLockDir(lockPath string) string {
+	return strings.TrimSuffix(lockPath, ".lock") + ".observers"
+}
+
+// reapObservers removes observer locks in dir whose process has died on this
+// host. Nothing else cleans them up after a crash, since no writer ever
+// contends for them.
+func (m *LockManager) reapObservers(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lock") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		payload, err := readLockPayload(path)
+		if err != nil {
+			if now.Sub(info.ModTime()) >= m.staleAfter {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+		if payload.PID > 0 && strings.TrimSpace(payload.Host) == lockHostname() && !pidAlive(payload.PID) {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// lockTakeoverGuard takes an exclusive flock on a sidecar of lockPath. The
+// kernel drops it if wtx dies, so unlike the lock file it never goes stale;
+// the sidecar is left in place because removing it would split waiters
+// across two inodes.
+func lockTakeoverGuard(lockPath string) (func(), error) {
+	return flockSidecar(lockPath + ".guard")
+}
+
+// flockSidecar takes an exclusive flock on path, creating it if needed, and
+// returns the unlock func.
+func flockSidecar(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fd := int(f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(fd, syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
 }
 
 func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, error) {
@@ -152,13 +266,7 @@ func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, e
 		if payload.OwnerID == buildOwnerID() {
 			return true, nil
 		}
-		if lockOwnerStillActive(payload.OwnerID, payload.PID) {
-			return false, nil
-		}
-		if time.Since(info.ModTime()) < m.staleAfter && payload.OwnerID != buildOwnerID() {
-			return false, nil
-		}
-		return true, nil
+		return !m.lockStillHeld(payload, info.ModTime(), time.Now()), nil
 	}
 	if errors.Is(err, os.ErrNotExist) {
 		return true, nil
@@ -166,12 +274,229 @@ func (m *LockManager) IsAvailable(repoRoot string, worktreePath string) (bool, e
 	return false, err
 }
 
+// OwnedByCurrentSession reports whether the worktree's live lock belongs to
+// this terminal or tmux session, i.e. it's the caller's own running agent.
+func (m *LockManager) OwnedByCurrentSession(repoRoot string, worktreePath string) bool {
+	lockPath, err := m.lockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return false
+	}
+	payload, err := readLockPayload(lockPath)
+	if err != nil || !lockOwnerStillActive(payload.OwnerID, payload.PID) {
+		return false
+	}
+	return lockOwnedBySession(payload, buildOwnerID(), os.Getpid())
+}
+
+// HeldByCurrentOwner reports whether the worktree's live lock was taken by
+// this exact owner (window or terminal) or process. Unlike
+// OwnedByCurrentSession, another window of the same tmux session doesn't count.
+func (m *LockManager) HeldByCurrentOwner(repoRoot string, worktreePath string) bool {
+	lockPath, err := m.lockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return false
+	}
+	payload, err := readLockPayload(lockPath)
+	if err != nil || !lockOwnerStillActive(payload.OwnerID, payload.PID) {
+		return false
+	}
+	return lockHeldBy(payload, buildOwnerID(), os.Getpid())
+}
+
+// Label returns the label stored in the worktree's lock, if any.
+func (m *LockManager) Label(repoRoot string, worktreePath string) string {
+	lockPath, err := m.lockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return ""
+	}
+	payload, err := readLockPayload(lockPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(payload.Label)
+}
+
+// SetLabel rewrites the label on a lock this owner holds; an empty label
+// clears it. Other fields, including ones a newer wtx wrote, are kept.
+func (m *LockManager) SetLabel(repoRoot string, worktreePath string, label string) error {
+	lockPath, err := m.lockPath(strings.TrimSpace(repoRoot), strings.TrimSpace(worktreePath))
+	if err != nil {
+		return err
+	}
+	current, err := readLockPayload(lockPath)
+	if err != nil || !lockHeldBy(current, buildOwnerID(), os.Getpid()) {
+		return errors.New("worktree is not locked by this window")
+	}
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if label = strings.TrimSpace(label); label != "" {
+		raw["label"] = label
+	} else {
+		delete(raw, "label")
+	}
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	tmpPath := lockPath + "." + randomToken() + ".tmp"
+	if err := os.WriteFile(tmpPath, payload, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, lockPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// lockHeldBy reports whether payload was written by ownerID or by pid.
+func lockHeldBy(payload lockPayloadData, ownerID string, pid int) bool {
+	return payload.OwnerID == ownerID || (pid > 0 && payload.PID == pid)
+}
+
+// lockOwnedBySession is lockHeldBy widened to any window of the same tmux
+// session, for marking rows as yours.
+func lockOwnedBySession(payload lockPayloadData, ownerID string, pid int) bool {
+	if lockHeldBy(payload, ownerID, pid) {
+		return true
+	}
+	lockSession, _, ok := parseTmuxOwnerID(payload.OwnerID)
+	if !ok {
+		return false
+	}
+	session, _, ok := parseTmuxOwnerID(ownerID)
+	return ok && session == lockSession
+}
+
+// lockStillHeld reports whether another owner still holds the lock. Same-host
+// locks trust PID liveness; the touch time is only a fallback when there is no
+// PID, and comes from the payload since the lock file's mtime is stamped by a
+// possibly skewed file server. Locks from other hosts can't be PID-checked, so
+// they count as held while either the mtime or the owner's timestamp is recent.
+func (m *LockManager) lockStillHeld(payload lockPayloadData, modTime time.Time, now time.Time) bool {
+	host := strings.TrimSpace(payload.Host)
+	touched, touchedErr := time.Parse(time.RFC3339Nano, strings.TrimSpace(payload.Timestamp))
+	switch {
+	case host == "":
+		// Written before locks recorded their host.
+		return lockOwnerStillActive(payload.OwnerID, payload.PID) || now.Sub(modTime) < m.staleAfter
+	case host == lockHostname():
+		if lockOwnerStillActive(payload.OwnerID, payload.PID) {
+			return true
+		}
+		if payload.PID > 0 || touchedErr != nil {
+			return false
+		}
+		return now.Sub(touched) < m.staleAfter
+	default:
+		if now.Sub(modTime) < m.staleAfter {
+			return true
+		}
+		return touchedErr == nil && now.Sub(touched) < m.staleAfter
+	}
+}
+
+func lockHostname() string {
+	host, _ := os.Hostname()
+	return strings.TrimSpace(host)
+}
+
+// TakeOver moves the worktree's writer lock from fromOwnerID/fromPID, the wtx
+// that handed it over, to this process. It fails if the lock has changed
+// hands since, so a handoff never steals someone else's lock.
+func (m *LockManager) TakeOver(repoRoot string, worktreePath string, fromOwnerID string, fromPID int) (*WorktreeLock, error) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	if repoRoot == "" {
+		return nil, errors.New("repo root required")
+	}
+	if worktreePath == "" {
+		return nil, errors.New("worktree path required")
+	}
+	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	release, err := lockTakeoverGuard(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	current, err := readLockPayload(lockPath)
+	if err != nil || current.OwnerID != fromOwnerID || current.PID != fromPID {
+		return nil, errors.New("worktree lock was not handed over")
+	}
+	ownerID := buildOwnerID()
+	pid := os.Getpid()
+	label := strings.TrimSpace(current.Label)
+	payload, err := lockPayload(repoRoot, worktreePath, ownerID, pid, label)
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := lockPath + "." + randomToken() + ".tmp"
+	if err := os.WriteFile(tmpPath, payload, 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, lockPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	_ = writeWorktreeLastUsed(repoRoot, worktreePath)
+	return &WorktreeLock{path: lockPath, worktreePath: worktreePath, repoRoot: repoRoot, ownerID: ownerID, pid: pid, label: label}, nil
+}
+
+// Observer reports whether l is an observer lock rather than the worktree's
+// writer lock.
+func (l *WorktreeLock) Observer() bool {
+	return l != nil && l.observer
+}
+
+// Release removes the lock file if it is still this lock's. After an idle
+// release or a takeover the file belongs to someone else and is left alone.
 func (l *WorktreeLock) Release() {
 	if l == nil {
 		return
 	}
-	_ = writeWorktreeLastUsed(l.repoRoot, l.worktreePath)
-	_ = os.Remove(l.path)
+	if l.observer {
+		if current, err := readLockPayload(l.path); err == nil && current.OwnerID == l.ownerID && current.PID == l.pid {
+			_ = os.Remove(l.path)
+		}
+		return
+	}
+	if removed, _ := removeLockIfHeldBy(l.path, l.ownerID, l.pid); removed {
+		_ = writeWorktreeLastUsed(l.repoRoot, l.worktreePath)
+	}
+}
+
+// removeLockIfHeldBy removes the writer lock at lockPath if its payload still
+// names ownerID and pid. It holds the takeover guard so the file can't change
+// hands between the check and the remove.
+func removeLockIfHeldBy(lockPath string, ownerID string, pid int) (bool, error) {
+	release, err := lockTakeoverGuard(lockPath)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	current, err := readLockPayload(lockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if current.OwnerID != ownerID || current.PID != pid {
+		return false, nil
+	}
+	if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
 }
 
 func (m *LockManager) ForceUnlock(repoRoot string, worktreePath string) error {
@@ -220,6 +545,41 @@ func (m *LockManager) ReleaseIfOwned(repoRoot string, worktreePath string) error
 	return nil
 }
 
+// ReleaseObserver drops this session's observer locks on the worktree that
+// are bound to pid, leaving the writer lock and other observers alone.
+func (m *LockManager) ReleaseObserver(repoRoot string, worktreePath string, pid int) error {
+	repoRoot = strings.TrimSpace(repoRoot)
+	worktreePath = strings.TrimSpace(worktreePath)
+	if repoRoot == "" || worktreePath == "" {
+		return nil
+	}
+	lockPath, err := m.lockPath(repoRoot, worktreePath)
+	if err != nil {
+		return err
+	}
+	dir := observerLockDir(lockPath)
+	m.reapObservers(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	ownerID := buildOwnerID()
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		payload, err := readLockPayload(path)
+		if err != nil || payload.OwnerID != ownerID || payload.PID != pid {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (l *WorktreeLock) RebindPID(pid int) error {
 	if l == nil {
 		return errors.New("lock required")
@@ -234,7 +594,10 @@ func (l *WorktreeLock) RebindPID(pid int) error {
 	if current.OwnerID != l.ownerID || current.PID != l.pid {
 		return errors.New("lock ownership lost")
 	}
-	payload, err := lockPayload(l.repoRoot, l.worktreePath, l.ownerID, pid)
+	// Keep the label as it is on disk; SetLabel may have changed it since
+	// this lock was taken.
+	label := strings.TrimSpace(current.Label)
+	payload, err := lockPayload(l.repoRoot, l.worktreePath, l.ownerID, pid, label)
 	if err != nil {
 		return err
 	}
@@ -246,8 +609,11 @@ func (l *WorktreeLock) RebindPID(pid int) error {
 		_ = os.Remove(tmpPath)
 		return err
 	}
-	_ = writeWorktreeLastUsed(l.repoRoot, l.worktreePath)
+	if !l.observer {
+		_ = writeWorktreeLastUsed(l.repoRoot, l.worktreePath)
+	}
 	l.pid = pid
+	l.label = label
 	return nil
 }
 
@@ -256,18 +622,17 @@ func (m *LockManager) lockPath(repoRoot string, worktreePath string) (string, er
 	if err != nil {
 		return "", err
 	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	lockDir, err := locksDir()
+	if err != nil {
+		return "", err
 	}
-	lockDir := filepath.Join(home, ".wtx", "locks")
 	return filepath.Join(lockDir, worktreeID+".lock"), nil
 }
 
 func worktreeID(repoRoot string, worktreePath string) (string, error) {
 	repoIDRoot := repoRoot
 	if gitPath, err := gitPath(); err == nil {
-		commonDir, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+		commonDir, err := gitCommonDir(repoRoot, gitPath)
 		if err == nil && strings.TrimSpace(commonDir) != "" {
 			repoIDRoot = commonDir
 		}
@@ -321,11 +686,19 @@ func writeWorktreeLastUsed(repoRoot string, worktreePath string) error {
 	return os.WriteFile(path, []byte(timestamp+"\n"), 0o644)
 }
 
+// worktreeLastUsedUnix returns the last-used stamp in Unix nanoseconds,
+// preferring the RFC3339 time written into the file over its mtime, which
+// copies and backups don't preserve.
 func worktreeLastUsedUnix(repoRoot string, worktreePath string) int64 {
 	path, err := worktreeLastUsedPath(repoRoot, worktreePath)
 	if err != nil {
 		return 0
 	}
+	if data, err := os.ReadFile(path); err == nil {
+		if ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data))); err == nil {
+			return ts.UnixNano()
+		}
+	}
 	info, err := os.Stat(path)
 	if err != nil {
 		return 0
@@ -333,16 +706,29 @@ func worktreeLastUsedUnix(repoRoot string, worktreePath string) int64 {
 	return info.ModTime().UnixNano()
 }
 
+// formatLastUsed renders a last-used stamp as "3h ago", or as an exact local
+// time when show_exact_times is set.
+func formatLastUsed(unixNano int64, now time.Time, exact bool) string {
+	if unixNano <= 0 {
+		return ""
+	}
+	ts := time.Unix(0, unixNano)
+	if exact {
+		return ts.Local().Format("2006-01-02 15:04:05 MST")
+	}
+	return formatLockAge(now.Sub(ts)) + " ago"
+}
+
 func worktreeLastUsedPath(repoRoot string, worktreePath string) (string, error) {
 	worktreeID, err := worktreeID(repoRoot, worktreePath)
 	if err != nil {
 		return "", err
 	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	home, err := wtxHomeDir()
+	if err != nil {
+		return "", err
 	}
-	lastUsedDir := filepath.Join(home, ".wtx", "last_used")
+	lastUsedDir := filepath.Join(home, "last_used")
 	return filepath.Join(lastUsedDir, worktreeID), nil
 }
 
@@ -397,17 +783,100 @@ func computeOwnerID() string {
 }
 
 type lockPayloadData struct {
-	OwnerID string `json:"owner_id"`
-	PID     int    `json:"pid"`
+	OwnerID      string `json:"owner_id"`
+	PID          int    `json:"pid"`
+	WorktreePath string `json:"worktree_path"`
+	RepoRoot     string `json:"repo_root"`
+	Timestamp    string `json:"timestamp"`
+	// Host is the owning machine; its PID is only meaningful there.
+	Host string `json:"host,omitempty"`
+	// Label is an optional note on what the lock is for; older locks have none.
+	Label string `json:"label,omitempty"`
+}
+
+type lockFileEntry struct {
+	Path     string
+	Payload  lockPayloadData
+	Err      error
+	Observer bool
+}
+
+func locksDir() (string, error) {
+	home, err := wtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "locks"), nil
+}
+
+func listLockFiles() ([]lockFileEntry, error) {
+	dir, err := locksDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out := make([]lockFileEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".observers") {
+			observers, _ := os.ReadDir(filepath.Join(dir, entry.Name()))
+			for _, observer := range observers {
+				if observer.IsDir() || !strings.HasSuffix(observer.Name(), ".lock") {
+					continue
+				}
+				path := filepath.Join(dir, entry.Name(), observer.Name())
+				payload, err := readLockPayload(path)
+				out = append(out, lockFileEntry{Path: path, Payload: payload, Err: err, Observer: true})
+			}
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lock") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		payload, err := readLockPayload(path)
+		out = append(out, lockFileEntry{Path: path, Payload: payload, Err: err})
+	}
+	return out, nil
 }
 
-func lockPayload(repoRoot string, worktreePath string, ownerID string, pid int) ([]byte, error) {
+// lockOwnerIsCurrentUser rejects locks written by another user or host that
+// share this HOME; session-scoped owner IDs are always local to this user.
+func lockOwnerIsCurrentUser(ownerID string) bool {
+	ownerID = strings.TrimSpace(ownerID)
+	if ownerID == "" || ownerID == buildOwnerID() {
+		return true
+	}
+	userHost, _, ok := strings.Cut(ownerID, ":")
+	if !ok || !strings.Contains(userHost, "@") {
+		return true
+	}
+	name := os.Getenv("USER")
+	if name == "" {
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+		}
+	}
+	host, _ := os.Hostname()
+	return userHost == name+"@"+host
+}
+
+func lockPayload(repoRoot string, worktreePath string, ownerID string, pid int, label string) ([]byte, error) {
 	data := map[string]any{
 		"pid":           pid,
 		"owner_id":      ownerID,
 		"worktree_path": worktreePath,
 		"repo_root":     repoRoot,
 		"timestamp":     time.Now().UTC().Format(time.RFC3339Nano),
+		"host":          lockHostname(),
+	}
+	if label != "" {
+		data["label"] = label
 	}
 	return json.Marshal(data)
 }
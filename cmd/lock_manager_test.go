@@ -1,6 +1,15 @@
 package cmd
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestParseTmuxOwnerID(t *testing.T) {
 	t.Run("session and window", func(t *testing.T) {
@@ -44,3 +53,418 @@ func TestLockOwnerStillActive_UnknownOwnerWithoutPID(t *testing.T) {
 		t.Fatalf("expected empty owner without pid to be inactive")
 	}
 }
+
+func TestLockStillHeld_ToleratesSkewedMtimes(t *testing.T) {
+	m := NewLockManager()
+	now := time.Now()
+	stamp := func(d time.Duration) string { return now.Add(d).UTC().Format(time.RFC3339Nano) }
+	deadPID := 1 << 30
+	cases := []struct {
+		name    string
+		payload lockPayloadData
+		modTime time.Time
+		want    bool
+	}{
+		{
+			name:    "same host live pid with mtime skewed into the past",
+			payload: lockPayloadData{Host: lockHostname(), PID: os.Getpid(), Timestamp: stamp(0)},
+			modTime: now.Add(-time.Hour),
+			want:    true,
+		},
+		{
+			name:    "same host dead pid with mtime skewed into the future",
+			payload: lockPayloadData{Host: lockHostname(), PID: deadPID, Timestamp: stamp(0)},
+			modTime: now.Add(time.Hour),
+			want:    false,
+		},
+		{
+			name:    "same host without pid falls back to payload timestamp",
+			payload: lockPayloadData{Host: lockHostname(), Timestamp: stamp(-time.Second)},
+			modTime: now.Add(-time.Hour),
+			want:    true,
+		},
+		{
+			name:    "other host with file server behind but fresh payload",
+			payload: lockPayloadData{Host: "other-host", PID: os.Getpid(), Timestamp: stamp(-time.Second)},
+			modTime: now.Add(-time.Hour),
+			want:    true,
+		},
+		{
+			name:    "other host ignores local pid liveness once stale",
+			payload: lockPayloadData{Host: "other-host", PID: os.Getpid(), Timestamp: stamp(-time.Hour)},
+			modTime: now.Add(-time.Hour),
+			want:    false,
+		},
+		{
+			name:    "legacy payload without host keeps mtime check",
+			payload: lockPayloadData{PID: deadPID},
+			modTime: now.Add(-time.Second),
+			want:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := m.lockStillHeld(tc.payload, tc.modTime, now); got != tc.want {
+				t.Fatalf("expected held=%v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLockOwnedBySession(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload lockPayloadData
+		ownerID string
+		pid     int
+		want    bool
+	}{
+		{name: "same owner", payload: lockPayloadData{OwnerID: "term-session:a", PID: 10}, ownerID: "term-session:a", pid: 20, want: true},
+		{name: "same pid", payload: lockPayloadData{OwnerID: "term-session:b", PID: 20}, ownerID: "term-session:a", pid: 20, want: true},
+		{name: "other window of same tmux session", payload: lockPayloadData{OwnerID: "tmux:$1:@3", PID: 10}, ownerID: "tmux:$1:@2", pid: 20, want: true},
+		{name: "other tmux session", payload: lockPayloadData{OwnerID: "tmux:$2:@3", PID: 10}, ownerID: "tmux:$1:@2", pid: 20, want: false},
+		{name: "other terminal", payload: lockPayloadData{OwnerID: "term-session:b", PID: 10}, ownerID: "term-session:a", pid: 20, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := lockOwnedBySession(tc.payload, tc.ownerID, tc.pid); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+	if lockHeldBy(lockPayloadData{OwnerID: "tmux:$1:@3", PID: 10}, "tmux:$1:@2", 20) {
+		t.Fatalf("expected another window of the same tmux session not to hold the lock")
+	}
+}
+
+const lockRaceHelperEnv = "WTX_LOCK_RACE_HELPER"
+
+// TestLockRaceHelper is run in child processes by
+// TestAcquire_ConcurrentStaleTakeoverHasOneWinner.
+func TestLockRaceHelper(t *testing.T) {
+	if os.Getenv(lockRaceHelperEnv) != "1" {
+		t.Skip("helper process only")
+	}
+	start, _ := strconv.ParseInt(os.Getenv("WTX_LOCK_RACE_START"), 10, 64)
+	beforeLockTakeoverFn = func() { time.Sleep(100 * time.Millisecond) }
+	time.Sleep(time.Until(time.Unix(0, start)))
+	lock, err := NewLockManager().Acquire(os.Getenv("WTX_LOCK_RACE_REPO"), os.Getenv("WTX_LOCK_RACE_WORKTREE"))
+	if err != nil {
+		os.Stdout.WriteString("lost\n")
+		return
+	}
+	os.Stdout.WriteString("won\n")
+	// Stay alive until every racer has tried, so the win can't go stale.
+	hold, _ := strconv.ParseInt(os.Getenv("WTX_LOCK_RACE_HOLD"), 10, 64)
+	time.Sleep(time.Until(time.Unix(0, hold)))
+	lock.Release()
+}
+
+func TestAcquire_ConcurrentStaleTakeoverHasOneWinner(t *testing.T) {
+	stateDir := t.TempDir()
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	t.Setenv(stateDirOverrideEnv, stateDir)
+
+	m := NewLockManager()
+	lockPath, err := m.lockPath(repo, worktree)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	stale, err := lockPayload(repo, worktree, "explicit:gone", 1<<30, "")
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if err := os.WriteFile(lockPath, stale, 0o644); err != nil {
+		t.Fatalf("write stale lock: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	const racers = 8
+	start := time.Now().Add(500 * time.Millisecond)
+	hold := start.Add(2 * time.Second)
+	cmds := make([]*exec.Cmd, racers)
+	outs := make([]*strings.Builder, racers)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestLockRaceHelper$")
+		cmd.Env = append(os.Environ(),
+			lockRaceHelperEnv+"=1",
+			"TMUX=",
+			"WTX_OWNER_ID=racer-"+strconv.Itoa(i),
+			"WTX_LOCK_RACE_REPO="+repo,
+			"WTX_LOCK_RACE_WORKTREE="+worktree,
+			"WTX_LOCK_RACE_START="+strconv.FormatInt(start.UnixNano(), 10),
+			"WTX_LOCK_RACE_HOLD="+strconv.FormatInt(hold.UnixNano(), 10),
+		)
+		outs[i] = &strings.Builder{}
+		cmd.Stdout = outs[i]
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("start racer: %v", err)
+		}
+		cmds[i] = cmd
+	}
+	winners := 0
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("racer %d failed: %v\n%s", i, err, outs[i])
+		}
+		if strings.Contains(outs[i].String(), "won") {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("expected exactly one racer to take over the stale lock, got %d", winners)
+	}
+}
+
+func TestLockLabel_StoredAndEditable(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	prev := lockLabel
+	lockLabel = " release prep "
+	t.Cleanup(func() { lockLabel = prev })
+
+	m := NewLockManager()
+	lock, err := m.Acquire(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer lock.Release()
+	if got := m.Label(repo, worktree); got != "release prep" {
+		t.Fatalf("expected label from --label, got %q", got)
+	}
+	if err := lock.RebindPID(os.Getpid()); err != nil {
+		t.Fatalf("rebind: %v", err)
+	}
+	if got := m.Label(repo, worktree); got != "release prep" {
+		t.Fatalf("expected label kept across rebind, got %q", got)
+	}
+	if err := m.SetLabel(repo, worktree, "hotfix"); err != nil {
+		t.Fatalf("set label: %v", err)
+	}
+	if got := m.Label(repo, worktree); got != "hotfix" {
+		t.Fatalf("expected edited label, got %q", got)
+	}
+	if err := m.SetLabel(repo, worktree, ""); err != nil {
+		t.Fatalf("clear label: %v", err)
+	}
+	if got := m.Label(repo, worktree); got != "" {
+		t.Fatalf("expected cleared label, got %q", got)
+	}
+	if err := m.SetLabel(repo, t.TempDir(), "x"); err == nil {
+		t.Fatalf("expected labeling an unheld lock to fail")
+	}
+	if err := m.SetLabel(repo, worktree, "edited"); err != nil {
+		t.Fatalf("set label: %v", err)
+	}
+	if err := lock.RebindPID(os.Getpid()); err != nil {
+		t.Fatalf("rebind: %v", err)
+	}
+	if got := m.Label(repo, worktree); got != "edited" {
+		t.Fatalf("expected rebind to keep the edited label, got %q", got)
+	}
+
+	other := t.TempDir()
+	lockPath, err := m.lockPath(repo, other)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	payload, err := lockPayload(repo, other, buildOwnerID()+"-other-window", os.Getppid(), "")
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if err := os.WriteFile(lockPath, payload, 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	if err := m.SetLabel(repo, other, "mine now"); err == nil {
+		t.Fatalf("expected labeling another owner's lock to fail")
+	}
+}
+
+func TestReadLockPayload_WithoutLabel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.lock")
+	if err := os.WriteFile(path, []byte(`{"owner_id":"a@b:1:x","pid":1,"worktree_path":"/wt"}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	payload, err := readLockPayload(path)
+	if err != nil || payload.Label != "" || payload.WorktreePath != "/wt" {
+		t.Fatalf("expected old lock to read without label, got %+v, %v", payload, err)
+	}
+}
+
+func TestObserverLocks_ShareWorktreeWithWriter(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	m := NewLockManager()
+	writer, err := m.Acquire(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire writer: %v", err)
+	}
+	defer writer.Release()
+
+	first, err := m.AcquireObserver(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire first observer: %v", err)
+	}
+	second, err := m.AcquireObserver(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire second observer: %v", err)
+	}
+	entries, err := listLockFiles()
+	if err != nil {
+		t.Fatalf("list locks: %v", err)
+	}
+	observers := 0
+	for _, entry := range entries {
+		if entry.Observer {
+			observers++
+		}
+	}
+	if len(entries) != 3 || observers != 2 {
+		t.Fatalf("expected one writer and two observers, got %+v", entries)
+	}
+
+	stamp, err := worktreeLastUsedPath(repo, worktree)
+	if err != nil {
+		t.Fatalf("last used path: %v", err)
+	}
+	if err := os.Remove(stamp); err != nil {
+		t.Fatalf("remove stamp: %v", err)
+	}
+	first.Release()
+	if err := m.ReleaseObserver(repo, worktree, os.Getpid()); err != nil {
+		t.Fatalf("release observer: %v", err)
+	}
+	if _, err := os.Stat(second.path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected observer bound to this pid to be released, got %v", err)
+	}
+	if _, err := os.Stat(stamp); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected observers to leave the last-used stamp alone, got %v", err)
+	}
+	if _, err := os.Stat(writer.path); err != nil {
+		t.Fatalf("expected writer lock to stay held: %v", err)
+	}
+}
+
+func TestWorktreeLastUsedUnix_PrefersStampContentsOverMtime(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	wt := t.TempDir()
+	path, err := worktreeLastUsedPath(repo, wt)
+	if err != nil {
+		t.Fatalf("worktreeLastUsedPath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	stamp := time.Date(2024, 3, 1, 12, 30, 15, 0, time.UTC)
+	if err := os.WriteFile(path, []byte(stamp.Format(time.RFC3339Nano)+"\n"), 0o644); err != nil {
+		t.Fatalf("write stamp: %v", err)
+	}
+	if got := worktreeLastUsedUnix(repo, wt); got != stamp.UnixNano() {
+		t.Fatalf("expected stamp contents %d, got %d", stamp.UnixNano(), got)
+	}
+	mtime := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.WriteFile(path, []byte("garbage\n"), 0o644); err != nil {
+		t.Fatalf("write stamp: %v", err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if got := worktreeLastUsedUnix(repo, wt); got != mtime.UnixNano() {
+		t.Fatalf("expected mtime fallback %d, got %d", mtime.UnixNano(), got)
+	}
+
+	now := stamp.Add(3 * time.Hour)
+	if got := formatLastUsed(stamp.UnixNano(), now, false); got != "3h ago" {
+		t.Fatalf("expected relative age, got %q", got)
+	}
+	if got := formatLastUsed(stamp.UnixNano(), now, true); got != stamp.Local().Format("2006-01-02 15:04:05 MST") {
+		t.Fatalf("expected exact time, got %q", got)
+	}
+	if got := formatLastUsed(0, now, true); got != "" {
+		t.Fatalf("expected empty for unknown last use, got %q", got)
+	}
+}
+
+func TestAcquire_ObserveFlagKeepsWriterLocks(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	prev := lockObserve
+	lockObserve = true
+	t.Cleanup(func() { lockObserve = prev })
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	m := NewLockManager()
+	writer, err := m.Acquire(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire writer: %v", err)
+	}
+	defer writer.Release()
+	lockPath, err := m.lockPath(repo, worktree)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	if writer.Observer() || writer.path != lockPath {
+		t.Fatalf("expected --observe to leave Acquire taking writer locks, got %s", writer.path)
+	}
+}
+
+func TestAcquireObserver_ReapsDeadObservers(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	m := NewLockManager()
+	dead, err := m.AcquireObserver(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire observer: %v", err)
+	}
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if err := dead.RebindPID(cmd.Process.Pid); err != nil {
+		t.Fatalf("rebind: %v", err)
+	}
+	live, err := m.AcquireObserver(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire second observer: %v", err)
+	}
+	defer live.Release()
+	if _, err := os.Stat(dead.path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the dead observer's lock to be reaped, got %v", err)
+	}
+	if _, err := os.Stat(live.path); err != nil {
+		t.Fatalf("expected the new observer lock to exist: %v", err)
+	}
+}
+
+func TestRelease_LeavesLockTakenOverByAnotherOwner(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	lock, err := NewLockManager().Acquire(repo, worktree)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	payload, err := lockPayload(repo, worktree, "teammate", lock.pid+1, "")
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if err := os.WriteFile(lock.path, payload, 0o644); err != nil {
+		t.Fatalf("write takeover: %v", err)
+	}
+	lock.Release()
+	current, err := readLockPayload(lock.path)
+	if err != nil || current.OwnerID != "teammate" {
+		t.Fatalf("expected the teammate's lock to survive Release, got %+v, %v", current, err)
+	}
+}
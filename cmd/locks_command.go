@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+type lockRow struct {
+	Path     string
+	Owner    string
+	Host     string
+	PID      string
+	State    string
+	Worktree string
+	Age      string
+	Label    string
+}
+
+func newLocksCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "locks",
+		Short: "List and force-remove worktree lock files",
+		Long:  "Lists every lock under ~/.wtx/locks, including --observe locks, with its owner, host, pid, liveness, age, label, and worktree. Works outside a repository.",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if !isInteractiveTerminal(os.Stdout) {
+				return printLockRows(os.Stdout, time.Now())
+			}
+			_, err := tea.NewProgram(newLocksModel()).Run()
+			return err
+		},
+	}
+}
+
+func loadLockRows(now time.Time) ([]lockRow, error) {
+	entries, err := listLockFiles()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]lockRow, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, newLockRow(entry, now))
+	}
+	return rows, nil
+}
+
+func newLockRow(entry lockFileEntry, now time.Time) lockRow {
+	row := lockRow{Path: entry.Path, Owner: "-", Host: "-", PID: "-", Worktree: "-", Age: "-", Label: "-"}
+	if entry.Err != nil {
+		row.State = "unreadable"
+		if info, err := os.Stat(entry.Path); err == nil {
+			row.Age = formatLockAge(now.Sub(info.ModTime()))
+		}
+		return row
+	}
+	payload := entry.Payload
+	row.Owner, row.Host = splitLockOwner(payload.OwnerID)
+	if payload.PID > 0 {
+		row.PID = strconv.Itoa(payload.PID)
+	}
+	if v := strings.TrimSpace(payload.WorktreePath); v != "" {
+		row.Worktree = v
+	}
+	if v := strings.TrimSpace(payload.Label); v != "" {
+		row.Label = v
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(payload.Timestamp)); err == nil {
+		row.Age = formatLockAge(now.Sub(ts))
+	}
+	row.State = "stale"
+	if lockOwnerStillActive(payload.OwnerID, payload.PID) {
+		row.State = "alive"
+		if entry.Observer {
+			row.State = "observing"
+		}
+	}
+	return row
+}
+
+// splitLockOwner extracts user and host from user@host:pid:token owner IDs;
+// session-scoped owners (tmux, terminal) are returned whole with no host.
+func splitLockOwner(ownerID string) (string, string) {
+	ownerID = strings.TrimSpace(ownerID)
+	if ownerID == "" {
+		return "-", "-"
+	}
+	userHost, _, ok := strings.Cut(ownerID, ":")
+	if ok {
+		if name, host, found := strings.Cut(userHost, "@"); found {
+			return name, host
+		}
+	}
+	return ownerID, "-"
+}
+
+func formatLockAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func formatLockRow(row lockRow) string {
+	return fmt.Sprintf("%-10s %-24s %-16s %-8s %-5s %-20s %s", row.State, truncateLockField(row.Owner, 24), truncateLockField(row.Host, 16), row.PID, row.Age, truncateLockField(row.Label, 20), row.Worktree)
+}
+
+func truncateLockField(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func printLockRows(w io.Writer, now time.Time) error {
+	rows, err := loadLockRows(now)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No locks.")
+		return nil
+	}
+	fmt.Fprintln(w, formatLockRow(lockRow{State: "STATE", Owner: "OWNER", Host: "HOST", PID: "PID", Age: "AGE", Label: "LABEL", Worktree: "WORKTREE"}))
+	for _, row := range rows {
+		fmt.Fprintln(w, formatLockRow(row))
+	}
+	return nil
+}
+
+type locksModel struct {
+	rows          []lockRow
+	selected      int
+	confirmRemove bool
+	errMsg        string
+	infoMsg       string
+}
+
+type locksLoadedMsg struct {
+	rows []lockRow
+	err  error
+}
+
+func newLocksModel() locksModel {
+	return locksModel{}
+}
+
+func loadLocksCmd() tea.Cmd {
+	return func() tea.Msg {
+		rows, err := loadLockRows(time.Now())
+		return locksLoadedMsg{rows: rows, err: err}
+	}
+}
+
+func (m locksModel) Init() tea.Cmd {
+	return loadLocksCmd()
+}
+
+func (m locksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case locksLoadedMsg:
+		m.rows = msg.rows
+		m.errMsg = ""
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+		}
+		m.selected = clampOpenDebugIndex(m.selected, len(m.rows))
+		return m, nil
+	case tea.KeyMsg:
+		if m.confirmRemove {
+			m.confirmRemove = false
+			if msg.String() == "y" || msg.String() == "Y" {
+				return m.removeSelected()
+			}
+			m.infoMsg = ""
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+		case "down", "j":
+			if m.selected < len(m.rows)-1 {
+				m.selected++
+			}
+			return m, nil
+		case "r":
+			m.infoMsg = ""
+			return m, loadLocksCmd()
+		case "d", "x":
+			if len(m.rows) == 0 {
+				return m, nil
+			}
+			if assumeYes {
+				return m.removeSelected()
+			}
+			m.confirmRemove = true
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m locksModel) removeSelected() (tea.Model, tea.Cmd) {
+	if m.selected < 0 || m.selected >= len(m.rows) {
+		return m, nil
+	}
+	row := m.rows[m.selected]
+	if err := os.Remove(row.Path); err != nil && !os.IsNotExist(err) {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+	m.errMsg = ""
+	m.infoMsg = "Removed " + filepath.Base(row.Path)
+	return m, loadLocksCmd()
+}
+
+func (m locksModel) View() string {
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(currentTheme().Accent)
+	b.WriteString(titleStyle.Render("Worktree locks"))
+	b.WriteString("\n")
+	b.WriteString(secondaryStyle.Render("  " + formatLockRow(lockRow{State: "State", Owner: "Owner", Host: "Host", PID: "PID", Age: "Age", Label: "Label", Worktree: "Worktree"})))
+	b.WriteString("\n")
+	for i, row := range m.rows {
+		line := "  " + formatLockRow(row)
+		switch {
+		case i == m.selected:
+			b.WriteString(selectorSelectedStyle.Render(line))
+		case row.State == "alive":
+			b.WriteString(selectorNormalStyle.Render(line))
+		default:
+			b.WriteString(selectorDisabledStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	if len(m.rows) == 0 {
+		b.WriteString(secondaryStyle.Render("  (no locks)"))
+		b.WriteString("\n")
+	}
+	if m.selected >= 0 && m.selected < len(m.rows) {
+		b.WriteString("\n")
+		b.WriteString(secondaryStyle.Render(m.rows[m.selected].Path))
+		b.WriteString("\n")
+	}
+	if m.errMsg != "" {
+		b.WriteString(errorStyle.Render(m.errMsg))
+		b.WriteString("\n")
+	}
+	if m.infoMsg != "" {
+		b.WriteString(m.infoMsg)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if m.confirmRemove {
+		b.WriteString(warnStyle.Render("Force-remove selected lock? y to confirm, any other key to cancel."))
+	} else {
+		b.WriteString(secondaryStyle.Render("↑/↓ navigate • d force-remove • r refresh • q quit"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitLockOwner(t *testing.T) {
+	tests := []struct {
+		owner    string
+		wantUser string
+		wantHost string
+	}{
+		{owner: "alice@box:123:abc", wantUser: "alice", wantHost: "box"},
+		{owner: "tmux:$1:@2", wantUser: "tmux:$1:@2", wantHost: "-"},
+		{owner: "", wantUser: "-", wantHost: "-"},
+	}
+	for _, tc := range tests {
+		gotUser, gotHost := splitLockOwner(tc.owner)
+		if gotUser != tc.wantUser || gotHost != tc.wantHost {
+			t.Fatalf("splitLockOwner(%q) = %q, %q; want %q, %q", tc.owner, gotUser, gotHost, tc.wantUser, tc.wantHost)
+		}
+	}
+}
+
+func TestPrintLockRowsShowsLivenessAndAge(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	writeTestLock(t, "stale.lock", map[string]any{
+		"owner_id":      "alice@box:1:abc",
+		"pid":           999999,
+		"worktree_path": "/tmp/wt.1",
+		"timestamp":     now.Add(-3 * time.Hour).UTC().Format(time.RFC3339Nano),
+		"label":         "release prep",
+	})
+
+	var out bytes.Buffer
+	if err := printLockRows(&out, now); err != nil {
+		t.Fatalf("printLockRows: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"stale", "alice", "box", "999999", "3h", "release prep", "/tmp/wt.1"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got %q", want, text)
+		}
+	}
+}
+
+func TestLocksModelForceRemovesAfterConfirm(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := writeTestLock(t, "live.lock", map[string]any{
+		"owner_id": "explicit:locks-test",
+		"pid":      os.Getpid(),
+	})
+	rows, err := loadLockRows(time.Now())
+	if err != nil {
+		t.Fatalf("loadLockRows: %v", err)
+	}
+	m := newLocksModel()
+	updated, _ := m.Update(locksLoadedMsg{rows: rows})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected lock kept until confirmed: %v", err)
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected lock removed after confirm, stat err=%v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	logFileEnv  = "WTX_LOG"
+	logLevelEnv = "WTX_LOG_LEVEL"
+)
+
+var (
+	loggerOnce sync.Once
+	logger     *slog.Logger
+)
+
+func wtxLogger() *slog.Logger {
+	loggerOnce.Do(func() {
+		logger = newWTXLogger(os.Getenv(logFileEnv), os.Getenv(logLevelEnv))
+	})
+	return logger
+}
+
+func newWTXLogger(path string, level string) *slog.Logger {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: parseLogLevel(level)})).With("pid", os.Getpid())
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func logDebug(msg string, args ...any) {
+	wtxLogger().Debug(msg, args...)
+}
+
+func logInfo(msg string, args ...any) {
+	wtxLogger().Info(msg, args...)
+}
+
+func logWarn(msg string, args ...any) {
+	wtxLogger().Warn(msg, args...)
+}
+
+func logError(msg string, args ...any) {
+	wtxLogger().Error(msg, args...)
+}
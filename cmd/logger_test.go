@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{in: "debug", want: slog.LevelDebug},
+		{in: " ERROR ", want: slog.LevelError},
+		{in: "info", want: slog.LevelInfo},
+		{in: "", want: slog.LevelInfo},
+		{in: "verbose", want: slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := parseLogLevel(tt.in); got != tt.want {
+			t.Fatalf("parseLogLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewWTXLogger_FiltersByLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "wtx.log")
+	l := newWTXLogger(path, "error")
+	l.Debug("debug line")
+	l.Error("error line")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	out := string(data)
+	if strings.Contains(out, "debug line") {
+		t.Fatalf("expected debug line to be filtered, got %q", out)
+	}
+	if !strings.Contains(out, "error line") {
+		t.Fatalf("expected error line in log, got %q", out)
+	}
+}
@@ -10,13 +10,14 @@ const (
 	openNewFetchKey      = "open_new_fetch"
 )
 
-func newOpenNewBranchForm(branch *string, baseRef *string, fetch *bool) *huh.Form {
+func newOpenNewBranchForm(branch *string, baseRef *string, fetch *bool, suggestions []string) *huh.Form {
 	branchInput := huh.NewInput().
 		Key(openNewBranchNameKey).
 		Title("Branch name").
 		Inline(true).
 		Prompt("> ").
 		Placeholder("tab to generate draft name").
+		Suggestions(suggestions).
 		Value(branch)
 
 	baseInput := huh.NewInput().
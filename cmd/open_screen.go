@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,20 +24,30 @@ type openSlotState struct {
 	Path      string
 	Branch    string
 	Locked    bool
+	Mine      bool
 	Dirty     bool
+	Changes   worktreeChanges
+	GitState  string
 	HasPR     bool
 	PRNumber  int
 	PRLoading bool
 }
 
 type openScreenLoadedMsg struct {
-	status         WorktreeStatus
-	branches       []openBranchOption
-	lockedBranches []openBranchOption
-	slots          []openSlotState
-	prBranches     []string
-	fetchID        string
-	err            error
+	status           WorktreeStatus
+	branches         []openBranchOption
+	lockedBranches   []openBranchOption
+	archivedBranches []openBranchOption
+	slots            []openSlotState
+	prBranches       []string
+	fetchID          string
+	err              error
+}
+
+// openScreenWorktreesMsg ends the first open-screen load phase; branch lists
+// are built in a second phase so the UI can report progress between them.
+type openScreenWorktreesMsg struct {
+	status WorktreeStatus
 }
 
 type openScreenPRDataMsg struct {
@@ -46,7 +57,12 @@ type openScreenPRDataMsg struct {
 }
 
 type openScreenDirtyMsg struct {
-	dirtyByPath map[string]bool
+	changesByPath map[string]worktreeChanges
+}
+
+type openBaseRefOptionsMsg struct {
+	refs []string
+	err  error
 }
 
 type openAllBranchesLoadedMsg struct {
@@ -57,12 +73,26 @@ type openAllBranchesLoadedMsg struct {
 
 const openSearchMatchLimit = 200
 
+const (
+	openLoadStageWorktrees = "Listing worktrees..."
+	openLoadStageStatus    = "Checking status..."
+	openLoadStagePRs       = "Fetching PRs..."
+)
+
 func loadOpenScreenCmd(orchestrator *WorktreeOrchestrator, mgr *WorktreeManager) tea.Cmd {
 	return func() tea.Msg {
 		if orchestrator == nil || mgr == nil {
 			return openScreenLoadedMsg{err: fmt.Errorf("open screen unavailable")}
 		}
-		status := orchestrator.Status()
+		return openScreenWorktreesMsg{status: orchestrator.Status()}
+	}
+}
+
+func loadOpenBranchesCmd(mgr *WorktreeManager, status WorktreeStatus) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return openScreenLoadedMsg{status: status, err: fmt.Errorf("open screen unavailable")}
+		}
 		if status.Err != nil {
 			return openScreenLoadedMsg{status: status, err: status.Err}
 		}
@@ -77,18 +107,41 @@ func loadOpenScreenCmd(orchestrator *WorktreeOrchestrator, mgr *WorktreeManager)
 				Path:      wt.Path,
 				Branch:    wt.Branch,
 				Locked:    !wt.Available,
+				Mine:      wt.LockedByMe,
+				GitState:  wt.GitState,
 				PRLoading: true,
 			}
 		}
+		branches = excludeBranches(branches, configuredExcludeBranchPatterns(), openSlotBranchSet(slots))
 		openBranches, lockedList, prBranches := buildOpenBranchLists(branches, slots, true)
+		if configuredEnrichOnlyWorktreeBranches() {
+			prBranches = limitPRBranchesToWorktrees(prBranches, slots, openBranches, lockedList)
+		}
+		var archivedList []openBranchOption
+		if archived, err := archivedBranchesForRepo(status.RepoRoot); err == nil {
+			inWorktree := openSlotBranchSet(slots)
+			gitPath := gitBinary()
+			openBranches, archivedList = splitArchivedOpenBranches(openBranches, archived, func(name string) bool {
+				if inWorktree[name] {
+					return false
+				}
+				exists, err := branchExistsLocalOrRemote(status.RepoRoot, gitPath, name)
+				return err == nil && exists
+			})
+			if !configuredEnrichOnlyWorktreeBranches() {
+				prBranches = appendMissingBranchNames(prBranches, archivedList)
+			}
+		}
+		sortOpenBranchesForDisplay(openBranches, configuredOpenBranchSort())
 
 		return openScreenLoadedMsg{
-			status:         status,
-			branches:       openBranches,
-			lockedBranches: lockedList,
-			slots:          slots,
-			prBranches:     prBranches,
-			fetchID:        fmt.Sprintf("%d", time.Now().UnixNano()),
+			status:           status,
+			branches:         openBranches,
+			lockedBranches:   lockedList,
+			archivedBranches: archivedList,
+			slots:            slots,
+			prBranches:       prBranches,
+			fetchID:          fmt.Sprintf("%d", time.Now().UnixNano()),
 		}
 	}
 }
@@ -102,7 +155,9 @@ func loadAllOpenBranchesCmd(mgr *WorktreeManager, slots []openSlotState) tea.Cmd
 		if err != nil {
 			return openAllBranchesLoadedMsg{err: err}
 		}
+		branches = excludeBranches(branches, configuredExcludeBranchPatterns(), openSlotBranchSet(slots))
 		openBranches, lockedBranches, _ := buildOpenBranchLists(branches, slots, false)
+		sortOpenBranchesForDisplay(openBranches, configuredOpenBranchSort())
 		return openAllBranchesLoadedMsg{
 			branches:       openBranches,
 			lockedBranches: lockedBranches,
@@ -110,6 +165,16 @@ func loadAllOpenBranchesCmd(mgr *WorktreeManager, slots []openSlotState) tea.Cmd
 	}
 }
 
+func loadOpenBaseRefOptionsCmd(mgr *WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return openBaseRefOptionsMsg{err: fmt.Errorf("open screen unavailable")}
+		}
+		refs, err := mgr.ListRemoteBaseRefs()
+		return openBaseRefOptionsMsg{refs: refs, err: err}
+	}
+}
+
 func buildOpenBranchLists(branches []string, slots []openSlotState, prLoading bool) ([]openBranchOption, []openBranchOption, []string) {
 	lockedOnlyBranches := make(map[string]bool, len(slots))
 	openSlotBranches := make(map[string]bool, len(slots))
@@ -194,26 +259,177 @@ func buildOpenBranchLists(branches []string, slots []openSlotState, prLoading bo
 	return openBranches, lockedList, prBranches
 }
 
+func configuredEnrichOnlyWorktreeBranches() bool {
+	cfg, err := LoadConfig()
+	return err == nil && cfg.EnrichOnlyWorktreeBranches
+}
+
+// limitPRBranchesToWorktrees keeps only the branches checked out in a slot and
+// stops the PR spinner on every other option, since no data will arrive.
+func limitPRBranchesToWorktrees(prBranches []string, slots []openSlotState, lists ...[]openBranchOption) []string {
+	inWorktree := openSlotBranchSet(slots)
+	limited := make([]string, 0, len(slots))
+	for _, name := range prBranches {
+		if inWorktree[name] {
+			limited = append(limited, name)
+		}
+	}
+	for _, list := range lists {
+		for i := range list {
+			if !inWorktree[strings.TrimSpace(list[i].Name)] {
+				list[i].PRLoading = false
+			}
+		}
+	}
+	return limited
+}
+
+func configuredOpenBranchSort() string {
+	if cfg, err := LoadConfig(); err == nil {
+		return cfg.OpenBranchSort
+	}
+	return openBranchSortRecent
+}
+
+// sortOpenBranchesForDisplay reorders branches in place. Recency order from
+// git is kept unless mode is alpha; <new branch> is rendered separately.
+func sortOpenBranchesForDisplay(branches []openBranchOption, mode string) {
+	if mode != openBranchSortAlpha {
+		return
+	}
+	sort.SliceStable(branches, func(i, j int) bool {
+		a := strings.ToLower(branches[i].Name)
+		b := strings.ToLower(branches[j].Name)
+		if a == b {
+			return branches[i].Name < branches[j].Name
+		}
+		return a < b
+	})
+}
+
+// splitArchivedOpenBranches moves every archived branch that still exists out
+// of branches and into its own list, newest archive first. Archived branches
+// beyond the recent-branch limit are listed too, so they can be recreated.
+func splitArchivedOpenBranches(branches []openBranchOption, archived []archivedBranch, exists func(string) bool) ([]openBranchOption, []openBranchOption) {
+	if len(archived) == 0 {
+		return branches, nil
+	}
+	byName := make(map[string]openBranchOption, len(branches))
+	for _, branch := range branches {
+		byName[strings.TrimSpace(branch.Name)] = branch
+	}
+	archivedSet := make(map[string]bool, len(archived))
+	archivedList := make([]openBranchOption, 0, len(archived))
+	for _, entry := range archived {
+		name := strings.TrimSpace(entry.Branch)
+		if name == "" || archivedSet[name] || !exists(name) {
+			continue
+		}
+		branch, ok := byName[name]
+		if !ok {
+			branch = openBranchOption{Name: name}
+		}
+		archivedSet[name] = true
+		archivedList = append(archivedList, branch)
+	}
+	if len(archivedList) == 0 {
+		return branches, nil
+	}
+	kept := make([]openBranchOption, 0, len(branches))
+	for _, branch := range branches {
+		if archivedSet[strings.TrimSpace(branch.Name)] {
+			continue
+		}
+		kept = append(kept, branch)
+	}
+	return kept, archivedList
+}
+
+func appendMissingBranchNames(names []string, options []openBranchOption) []string {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+	for _, option := range options {
+		if name := strings.TrimSpace(option.Name); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// openSelectableIndices lists the selectable rows after <new branch> as
+// indices into openBranches, followed by the archived rows (offset by
+// len(openBranches)) while the archived section is shown.
+func (m model) openSelectableIndices() []int {
+	indices := openFilteredIndices(m.openTypeahead, m.openBranches)
+	if strings.TrimSpace(m.openTypeahead) != "" {
+		return indices
+	}
+	for i := range m.openArchivedBranches {
+		indices = append(indices, len(m.openBranches)+i)
+	}
+	return indices
+}
+
+// openSelectedBranchName is the branch on the selected row, recent or
+// archived.
+func (m model) openSelectedBranchName() (string, bool) {
+	index := m.openSelected - 1
+	var name string
+	switch {
+	case index >= 0 && index < len(m.openBranches):
+		name = m.openBranches[index].Name
+	case index >= len(m.openBranches) && index < len(m.openBranches)+len(m.openArchivedBranches) && strings.TrimSpace(m.openTypeahead) == "":
+		name = m.openArchivedBranches[index-len(m.openBranches)].Name
+	}
+	name = strings.TrimSpace(name)
+	return name, name != ""
+}
+
 func fetchDirtyStatusCmd(paths []string) tea.Cmd {
 	return func() tea.Msg {
-		result := make(map[string]bool, len(paths))
+		withLines := false
+		if cfg, err := LoadConfig(); err == nil {
+			withLines = cfg.DirtyLineCounts
+		}
+		result := make(map[string]worktreeChanges, len(paths))
 		var mu sync.Mutex
 		var wg sync.WaitGroup
 		for _, p := range paths {
 			wg.Add(1)
 			go func(path string) {
 				defer wg.Done()
-				dirty, err := worktreeDirty(path)
+				changes, err := worktreeChangeStats(path, withLines)
 				if err == nil {
 					mu.Lock()
-					result[path] = dirty
+					result[path] = changes
 					mu.Unlock()
 				}
 			}(p)
 		}
 		wg.Wait()
-		return openScreenDirtyMsg{dirtyByPath: result}
+		return openScreenDirtyMsg{changesByPath: result}
+	}
+}
+
+func refreshOpenDirtyCmd(slots []openSlotState) tea.Cmd {
+	paths := openSlotPaths(slots)
+	if len(paths) == 0 {
+		return nil
+	}
+	return fetchDirtyStatusCmd(paths)
+}
+
+func openSlotPaths(slots []openSlotState) []string {
+	var paths []string
+	for _, slot := range slots {
+		if slot.Path != "" {
+			paths = append(paths, slot.Path)
+		}
 	}
+	return paths
 }
 
 func fetchOpenPRDataCmd(orchestrator *WorktreeOrchestrator, repoRoot string, branches []string, fetchID string) tea.Cmd {
@@ -305,7 +521,8 @@ func renderOpenScreen(m model) string {
 	}
 	if m.openShowDebug {
 		b.WriteString("Worktrees debug:\n")
-		b.WriteString(secondaryStyle.Render(fmt.Sprintf("  %-12s %-24s %s", "State", "Branch", "Path")) + "\n")
+		stateWidth := debugStateWidth(m.openSlots)
+		b.WriteString(secondaryStyle.Render(fmt.Sprintf("  %-*s %-24s %s", stateWidth, "State", "Branch", "Path")) + "\n")
 		for i, slot := range m.openSlots {
 			cursor := "  "
 			rowRenderer := secondaryStyle.Render
@@ -313,7 +530,7 @@ func renderOpenScreen(m model) string {
 				rowRenderer = selectorSelectedStyle.Render
 			}
 			state := debugWorktreeState(slot)
-			line := fmt.Sprintf("%s%-12s %-24s %s", cursor, state, slot.Branch, slot.Path)
+			line := fmt.Sprintf("%s%-*s %-24s %s", cursor, stateWidth, state, slot.Branch, displayWorktreePath(m.status.RepoRoot, slot.Path, m.relativePaths))
 			b.WriteString(rowRenderer(line) + "\n")
 		}
 		if len(m.openSlots) == 0 {
@@ -334,22 +551,35 @@ func renderOpenScreen(m model) string {
 			b.WriteString(errorStyle.Render(m.errMsg))
 			b.WriteString("\n")
 		}
+		if m.warnMsg != "" {
+			b.WriteString("\n")
+			b.WriteString(warnStyle.Render(m.warnMsg))
+			b.WriteString("\n")
+		}
 		if m.updateHint != "" {
 			b.WriteString("\n")
 			b.WriteString(renderUpdateHint(m.updateHint, m.updateHintIsError))
 			b.WriteString("\n")
 		}
-		b.WriteString("\nUse up/down to select. d delete selected (with confirm). u unlock selected (with confirm). n new worktree.\n")
+		b.WriteString("\nUse up/down to select. d delete selected (with confirm). a archive selected, keeping its branch. x discards its changes (with confirm). u unlock selected (with confirm). n new worktree. R repairs worktree links. A toggles relative paths.\n")
 		if m.openDebugCreating {
 			b.WriteString("Type branch name, tab generates draft-<ts>, enter to create, esc to cancel. ")
 		}
-		b.WriteString("Ctrl+R refreshes. Esc/Ctrl+D back. q quits.\n")
+		b.WriteString("Ctrl+R refreshes. Ctrl+L refreshes clean/dirty only. Esc/Ctrl+D back. q quits.\n")
 		return b.String()
 	}
 	if m.openStage == openStageNewBranchConfig {
 		if m.openNewBranchForm != nil {
 			b.WriteString(m.openNewBranchForm.View())
 			b.WriteString("\n")
+			if focused := m.openNewBranchForm.GetFocusedField(); focused != nil && focused.GetKey() == openNewBranchNameKey {
+				if branch := strings.TrimSpace(fmt.Sprint(focused.GetValue())); containsBranch(m.branchNameOptions, branch) {
+					b.WriteString(warnStyle.Render(branch + " already exists; submitting checks it out instead of creating it."))
+					b.WriteString("\n")
+				}
+			}
+			b.WriteString(secondaryStyle.Render("Tab completes existing branch names. Ctrl+B picks the base ref from remote branches. Ctrl+G names the branch after a GitHub issue."))
+			b.WriteString("\n")
 		}
 		if m.openLoadErr != "" {
 			b.WriteString("\n")
@@ -368,6 +598,65 @@ func renderOpenScreen(m model) string {
 		}
 		return b.String()
 	}
+	if m.openStage == openStagePickBaseRef {
+		b.WriteString("Pick base ref:\n")
+		b.WriteString("  " + inputStyle.Render(m.openBaseRefInput.View()) + "\n")
+		if m.openBaseRefLoading {
+			b.WriteString("  Loading remote branches...\n")
+		} else if len(m.openBaseRefFiltered) == 0 {
+			b.WriteString("  No matching remote branches. Enter uses the typed ref.\n")
+		}
+		limit := openBranchRenderLimit(m.height)
+		start := 0
+		if m.openBaseRefIndex >= limit {
+			start = m.openBaseRefIndex - limit + 1
+		}
+		for i := start; i < len(m.openBaseRefFiltered) && i < start+limit; i++ {
+			line := "  " + m.openBaseRefFiltered[i]
+			if i == m.openBaseRefIndex {
+				b.WriteString(actionSelectedStyle.Render(line) + "\n")
+			} else {
+				b.WriteString(actionNormalStyle.Render(line) + "\n")
+			}
+		}
+		if m.errMsg != "" {
+			b.WriteString("\n")
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nType to filter, up/down to choose, enter to select. Esc goes back.\n")
+		return b.String()
+	}
+	if m.openStage == openStagePickIssue {
+		b.WriteString("Pick issue:\n")
+		b.WriteString("  " + inputStyle.Render(m.openIssueInput.View()) + "\n")
+		if m.openIssuesLoading {
+			b.WriteString("  Loading open issues...\n")
+		} else if len(m.openIssuesFiltered) == 0 {
+			b.WriteString("  No matching open issues.\n")
+		}
+		limit := openBranchRenderLimit(m.height)
+		start := 0
+		if m.openIssueIndex >= limit {
+			start = m.openIssueIndex - limit + 1
+		}
+		for i := start; i < len(m.openIssuesFiltered) && i < start+limit; i++ {
+			issue := m.openIssuesFiltered[i]
+			line := fmt.Sprintf("  #%-6d %s", issue.Number, issue.Title)
+			if i == m.openIssueIndex {
+				b.WriteString(actionSelectedStyle.Render(line) + "\n")
+			} else {
+				b.WriteString(actionNormalStyle.Render(line) + "\n")
+			}
+		}
+		if m.errMsg != "" {
+			b.WriteString("\n")
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nType to filter, up/down to choose, enter names the branch after the issue. Esc goes back.\n")
+		return b.String()
+	}
 	if m.openStage == openStagePickWorktree {
 		b.WriteString("No clean available worktree. Choose target:\n")
 		createLine := "  + Create new worktree"
@@ -385,7 +674,7 @@ func renderOpenScreen(m model) string {
 				render = selectorSelectedStyle.Render
 			}
 			state := debugWorktreeState(slot)
-			line := fmt.Sprintf("%s%-12s %-24s %s", cursor, state, slot.Branch, slot.Path)
+			line := fmt.Sprintf("%s%-*s %-24s %s", cursor, debugStateWidth(m.openSlots), state, slot.Branch, displayWorktreePath(m.status.RepoRoot, slot.Path, m.relativePaths))
 			b.WriteString(render(line) + "\n")
 		}
 		if m.openLoadErr != "" {
@@ -408,7 +697,7 @@ func renderOpenScreen(m model) string {
 			b.WriteString(renderUpdateHint(m.updateHint, m.updateHintIsError))
 			b.WriteString("\n")
 		}
-		b.WriteString("\nUse up/down to choose, enter to select. Esc goes back. Ctrl+R refreshes (auto-refresh every 2s).\n")
+		b.WriteString("\nUse up/down to choose, enter to select. Esc goes back. Ctrl+R refreshes (auto-refresh every 2s). Ctrl+L refreshes clean/dirty only.\n")
 		return b.String()
 	}
 	b.WriteString("Choose branch:\n")
@@ -419,7 +708,7 @@ func renderOpenScreen(m model) string {
 	} else {
 		b.WriteString(actionNormalStyle.Render(newBranchLine) + "\n")
 	}
-	branchColWidth := openBranchColumnWidth(m.openBranches, m.openLockedBranches)
+	branchColWidth := openBranchColumnWidth(m.openBranches, append(append([]openBranchOption{}, m.openLockedBranches...), m.openArchivedBranches...))
 	filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
 	visibleFiltered, trimmed := openVisibleFilteredIndices(filtered, m.openSelected, openBranchRenderLimit(m.height))
 	for _, branchIndex := range visibleFiltered {
@@ -471,10 +760,33 @@ func renderOpenScreen(m model) string {
 			b.WriteString(secondaryStyle.Render(line) + "\n")
 		}
 	}
+	if len(m.openArchivedBranches) > 0 && strings.TrimSpace(m.openTypeahead) == "" {
+		b.WriteString("\n")
+		b.WriteString(secondaryStyle.Render(fmt.Sprintf("Archived (%d, type to search):", len(m.openArchivedBranches))) + "\n")
+		for i, branch := range m.openArchivedBranches {
+			pr := "-"
+			if branch.HasPR && branch.PRNumber > 0 {
+				pr = fmt.Sprintf("#%d", branch.PRNumber)
+				if strings.TrimSpace(branch.PRURL) != "" {
+					pr = termenv.Hyperlink(branch.PRURL, pr)
+				}
+			}
+			line := fmt.Sprintf("  %-*s %s", branchColWidth, branch.Name, pr)
+			if m.openSelected == len(m.openBranches)+i+1 {
+				b.WriteString(actionSelectedStyle.Render(line) + "\n")
+			} else {
+				b.WriteString(secondaryStyle.Render(line) + "\n")
+			}
+		}
+	}
 	if m.openLoadErr != "" {
 		b.WriteString("\n")
 		b.WriteString(errorStyle.Render("Error: " + m.openLoadErr))
 		b.WriteString("\n")
+	} else if m.openLoading && m.openLoadStage != "" {
+		b.WriteString("\n")
+		b.WriteString(secondaryStyle.Render(m.ghSpinner.View() + " " + m.openLoadStage))
+		b.WriteString("\n")
 	}
 	if m.errMsg != "" {
 		b.WriteString("\n")
@@ -492,7 +804,7 @@ func renderOpenScreen(m model) string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString("Use up/down or type to search by branch/PR. Enter selects. Ctrl+R refreshes. Ctrl+D debug. q quits.\n")
+	b.WriteString("Use up/down or type to search by branch/PR. Enter selects. Ctrl+R refreshes. Ctrl+L refreshes clean/dirty only. Ctrl+D debug. ? shows all keys. q quits.\n")
 	return b.String()
 }
 
@@ -517,15 +829,31 @@ func openBranchColumnWidth(openBranches []openBranchOption, lockedBranches []ope
 }
 
 func debugWorktreeState(slot openSlotState) string {
+	if slot.Mine {
+		return "yours"
+	}
 	if slot.Locked {
 		return "in use"
 	}
 	if slot.Dirty {
+		if slot.Changes.Files > 0 {
+			return "unclean (" + slot.Changes.label() + ")"
+		}
 		return "unclean"
 	}
 	return "clean"
 }
 
+// debugStateWidth is the state column width for slots: at least 12, wider
+// when change counts make a state longer.
+func debugStateWidth(slots []openSlotState) int {
+	width := 12
+	for _, slot := range slots {
+		width = max(width, len(debugWorktreeState(slot)))
+	}
+	return width
+}
+
 func findReusableOpenSlot(slots []openSlotState, branch string) (openSlotState, bool) {
 	want := strings.TrimSpace(branch)
 	for _, slot := range slots {
@@ -540,9 +868,11 @@ func findReusableOpenSlot(slots []openSlotState, branch string) (openSlotState,
 	return openSlotState{}, false
 }
 
+// findAnyAvailableOpenSlot picks a slot to check another branch out in, so it
+// skips slots mid-rebase or mid-merge as well as locked and dirty ones.
 func findAnyAvailableOpenSlot(slots []openSlotState) (openSlotState, bool) {
 	for _, slot := range slots {
-		if slot.Locked || slot.Dirty {
+		if slot.Locked || slot.Dirty || slot.GitState != "" {
 			continue
 		}
 		return slot, true
@@ -550,6 +880,16 @@ func findAnyAvailableOpenSlot(slots []openSlotState) (openSlotState, bool) {
 	return openSlotState{}, false
 }
 
+func clampOpenBaseRefIndex(index int, count int) int {
+	if count <= 0 || index < 0 {
+		return 0
+	}
+	if index >= count {
+		return count - 1
+	}
+	return index
+}
+
 func openPickRowCount(slots []openSlotState) int {
 	return len(slots) + 1
 }
@@ -691,15 +1031,76 @@ func ensureOpenSelectionVisible(current int, filtered []int) int {
 }
 
 func worktreeDirty(path string) (bool, error) {
-	gitOut, err := gitOutputInDir(path, "git", "status", "--porcelain")
+	changes, err := worktreeChangeStats(path, false)
+	return changes.Files > 0, err
+}
+
+// worktreeChanges says how dirty a worktree is. Line totals are only filled
+// in (Lines set) when asked for, since diff --shortstat costs more than
+// status --porcelain.
+type worktreeChanges struct {
+	Files      int
+	Insertions int
+	Deletions  int
+	Lines      bool
+}
+
+func (c worktreeChanges) label() string {
+	label := fmt.Sprintf("%d files", c.Files)
+	if c.Files == 1 {
+		label = "1 file"
+	}
+	if c.Lines {
+		label += fmt.Sprintf(", +%d -%d", c.Insertions, c.Deletions)
+	}
+	return label
+}
+
+func worktreeChangeStats(path string, withLines bool) (worktreeChanges, error) {
+	gitOut, err := gitOutputInDir(path, gitBinary(), "status", "--porcelain")
 	if err != nil {
 		msg := strings.TrimSpace(gitOut)
 		if msg == "" {
-			return false, err
+			return worktreeChanges{}, err
+		}
+		return worktreeChanges{}, fmt.Errorf("git status failed for %s: %s", path, msg)
+	}
+	var changes worktreeChanges
+	for _, line := range strings.Split(gitOut, "\n") {
+		if strings.TrimSpace(line) != "" {
+			changes.Files++
+		}
+	}
+	if withLines && changes.Files > 0 {
+		if out, err := gitOutputInDir(path, gitBinary(), "diff", "HEAD", "--shortstat"); err == nil {
+			changes.Insertions, changes.Deletions = parseShortstat(out)
+			changes.Lines = true
+		}
+	}
+	return changes, nil
+}
+
+// parseShortstat reads the insertion and deletion totals from
+// "3 files changed, 10 insertions(+), 2 deletions(-)".
+func parseShortstat(out string) (int, int) {
+	var insertions, deletions int
+	for _, part := range strings.Split(out, ",") {
+		fields := strings.Fields(part)
+		if len(fields) < 2 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(fields[1], "insertion"):
+			insertions = n
+		case strings.HasPrefix(fields[1], "deletion"):
+			deletions = n
 		}
-		return false, fmt.Errorf("git status failed for %s: %s", path, msg)
 	}
-	return strings.TrimSpace(gitOut) != "", nil
+	return insertions, deletions
 }
 
 func worktreeLockedByAny(orchestrator *WorktreeOrchestrator, repoRoot string, worktreePath string) (bool, error) {
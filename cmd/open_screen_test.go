@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestRenderOpenScreenTmuxHintShownBelowUpdateHint(t *testing.T) {
@@ -97,6 +101,57 @@ func TestBuildOpenBranchLists_NoPRLoadingInSearchMode(t *testing.T) {
 	}
 }
 
+func TestLimitPRBranchesToWorktrees(t *testing.T) {
+	slots := []openSlotState{{Path: "/wt/1", Branch: "feature/a"}, {Path: "/wt/2", Branch: "feature/b", Locked: true}}
+	openBranches, lockedBranches, prBranches := buildOpenBranchLists([]string{"main", "feature/a", "feature/b", "old"}, slots, true)
+	got := limitPRBranchesToWorktrees(prBranches, slots, openBranches, lockedBranches)
+	if strings.Join(got, ",") != "feature/a,feature/b" {
+		t.Fatalf("expected only worktree branches, got %v", got)
+	}
+	for _, b := range append(openBranches, lockedBranches...) {
+		inWorktree := b.Name == "feature/a" || b.Name == "feature/b"
+		if b.PRLoading != inWorktree {
+			t.Fatalf("expected %s PRLoading=%v, got %v", b.Name, inWorktree, b.PRLoading)
+		}
+	}
+}
+
+func TestSplitArchivedOpenBranches(t *testing.T) {
+	branches := []openBranchOption{{Name: "main"}, {Name: "feature/a"}, {Name: "feature/b", PRNumber: 7}}
+	archived := []archivedBranch{{Branch: "feature/b"}, {Branch: "old/c"}, {Branch: "gone"}}
+	exists := func(name string) bool { return name != "gone" }
+	kept, archivedList := splitArchivedOpenBranches(branches, archived, exists)
+	if len(kept) != 2 || kept[0].Name != "main" || kept[1].Name != "feature/a" {
+		t.Fatalf("unexpected kept branches: %+v", kept)
+	}
+	if len(archivedList) != 2 || archivedList[0].Name != "feature/b" || archivedList[0].PRNumber != 7 || archivedList[1].Name != "old/c" {
+		t.Fatalf("expected archived branches from state, beyond the recent list, got %+v", archivedList)
+	}
+}
+
+func TestOpenScreen_ArchivedRowsAreSelectable(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	m.openStage = openStageMain
+	m.openBranches = []openBranchOption{{Name: "main"}}
+	m.openArchivedBranches = []openBranchOption{{Name: "old/c"}}
+
+	var updatedModel tea.Model = m
+	for range 2 {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if got := updatedModel.(model).openSelected; got != 2 {
+		t.Fatalf("expected the archived row selected, got %d", got)
+	}
+	if view := renderOpenScreen(updatedModel.(model)); !strings.Contains(view, "old/c") {
+		t.Fatalf("expected archived row rendered, got %q", view)
+	}
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updatedModel.(model).openTargetBranch; got != "old/c" {
+		t.Fatalf("expected archived branch as the open target, got %q", got)
+	}
+}
+
 func TestOpenVisibleFilteredIndices_KeepsSelectionVisible(t *testing.T) {
 	filtered := make([]int, 0, 50)
 	for i := 0; i < 50; i++ {
@@ -132,3 +187,44 @@ func TestOpenBranchRenderLimit_Clamped(t *testing.T) {
 		t.Fatalf("expected min-clamped limit 8, got %d", got)
 	}
 }
+
+func TestSortOpenBranchesForDisplay(t *testing.T) {
+	branches := []openBranchOption{{Name: "zeta"}, {Name: "Alpha"}, {Name: "beta"}}
+	sortOpenBranchesForDisplay(branches, openBranchSortRecent)
+	if branches[0].Name != "zeta" {
+		t.Fatalf("expected recent order to be preserved, got %+v", branches)
+	}
+	sortOpenBranchesForDisplay(branches, openBranchSortAlpha)
+	got := []string{branches[0].Name, branches[1].Name, branches[2].Name}
+	if got[0] != "Alpha" || got[1] != "beta" || got[2] != "zeta" {
+		t.Fatalf("expected case-insensitive alpha order, got %v", got)
+	}
+}
+
+func TestWorktreeChangeStats_CountsFilesAndOptionalLines(t *testing.T) {
+	repo := initTestRepo(t)
+
+	clean, err := worktreeChangeStats(repo, true)
+	if err != nil || clean.Files != 0 || clean.Lines {
+		t.Fatalf("expected clean worktree, got %+v %v", clean, err)
+	}
+	writeTestFile(t, filepath.Join(repo, "a.txt"), "three\nfour\n")
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	quick, err := worktreeChangeStats(repo, false)
+	if err != nil || quick.Files != 2 || quick.Lines {
+		t.Fatalf("expected 2 files without line counts, got %+v %v", quick, err)
+	}
+	full, err := worktreeChangeStats(repo, true)
+	if err != nil || !full.Lines || full.Insertions != 2 || full.Deletions != 1 {
+		t.Fatalf("expected +2 -1, got %+v %v", full, err)
+	}
+	if got := debugWorktreeState(openSlotState{Dirty: true, Changes: full}); got != "unclean (2 files, +2 -1)" {
+		t.Fatalf("unexpected state %q", got)
+	}
+	if got := debugWorktreeState(openSlotState{Dirty: true, Changes: worktreeChanges{Files: 1}}); got != "unclean (1 file)" {
+		t.Fatalf("unexpected state %q", got)
+	}
+}
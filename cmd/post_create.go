@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// runPostCreateSteps copies configured files from the main checkout into a new
+// worktree and then runs the post-create hook inside it.
+func runPostCreateSteps(repoRoot string, target string, branch string) error {
+	cfg, err := loadConfigForDir(repoRoot)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, rel := range cfg.CopyOnCreate {
+		if err := validateCopyOnCreateEntry(rel); err != nil {
+			return err
+		}
+		if err := copyIntoWorktree(filepath.Join(repoRoot, rel), filepath.Join(target, rel)); err != nil {
+			return fmt.Errorf("copy %s: %w", rel, err)
+		}
+	}
+	if cfg.PostCreateHook == "" {
+		return nil
+	}
+	cmd := exec.Command("/bin/sh", "-c", cfg.PostCreateHook)
+	cmd.Dir = target
+	cmd.Env = append(os.Environ(),
+		"WTX_WORKTREE_PATH="+target,
+		"WTX_REPO_ROOT="+repoRoot,
+		"WTX_BRANCH="+branch,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("post-create hook failed: %w", commandErrorWithOutput(err, out))
+	}
+	return nil
+}
+
+// copyIntoWorktree copies src to dst, recursing into directories. Missing
+// sources are skipped and existing destination files are left untouched.
+func copyIntoWorktree(src string, dst string) error {
+	if _, err := os.Lstat(src); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		out := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(out, 0o755)
+		}
+		if _, err := os.Lstat(out); err == nil {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, out)
+		}
+		return copyFile(path, out)
+	})
+}
+
+func copyFile(src string, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
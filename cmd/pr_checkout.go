@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const ghPRCheckoutTimeout = 60 * time.Second
+
+// CheckoutPRWorktree puts pull request number into a worktree via
+// `gh pr checkout`, which also sets up the remote and local branch for PRs
+// from forks. If the PR's local branch already has an idle worktree, gh
+// updates that worktree to the PR head; otherwise a new worktree is added at
+// HEAD and gh switches it over.
+func (m *WorktreeManager) CheckoutPRWorktree(number int) (WorktreeInfo, error) {
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	head, err := resolvePRHead(repoRoot, number)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	branches := []string{head.HeadRefName}
+	if head.IsCrossRepository {
+		branches = prPullRefBranches(repoRoot, gitPath, number)
+	}
+	reused, reusedLock, err := m.reusablePRWorktree(repoRoot, gitPath, branches)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	if reusedLock != nil {
+		defer reusedLock.Release()
+		if err := runGHPRCheckout(reused.Path, number); err != nil {
+			return WorktreeInfo{}, err
+		}
+		reused.PRNumber = number
+		reused.HasPR = true
+		return reused, nil
+	}
+
+	layoutRoot := worktreeLayoutRoot(repoRoot, gitPath)
+	target, err := nextWorktreePath(layoutRoot)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, target)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	defer lock.Release()
+
+	if err := runWorktreeAdd(layoutRoot, gitPath, target, "--detach", target, "HEAD"); err != nil {
+		return WorktreeInfo{}, err
+	}
+	if err := runGHPRCheckout(target, number); err != nil {
+		_ = runCommandInDir(repoRoot, gitPath, "worktree", "remove", "--force", target)
+		return WorktreeInfo{}, err
+	}
+	branch, err := gitOutputInDir(target, gitPath, "branch", "--show-current")
+	if err != nil || branch == "" {
+		branch = head.HeadRefName
+	}
+	created := WorktreeInfo{Path: target, Branch: branch, PRNumber: number, HasPR: true}
+	if err := runPostCreateSteps(repoRoot, target, branch); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// reusablePRWorktree finds an existing worktree on one of branches and
+// returns it with its lock held, failing if that worktree is in use. The lock
+// is nil when no worktree matches.
+func (m *WorktreeManager) reusablePRWorktree(repoRoot string, gitPath string, branches []string) (WorktreeInfo, *WorktreeLock, error) {
+	if len(branches) == 0 {
+		return WorktreeInfo{}, nil, nil
+	}
+	worktrees, _, err := listWorktrees(repoRoot, gitPath)
+	if err != nil {
+		return WorktreeInfo{}, nil, err
+	}
+	for _, wt := range worktrees {
+		branch := strings.TrimSpace(wt.Branch)
+		if !slices.Contains(branches, branch) {
+			continue
+		}
+		lock, err := m.lockMgr.Acquire(repoRoot, wt.Path)
+		if err != nil {
+			return WorktreeInfo{}, nil, fmt.Errorf("branch %s already has a worktree in use", branch)
+		}
+		return wt, lock, nil
+	}
+	return WorktreeInfo{}, nil, nil
+}
+
+// prPullRefBranches lists local branches that track refs/pull/<number>/head,
+// which is how gh pr checkout sets up the branch for a fork PR.
+func prPullRefBranches(repoRoot string, gitPath string, number int) []string {
+	out, err := gitOutputInDir(repoRoot, gitPath, "config", "--get-regexp", `^branch\..*\.merge$`)
+	if err != nil {
+		return nil
+	}
+	pullRef := "refs/pull/" + strconv.Itoa(number) + "/head"
+	var branches []string
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || strings.TrimSpace(value) != pullRef {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), ".merge")
+		if name != "" {
+			branches = append(branches, name)
+		}
+	}
+	return branches
+}
+
+func runGHPRCheckout(dir string, number int) error {
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		return errors.New("gh not installed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ghPRCheckoutTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, ghPath, "pr", "checkout", strconv.Itoa(number))
+	cmd.Dir = dir
+	cmd.Env = ghCommandEnv(dir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("gh pr checkout %d: %s", number, msg)
+		}
+		return fmt.Errorf("gh pr checkout %d: %w", number, err)
+	}
+	return nil
+}
+
+func checkoutPRWorktreeCmd(mgr *WorktreeManager, number int) tea.Cmd {
+	return func() tea.Msg {
+		created, err := mgr.CheckoutPRWorktree(number)
+		return createWorktreeDoneMsg{created: created, err: err}
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupPRCheckoutTest(t *testing.T, viewJSON string, checkout string) string {
+	t.Helper()
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	runTestGit(t, repo, "init", "-q")
+	runTestGit(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"view) echo '" + viewJSON + "' ;;\n" +
+		"checkout) " + checkout + " ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake gh: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return repo
+}
+
+func TestCheckoutPRWorktree_CrossRepositoryUsesGHBranch(t *testing.T) {
+	repo := setupPRCheckoutTest(t,
+		`{"headRefName":"main","isCrossRepository":true,"headRepositoryOwner":{"login":"fork"}}`,
+		"git checkout -q -b fork/main")
+
+	created, err := NewWorktreeManager(repo, NewLockManager()).CheckoutPRWorktree(42)
+	if err != nil {
+		t.Fatalf("CheckoutPRWorktree: %v", err)
+	}
+	if created.Branch != "fork/main" || created.PRNumber != 42 {
+		t.Fatalf("unexpected worktree %+v", created)
+	}
+	if sameRealPath(created.Path, repo) {
+		t.Fatalf("expected a new worktree, got the main checkout")
+	}
+}
+
+func TestCheckoutPRWorktree_RemovesWorktreeWhenGHFails(t *testing.T) {
+	repo := setupPRCheckoutTest(t,
+		`{"headRefName":"feature","isCrossRepository":true,"headRepositoryOwner":{"login":"fork"}}`,
+		"echo 'could not fetch' >&2; exit 1")
+
+	_, err := NewWorktreeManager(repo, NewLockManager()).CheckoutPRWorktree(7)
+	if err == nil || !strings.Contains(err.Error(), "could not fetch") {
+		t.Fatalf("expected gh error, got %v", err)
+	}
+	worktrees, _, err := listWorktrees(repo, "git")
+	if err != nil {
+		t.Fatalf("listWorktrees: %v", err)
+	}
+	if len(worktrees) != 1 {
+		t.Fatalf("expected the failed worktree to be removed, got %+v", worktrees)
+	}
+}
+
+func TestCheckoutPRWorktree_ReusesSameRepoBranchWorktree(t *testing.T) {
+	ranIn := filepath.Join(t.TempDir(), "gh-ran-in")
+	repo := setupPRCheckoutTest(t,
+		`{"headRefName":"feature","isCrossRepository":false}`,
+		"pwd > '"+ranIn+"'")
+	runTestGit(t, repo, "branch", "feature")
+	existing := filepath.Join(t.TempDir(), "feature")
+	runTestGit(t, repo, "worktree", "add", "-q", existing, "feature")
+
+	got, err := NewWorktreeManager(repo, NewLockManager()).CheckoutPRWorktree(3)
+	if err != nil {
+		t.Fatalf("CheckoutPRWorktree: %v", err)
+	}
+	if !sameRealPath(got.Path, existing) {
+		t.Fatalf("expected reuse of %s, got %s", existing, got.Path)
+	}
+	dir, err := os.ReadFile(ranIn)
+	if err != nil || !sameRealPath(strings.TrimSpace(string(dir)), existing) {
+		t.Fatalf("expected gh pr checkout to update %s, ran in %q (%v)", existing, dir, err)
+	}
+}
+
+func TestCheckoutPRWorktree_ReusesForkPRWorktree(t *testing.T) {
+	repo := setupPRCheckoutTest(t,
+		`{"headRefName":"main","isCrossRepository":true,"headRepositoryOwner":{"login":"fork"}}`,
+		"git checkout -q -B fork/main && git config branch.fork/main.merge refs/pull/42/head")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	first, err := mgr.CheckoutPRWorktree(42)
+	if err != nil {
+		t.Fatalf("first CheckoutPRWorktree: %v", err)
+	}
+	second, err := mgr.CheckoutPRWorktree(42)
+	if err != nil {
+		t.Fatalf("second CheckoutPRWorktree: %v", err)
+	}
+	if !sameRealPath(first.Path, second.Path) || second.Branch != "fork/main" || second.PRNumber != 42 {
+		t.Fatalf("expected reuse of %+v, got %+v", first, second)
+	}
+	worktrees, _, err := listWorktrees(repo, "git")
+	if err != nil {
+		t.Fatalf("listWorktrees: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("expected one PR worktree, got %+v", worktrees)
+	}
+}
@@ -25,7 +25,8 @@ func newPRCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pr <number>",
 		Short: "Select or create a branch worktree by pull request number",
-		Long: "Resolves a pull request number to its head branch and then runs the same worktree flow as `wtx checkout`.\n\n" +
+		Long: "Resolves a pull request number to its head branch and then runs the same worktree flow as `wtx checkout`. " +
+			"Pull requests from forks are fetched with `gh pr checkout` into a new worktree first.\n\n" +
 			"Requires `gh` and a GitHub-backed repository.",
 		Example: strings.Join([]string{
 			"  wtx pr 123",
@@ -45,11 +46,23 @@ func newPRCommand() *cobra.Command {
 				return usageError(cmd, err.Error())
 			}
 
-			branch, err := resolvePRBranchWithSpinner(number)
+			head, err := resolvePRHeadWithSpinner(number)
 			if err != nil {
 				return err
 			}
-			return runCheckout(branch, false, "", nil, os.Args)
+			branch := head.HeadRefName
+			if head.IsCrossRepository {
+				// A fork's branch isn't on our remote; let gh fetch it into a
+				// fresh worktree, then open that worktree's local branch.
+				if err := runCheckoutStep(fmt.Sprintf("Checking out PR #%d", number), func() error {
+					created, err := NewWorktreeManager("", NewLockManager()).CheckoutPRWorktree(number)
+					branch = created.Branch
+					return err
+				}); err != nil {
+					return err
+				}
+			}
+			return runCheckout(branch, false, "", nil, "", os.Args)
 		},
 	}
 	return cmd
@@ -68,28 +81,33 @@ func parsePRNumber(raw string) (int, error) {
 }
 
 type ghPRBranchResult struct {
-	HeadRefName string `json:"headRefName"`
-	State       string `json:"state"`
+	HeadRefName         string `json:"headRefName"`
+	State               string `json:"state"`
+	IsCrossRepository   bool   `json:"isCrossRepository"`
+	HeadRepositoryOwner struct {
+		Login string `json:"login"`
+	} `json:"headRepositoryOwner"`
 }
 
-func resolvePRBranchWithSpinner(number int) (string, error) {
+func resolvePRHeadWithSpinner(number int) (ghPRBranchResult, error) {
 	stop := startDelayedSpinner(prResolveSpinnerMessage, prResolveSpinnerDelay)
 	defer stop()
-	return resolvePRBranch(number)
-}
-
-func resolvePRBranch(number int) (string, error) {
-	if number <= 0 {
-		return "", errors.New("pull request number required")
-	}
 	_, repoRoot, err := requireGitContext("")
 	if err != nil {
-		return "", err
+		return ghPRBranchResult{}, err
 	}
+	return resolvePRHead(repoRoot, number)
+}
 
+// resolvePRHead looks up a pull request's head branch and whether it comes
+// from a fork.
+func resolvePRHead(repoRoot string, number int) (ghPRBranchResult, error) {
+	if number <= 0 {
+		return ghPRBranchResult{}, errors.New("pull request number required")
+	}
 	ghBin, err := exec.LookPath("gh")
 	if err != nil {
-		return "", errors.New("`gh` not installed; install GitHub CLI to use `wtx pr`")
+		return ghPRBranchResult{}, errors.New("`gh` not installed; install GitHub CLI to use `wtx pr`")
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), prResolveTimeout)
 	defer cancel()
@@ -97,29 +115,30 @@ func resolvePRBranch(number int) (string, error) {
 		ctx,
 		ghBin,
 		"pr", "view", strconv.Itoa(number),
-		"--json", "headRefName,state",
+		"--json", "headRefName,state,isCrossRepository,headRepositoryOwner",
 	)
 	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			return "", fmt.Errorf("resolving PR #%d timed out after %s", number, prResolveTimeout.Round(time.Second))
+			return ghPRBranchResult{}, fmt.Errorf("resolving PR #%d timed out after %s", number, prResolveTimeout.Round(time.Second))
 		}
 		msg := strings.TrimSpace(string(out))
 		if msg != "" {
-			return "", fmt.Errorf("failed to resolve PR #%d: %s", number, msg)
+			return ghPRBranchResult{}, fmt.Errorf("failed to resolve PR #%d: %s", number, msg)
 		}
-		return "", fmt.Errorf("failed to resolve PR #%d: %w", number, err)
+		return ghPRBranchResult{}, fmt.Errorf("failed to resolve PR #%d: %w", number, err)
 	}
 	var result ghPRBranchResult
 	if err := json.Unmarshal(out, &result); err != nil {
-		return "", fmt.Errorf("failed to parse PR #%d details: %w", number, err)
+		return ghPRBranchResult{}, fmt.Errorf("failed to parse PR #%d details: %w", number, err)
 	}
-	branch := strings.TrimSpace(result.HeadRefName)
-	if branch == "" {
-		return "", fmt.Errorf("PR #%d has no head branch", number)
+	result.HeadRefName = strings.TrimSpace(result.HeadRefName)
+	if result.HeadRefName == "" {
+		return ghPRBranchResult{}, fmt.Errorf("PR #%d has no head branch", number)
 	}
-	return branch, nil
+	return result, nil
 }
 
 func startDelayedSpinner(message string, delay time.Duration) func() {
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+const (
+	ioctlGetTermios = 0
+	ioctlSetTermios = 0
+)
+
+func openPTY() (*os.File, *os.File, error) {
+	return nil, nil, errors.New("pseudo-terminals are not supported on this platform")
+}
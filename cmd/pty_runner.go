@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ptyView is how a PTY-proxied agent is shown in the current terminal. Only
+// setSize and copyOutput are required.
+type ptyView struct {
+	// setSize applies the terminal size to the agent's PTY.
+	setSize func(master *os.File, ws *unix.Winsize)
+	// copyOutput pumps the agent's output until the PTY closes.
+	copyOutput func(master io.Reader)
+	// start draws any chrome once the terminal is raw; stop removes it before
+	// the terminal is restored.
+	start func()
+	stop  func()
+	// resized runs after a SIGWINCH has been applied to the PTY.
+	resized func(ws *unix.Winsize)
+	// tick runs every tickEvery while the agent is running.
+	tick      func()
+	tickEvery time.Duration
+}
+
+// runOnPTY runs runCmd in workDir on a new PTY sized from sizeFD, locks the
+// worktree to the agent, and proxies stdin and output through view until the
+// agent exits. started is false when the agent never ran; err then explains
+// why, otherwise it reports the agent's failure.
+func (r *Runner) runOnPTY(worktreePath string, workDir string, branch string, lock *WorktreeLock, runCmd string, sizeFD int, view ptyView) (bool, error) {
+	size, err := unix.IoctlGetWinsize(sizeFD, unix.TIOCGWINSZ)
+	if err != nil {
+		return false, fmt.Errorf("the agent needs a terminal: %w", err)
+	}
+	master, slave, err := openPTY()
+	if err != nil {
+		return false, err
+	}
+	defer master.Close()
+	view.setSize(master, size)
+
+	cmd := exec.Command("/bin/sh", "-lc", commandToRun(false, runCmd))
+	cmd.Dir = workDir
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		_ = slave.Close()
+		return false, err
+	}
+	_ = slave.Close()
+	boundLock, err := r.lockWorktreeForPID(worktreePath, cmd.Process.Pid, lock)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_, _ = cmd.Process.Wait()
+		return false, err
+	}
+	if boundLock != nil {
+		defer boundLock.Release()
+	}
+	activateWorktreeUI(worktreePath, branch)
+	started := recordAgentLaunched(worktreePath, branch)
+
+	stdinFD := int(os.Stdin.Fd())
+	restore, err := makeRaw(stdinFD)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_, _ = cmd.Process.Wait()
+		return false, err
+	}
+	if view.start != nil {
+		view.start()
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		var tick <-chan time.Time
+		if view.tick != nil && view.tickEvery > 0 {
+			ticker := time.NewTicker(view.tickEvery)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-done:
+				return
+			case <-winch:
+				if ws, err := unix.IoctlGetWinsize(sizeFD, unix.TIOCGWINSZ); err == nil {
+					view.setSize(master, ws)
+					if view.resized != nil {
+						view.resized(ws)
+					}
+				}
+			case <-tick:
+				view.tick()
+			}
+		}
+	}()
+	pumpDone := pumpStdin(master, stdinFD, done)
+	copyDone := make(chan struct{})
+	go func() {
+		view.copyOutput(master)
+		close(copyDone)
+	}()
+
+	runErr := cmd.Wait()
+	recordAgentExited(worktreePath, branch, started, agentExitCode(runErr))
+	close(done)
+	signal.Stop(winch)
+	// Stop reading stdin so keystrokes after the agent exits, like the Enter
+	// pauseForAgentOutput waits for, stay with wtx.
+	<-pumpDone
+	// The PTY read side returns once the child and its descendants close it;
+	// don't hang on a background process that keeps it open.
+	select {
+	case <-copyDone:
+	case <-time.After(200 * time.Millisecond):
+	}
+	if view.stop != nil {
+		view.stop()
+	}
+	restore()
+	pauseForAgentOutput(worktreePath, os.Stdin, os.Stdout)
+	if runErr != nil {
+		return true, fmt.Errorf("worktree command failed: %w", runErr)
+	}
+	return true, nil
+}
+
+// stdinPollInterval bounds how long pumpStdin takes to notice stop.
+const stdinPollInterval = 50 * time.Millisecond
+
+// pumpStdin copies fd to dst until stop is closed, fd hits EOF, or dst fails.
+// It polls rather than blocking in read so it can be stopped without
+// consuming another keystroke.
+func pumpStdin(dst io.Writer, fd int, stop <-chan struct{}) <-chan struct{} {
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		buf := make([]byte, 4096)
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			ready, err := unix.Poll(fds, int(stdinPollInterval/time.Millisecond))
+			if errors.Is(err, unix.EINTR) || (err == nil && ready == 0) {
+				continue
+			}
+			if err != nil {
+				return
+			}
+			n, err := unix.Read(fd, buf)
+			if errors.Is(err, unix.EINTR) || errors.Is(err, unix.EAGAIN) {
+				continue
+			}
+			if n <= 0 || err != nil {
+				return
+			}
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+	}()
+	return finished
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestPumpStdin_StopsWithoutConsumingLaterInput(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	var dst lockedBuffer
+	stop := make(chan struct{})
+	finished := pumpStdin(&dst, int(r.Fd()), stop)
+
+	if _, err := w.Write([]byte("hi")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for dst.String() != "hi" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := dst.String(); got != "hi" {
+		t.Fatalf("expected input forwarded, got %q", got)
+	}
+
+	close(stop)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatalf("expected pump to stop")
+	}
+	if _, err := w.Write([]byte("\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 1)
+	if n, err := r.Read(buf); err != nil || n != 1 || buf[0] != '\n' {
+		t.Fatalf("expected the Enter after stop to stay unread, got %q err=%v", buf[:n], err)
+	}
+	if got := dst.String(); got != "hi" {
+		t.Fatalf("expected nothing forwarded after stop, got %q", got)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newRepairCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repair",
+		Short: "Run git worktree repair for this repository",
+		Long:  "Runs `git worktree repair` from the primary checkout for all managed worktrees. Use it after moving the repository or its worktrees.",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runRepair(os.Stdout, NewWorktreeManager("", NewLockManager()))
+		},
+	}
+}
+
+func runRepair(w io.Writer, mgr *WorktreeManager) error {
+	out, err := mgr.RepairWorktrees()
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		fmt.Fprintln(w, "Worktree links are already consistent.")
+		return nil
+	}
+	fmt.Fprintln(w, out)
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const repoConfigFileName = ".wtx.json"
+
+// repoConfig is the subset of Config a repository may override by committing
+// .wtx.json at its root. Unset fields fall back to ~/.wtx/config.json.
+type repoConfig struct {
+	AgentCommand     *string           `json:"agent_command,omitempty"`
+	NewBranchBaseRef *string           `json:"new_branch_base_ref,omitempty"`
+	PostCreateHook   *string           `json:"post_create_hook,omitempty"`
+	CopyOnCreate     []string          `json:"copy_on_create,omitempty"`
+	AgentSubdir      *string           `json:"agent_subdir,omitempty"`
+	GHEnv            map[string]string `json:"gh_env,omitempty"`
+}
+
+// findRepoConfigRoot walks up from dir to the nearest worktree root. It does
+// not shell out to git because LoadConfig is used while resolving git itself.
+func findRepoConfigRoot(dir string) (string, bool) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", false
+		}
+		dir = wd
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+			return abs, true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", false
+		}
+		abs = parent
+	}
+}
+
+func readRepoConfig(root string) (repoConfig, bool, error) {
+	path := filepath.Join(root, repoConfigFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return repoConfig{}, false, nil
+		}
+		return repoConfig{}, false, err
+	}
+	var rc repoConfig
+	if err := json.Unmarshal(data, &rc); err != nil {
+		return repoConfig{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	// The file is shared, so keys from a newer wtx must not break older ones.
+	if unknown := unknownRepoConfigKeys(data); len(unknown) > 0 {
+		logWarn("ignoring unknown keys in repo config", "path", path, "keys", strings.Join(unknown, ","))
+	}
+	if err := validateRepoConfig(rc); err != nil {
+		return repoConfig{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return rc, true, nil
+}
+
+// unknownRepoConfigKeys lists the top-level keys of data that repoConfig
+// doesn't define, sorted.
+func unknownRepoConfigKeys(data []byte) []string {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	known := map[string]bool{}
+	t := reflect.TypeOf(repoConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+	}
+	var unknown []string
+	for key := range raw {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func validateRepoConfig(rc repoConfig) error {
+	if rc.AgentCommand != nil && strings.TrimSpace(*rc.AgentCommand) == "" {
+		return errors.New("agent_command must not be empty")
+	}
+	for _, entry := range rc.CopyOnCreate {
+		if err := validateCopyOnCreateEntry(entry); err != nil {
+			return err
+		}
+	}
+	if rc.AgentSubdir != nil {
+		if err := validateAgentSubdir(*rc.AgentSubdir); err != nil {
+			return err
+		}
+	}
+	for key := range rc.GHEnv {
+		if err := validateGHEnvKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateAgentSubdir(subdir string) error {
+	subdir = strings.TrimSpace(subdir)
+	if subdir == "" {
+		return nil
+	}
+	if filepath.IsAbs(subdir) {
+		return fmt.Errorf("agent_subdir %q must be relative to the worktree root", subdir)
+	}
+	clean := filepath.Clean(subdir)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("agent_subdir %q escapes the worktree root", subdir)
+	}
+	return nil
+}
+
+func validateGHEnvKey(key string) error {
+	if strings.TrimSpace(key) == "" || strings.ContainsAny(key, "= \t") {
+		return fmt.Errorf("gh_env key %q is not a valid variable name", key)
+	}
+	return nil
+}
+
+func validateCopyOnCreateEntry(entry string) error {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return errors.New("copy_on_create entries must not be empty")
+	}
+	if filepath.IsAbs(entry) {
+		return fmt.Errorf("copy_on_create entry %q must be relative to the repo root", entry)
+	}
+	clean := filepath.Clean(entry)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("copy_on_create entry %q escapes the repo root", entry)
+	}
+	return nil
+}
+
+func applyRepoConfig(cfg *Config, rc repoConfig) {
+	if rc.AgentCommand != nil {
+		cfg.AgentCommand = strings.TrimSpace(*rc.AgentCommand)
+	}
+	if rc.NewBranchBaseRef != nil {
+		cfg.NewBranchBaseRef = strings.TrimSpace(*rc.NewBranchBaseRef)
+	}
+	if rc.PostCreateHook != nil {
+		cfg.PostCreateHook = strings.TrimSpace(*rc.PostCreateHook)
+	}
+	if rc.AgentSubdir != nil {
+		cfg.AgentSubdir = strings.TrimSpace(*rc.AgentSubdir)
+	}
+	if rc.GHEnv != nil {
+		merged := make(map[string]string, len(cfg.GHEnv)+len(rc.GHEnv))
+		for key, value := range cfg.GHEnv {
+			merged[key] = value
+		}
+		for key, value := range rc.GHEnv {
+			merged[key] = value
+		}
+		cfg.GHEnv = merged
+	}
+	if rc.CopyOnCreate != nil {
+		cfg.CopyOnCreate = make([]string, 0, len(rc.CopyOnCreate))
+		for _, entry := range rc.CopyOnCreate {
+			cfg.CopyOnCreate = append(cfg.CopyOnCreate, filepath.Clean(strings.TrimSpace(entry)))
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 type Runner struct {
@@ -35,7 +39,7 @@ func (r *Runner) RunInWorktree(worktreePath string, branch string, lock *Worktre
 		return RunResult{}, err
 	}
 
-	cfg, err := LoadConfig()
+	cfg, err := loadConfigForDir(worktreePath)
 	if err != nil {
 		return RunResult{}, err
 	}
@@ -43,30 +47,93 @@ func (r *Runner) RunInWorktree(worktreePath string, branch string, lock *Worktre
 	if err != nil {
 		return RunResult{}, err
 	}
+	if err := checkAgentInstalled(runCmd); err != nil {
+		return RunResult{}, err
+	}
+	workDir, warning := agentWorkDir(worktreePath, cfg.AgentSubdir)
+	if warning != "" {
+		fmt.Fprintln(os.Stderr, "wtx warning:", warning)
+	}
+	runCmd = r.expandAgentCommand(runCmd, cfg, worktreePath, branch)
+	env, envErr := loadWorktreeEnv(worktreePath)
+	if envErr != nil {
+		fmt.Fprintln(os.Stderr, "wtx warning:", envErr)
+		if warning == "" {
+			warning = envErr.Error()
+		}
+	}
+	runCmd = withAgentEnv(runCmd, env)
+
+	result, err := r.runInWorktree(worktreePath, workDir, branch, lock, false, runCmd)
+	if warning != "" && result.Warning == "" {
+		result.Warning = warning
+	}
+	return result, err
+}
 
-	return r.runInWorktree(worktreePath, branch, lock, false, runCmd)
+// expandAgentCommand fills the {branch}, {path} and {base} placeholders of an
+// agent command. Values are single-quoted so odd branch names stay one
+// argument under /bin/sh -c.
+func (r *Runner) expandAgentCommand(runCmd string, cfg Config, worktreePath string, branch string) string {
+	if !strings.Contains(runCmd, "{") {
+		return runCmd
+	}
+	base := ""
+	if strings.Contains(runCmd, "{base}") {
+		base = newBranchBaseRef(cfg.BaseRefRules, branch, func() string {
+			if v := strings.TrimSpace(cfg.NewBranchBaseRef); v != "" {
+				return v
+			}
+			return NewWorktreeManager(worktreePath, r.lockMgr).ResolveBaseRefForNewBranch()
+		})
+	}
+	return expandAgentPlaceholders(runCmd, branch, worktreePath, base)
+}
+
+func expandAgentPlaceholders(runCmd string, branch string, path string, base string) string {
+	return strings.NewReplacer(
+		"{branch}", shellQuote(strings.TrimSpace(branch)),
+		"{path}", shellQuote(strings.TrimSpace(path)),
+		"{base}", shellQuote(strings.TrimSpace(base)),
+	).Replace(runCmd)
 }
 
 func (r *Runner) RunShellInWorktree(worktreePath string, branch string, lock *WorktreeLock) (RunResult, error) {
-	return r.runInWorktree(worktreePath, branch, lock, true, "")
+	return r.runInWorktree(worktreePath, worktreePath, branch, lock, true, "")
+}
+
+// agentWorkDir resolves the directory the agent starts in. A missing subdir
+// falls back to the worktree root with a warning rather than failing the open.
+func agentWorkDir(worktreePath string, subdir string) (string, string) {
+	subdir = strings.TrimSpace(subdir)
+	if subdir == "" {
+		return worktreePath, ""
+	}
+	dir := filepath.Join(worktreePath, subdir)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return worktreePath, fmt.Sprintf("agent_subdir %q not found in %s; starting in the worktree root", subdir, worktreePath)
+	}
+	return dir, ""
 }
 
-func (r *Runner) runInWorktree(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+func (r *Runner) runInWorktree(worktreePath string, workDir string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
 		return RunResult{}, errors.New("worktree path required")
 	}
 	branch = strings.TrimSpace(branch)
+	allowDirenv(worktreePath, workDir)
+	recordEvent(worktreePath, wtxEvent{Event: eventWorktreeOpened, Branch: branch})
 
 	if tmuxAvailable() {
-		return r.runInTmux(worktreePath, branch, lock, openShell, runCmd)
+		return r.runInTmux(worktreePath, workDir, branch, lock, openShell, runCmd)
 	}
-	return r.runWithoutTmux(worktreePath, branch, lock, openShell, runCmd)
+	return r.runWithoutTmux(worktreePath, workDir, branch, lock, openShell, runCmd)
 }
 
-func (r *Runner) runInTmux(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+func (r *Runner) runInTmux(worktreePath string, workDir string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
 	paneID, _ := currentPaneID()
-	newPaneID, err := splitCommandPane(worktreePath, commandToRunInTmux(worktreePath, openShell, runCmd))
+	newPaneID, err := splitCommandPane(workDir, commandToRunInTmux(worktreePath, openShell, runCmd, lock.Observer()))
 	if err != nil {
 		return RunResult{}, err
 	}
@@ -89,8 +156,57 @@ func (r *Runner) runInTmux(worktreePath string, branch string, lock *WorktreeLoc
 	return RunResult{Started: true}, nil
 }
 
-func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
-	cmd := shellCommand(worktreePath, commandToRun(openShell, runCmd))
+// StartInTmuxWindow launches the agent in a detached tmux window and binds
+// lock to the new pane so the current UI keeps running. workDir is where the
+// agent starts; empty means the worktree root.
+func (r *Runner) StartInTmuxWindow(worktreePath string, workDir string, branch string, lock *WorktreeLock, runCmd string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	if !tmuxAvailable() {
+		return errors.New("tmux unavailable")
+	}
+	name := strings.TrimSpace(branch)
+	if name == "" {
+		name = filepath.Base(worktreePath)
+	}
+	if strings.TrimSpace(workDir) == "" {
+		workDir = worktreePath
+	}
+	allowDirenv(worktreePath, workDir)
+	paneID, err := newCommandWindow(workDir, name, commandToRunInTmux(worktreePath, false, runCmd, lock.Observer()))
+	if err != nil {
+		return err
+	}
+	if err := r.lockWorktreeForPane(worktreePath, paneID, lock); err != nil {
+		_ = exec.Command("tmux", "kill-pane", "-t", paneID).Run()
+		return err
+	}
+	recordRecentBranchForWorktree(worktreePath, branch)
+	recordEvent(worktreePath, wtxEvent{Event: eventWorktreeOpened, Branch: branch})
+	return nil
+}
+
+func (r *Runner) runWithoutTmux(worktreePath string, workDir string, branch string, lock *WorktreeLock, openShell bool, runCmd string) (RunResult, error) {
+	if command, ok := newTabCommand(worktreePath, branch, lock); ok && !openShell {
+		// Keep the lock until the new tab's wtx takes it over, so nobody can
+		// grab the worktree in between and a failed tab still runs here.
+		if err := openInNewTabFn(command); err != nil {
+			fmt.Fprintln(os.Stderr, "wtx warning: opening a new tab failed, running here:", err)
+		} else if waitForLockHandoff(lock, newTabHandoffTimeout) {
+			return RunResult{Started: true, Warning: "opened in a new terminal tab"}, nil
+		} else {
+			fmt.Fprintln(os.Stderr, "wtx warning: the new tab did not start the agent, running here")
+		}
+	}
+	if statusHeaderEnabled && !openShell && isInteractiveTerminal(os.Stdin) && isInteractiveTerminal(os.Stdout) {
+		return r.runWithStatusHeader(worktreePath, workDir, branch, lock, runCmd)
+	}
+	if useAgentPTY(worktreePath, openShell) {
+		return r.runWithAgentPTY(worktreePath, workDir, branch, lock, runCmd)
+	}
+	cmd := shellCommand(workDir, commandToRun(openShell, runCmd))
 	if err := cmd.Start(); err != nil {
 		return RunResult{}, err
 	}
@@ -107,8 +223,16 @@ func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *Worktr
 	}
 
 	activateWorktreeUI(worktreePath, branch)
+	var started time.Time
+	if !openShell {
+		started = recordAgentLaunched(worktreePath, branch)
+	}
 
 	runErr := cmd.Wait()
+	if !openShell {
+		recordAgentExited(worktreePath, branch, started, agentExitCode(runErr))
+		pauseForAgentOutput(worktreePath, os.Stdin, os.Stdout)
+	}
 	result := RunResult{Started: true, Warning: "tmux unavailable; running in current terminal"}
 	if runErr != nil {
 		return result, fmt.Errorf("worktree command failed: %w", runErr)
@@ -116,6 +240,52 @@ func (r *Runner) runWithoutTmux(worktreePath string, branch string, lock *Worktr
 	return result, nil
 }
 
+// pauseForAgentOutput waits for enter after the agent exits when
+// keep_agent_output is set, so its last lines can be read before wtx redraws.
+// Non-interactive runs never wait.
+func pauseForAgentOutput(worktreePath string, in *os.File, out io.Writer) {
+	if !isInteractiveTerminal(in) {
+		return
+	}
+	cfg, err := loadConfigForDir(worktreePath)
+	if err != nil || !cfg.KeepAgentOutput {
+		return
+	}
+	waitForEnter(in, out)
+}
+
+func waitForEnter(r io.Reader, w io.Writer) {
+	fmt.Fprint(w, "\nPress enter to return to wtx")
+	_, _ = bufio.NewReader(r).ReadString('\n')
+}
+
+// allowDirenv runs `direnv allow` for the .envrc in the worktree root and in
+// workDir when direnv_allow_on_open is set, so direnv doesn't block the agent's
+// shell. Missing direnv or .envrc is not an error.
+func allowDirenv(worktreePath string, workDir string) {
+	cfg, err := loadConfigForDir(worktreePath)
+	if err != nil || !cfg.DirenvAllowOnOpen {
+		return
+	}
+	direnvPath, err := exec.LookPath("direnv")
+	if err != nil {
+		return
+	}
+	dirs := []string{worktreePath}
+	if workDir = strings.TrimSpace(workDir); workDir != "" && workDir != worktreePath {
+		dirs = append(dirs, workDir)
+	}
+	for _, dir := range dirs {
+		envrc := filepath.Join(dir, ".envrc")
+		if _, err := os.Stat(envrc); err != nil {
+			continue
+		}
+		if out, err := exec.Command(direnvPath, "allow", envrc).CombinedOutput(); err != nil {
+			logError("direnv allow failed", "path", envrc, "err", err, "output", strings.TrimSpace(string(out)))
+		}
+	}
+}
+
 func shellCommand(worktreePath string, runCmd string) *exec.Cmd {
 	cmd := exec.Command("/bin/sh", "-lc", runCmd)
 	cmd.Dir = worktreePath
@@ -132,7 +302,9 @@ func commandToRun(openShell bool, runCmd string) string {
 	return runCmd
 }
 
-func commandToRunInTmux(worktreePath string, openShell bool, runCmd string) string {
+// commandToRunInTmux wraps runCmd with the agent lifecycle hooks. observe
+// tells them the pane's lock is an observer lock.
+func commandToRunInTmux(worktreePath string, openShell bool, runCmd string, observe bool) string {
 	if openShell {
 		return loginShellCommand
 	}
@@ -142,6 +314,10 @@ func commandToRunInTmux(worktreePath string, openShell bool, runCmd string) stri
 	}
 	startCmd := shellQuote(bin) + " tmux-agent-start --worktree " + shellQuote(worktreePath)
 	exitCmd := shellQuote(bin) + " tmux-agent-exit --worktree " + shellQuote(worktreePath)
+	if observe {
+		startCmd += " --observe"
+		exitCmd += " --observe"
+	}
 	return startCmd + "; " +
 		"finish(){ code=\"$1\"; " + exitCmd + " --code \"$code\"; exec \"${SHELL:-/bin/sh}\" -l; }; " +
 		"trap 'finish 130' INT TERM; " +
@@ -169,8 +345,11 @@ func (r *Runner) lockWorktreeForPane(worktreePath string, paneID string, existin
 	if err != nil {
 		return err
 	}
-	_, err = r.lockWorktreeForPID(worktreePath, pid, existingLock)
-	return err
+	if _, err := r.lockWorktreeForPID(worktreePath, pid, existingLock); err != nil {
+		return err
+	}
+	rememberAgentPane(worktreePath, paneID)
+	return nil
 }
 
 func (r *Runner) lockWorktreeForPID(worktreePath string, pid int, existingLock *WorktreeLock) (*WorktreeLock, error) {
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAgentWorkDir(t *testing.T) {
+	worktree := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(worktree, "packages", "web"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		subdir      string
+		wantDir     string
+		wantWarning bool
+	}{
+		{name: "unset", subdir: "", wantDir: worktree},
+		{name: "existing", subdir: "packages/web", wantDir: filepath.Join(worktree, "packages", "web")},
+		{name: "missing", subdir: "packages/api", wantDir: worktree, wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, warning := agentWorkDir(worktree, tt.subdir)
+			if dir != tt.wantDir {
+				t.Fatalf("expected dir %q, got %q", tt.wantDir, dir)
+			}
+			if tt.wantWarning != (warning != "") {
+				t.Fatalf("unexpected warning %q", warning)
+			}
+			if tt.wantWarning && !strings.Contains(warning, tt.subdir) {
+				t.Fatalf("expected warning to name the subdir, got %q", warning)
+			}
+		})
+	}
+}
+
+func TestPauseForAgentOutput_SkipsNonInteractiveAndWaitsForEnter(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(configDirOverrideEnv, dir)
+	writeTestFile(t, filepath.Join(dir, "config.json"), `{"agent_command":"claude","keep_agent_output":true}`)
+	in, err := os.CreateTemp(dir, "stdin")
+	if err != nil {
+		t.Fatalf("create stdin: %v", err)
+	}
+	defer in.Close()
+	var out bytes.Buffer
+	pauseForAgentOutput(dir, in, &out)
+	if out.Len() != 0 {
+		t.Fatalf("expected no prompt for non-interactive stdin, got %q", out.String())
+	}
+
+	rest := strings.NewReader("\n")
+	waitForEnter(rest, &out)
+	if !strings.Contains(out.String(), "Press enter to return to wtx") {
+		t.Fatalf("expected return prompt, got %q", out.String())
+	}
+}
+
+func TestUseAgentPTY_RequiresTerminalAndConfig(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if err := SaveConfig(Config{AgentPTY: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	dir := t.TempDir()
+	if useAgentPTY(dir, true) {
+		t.Fatalf("expected shells to never use the agent pty")
+	}
+	if !isInteractiveTerminal(os.Stdin) && useAgentPTY(dir, false) {
+		t.Fatalf("expected non-terminal stdin to skip the agent pty")
+	}
+}
+
+func TestAllowDirenv_AllowsEnvrcWhenEnabled(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	bin := t.TempDir()
+	logPath := filepath.Join(bin, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + logPath + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "direnv"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake direnv: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	wt := t.TempDir()
+	envrc := filepath.Join(wt, ".envrc")
+	if err := os.WriteFile(envrc, []byte("export A=1\n"), 0o644); err != nil {
+		t.Fatalf("write .envrc: %v", err)
+	}
+
+	allowDirenv(wt, wt)
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("expected direnv to be skipped by default, stat err=%v", err)
+	}
+
+	if err := SaveConfig(Config{DirenvAllowOnOpen: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	allowDirenv(wt, filepath.Join(wt, "sub"))
+	calls, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected direnv to run: %v", err)
+	}
+	if strings.TrimSpace(string(calls)) != "allow "+envrc {
+		t.Fatalf("expected one allow of %s, got %q", envrc, calls)
+	}
+}
+
+func TestExpandAgentPlaceholders_QuotesValues(t *testing.T) {
+	got := expandAgentPlaceholders("claude --context {branch} --dir {path} --base {base}", "feat/it's;rm -rf", "/tmp/wt 1", "origin/main")
+	want := `claude --context 'feat/it'\''s;rm -rf' --dir '/tmp/wt 1' --base 'origin/main'`
+	if got != want {
+		t.Fatalf("unexpected expansion\n got: %s\nwant: %s", got, want)
+	}
+	out, err := exec.Command("/bin/sh", "-c", "printf '%s\\n' "+expandAgentPlaceholders("{branch}", "a b;$(echo x)", "", "")).Output()
+	if err != nil {
+		t.Fatalf("sh: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "a b;$(echo x)" {
+		t.Fatalf("expected the branch to reach the agent verbatim, got %q", out)
+	}
+}
@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const stateFileName = "state.json"
+
+type wtxState struct {
+	Repos map[string]repoState `json:"repos,omitempty"`
+	// Notes holds per-worktree notes keyed by worktreeID, so they follow the
+	// directory across branch checkouts.
+	Notes map[string]string `json:"notes,omitempty"`
+	// AgentPanes remembers the tmux pane each worktree's agent was started
+	// in, keyed by worktreeID, so reopening can attach instead of duplicating.
+	AgentPanes map[string]agentPane `json:"agent_panes,omitempty"`
+	// Adopted maps the worktreeID of each worktree adopted from outside the
+	// <repo>.wt layout to its path; wtx manages those like its own.
+	Adopted map[string]string `json:"adopted,omitempty"`
+}
+
+type agentPane struct {
+	SessionID string `json:"session_id"`
+	WindowID  string `json:"window_id"`
+	PaneID    string `json:"pane_id"`
+}
+
+type repoState struct {
+	Archived []archivedBranch `json:"archived,omitempty"`
+}
+
+type archivedBranch struct {
+	Branch         string `json:"branch"`
+	Path           string `json:"path,omitempty"`
+	ArchivedAtUnix int64  `json:"archived_at_unix"`
+}
+
+func statePath() (string, error) {
+	home, err := wtxHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, stateFileName), nil
+}
+
+func readState() (wtxState, error) {
+	path, err := statePath()
+	if err != nil {
+		return wtxState{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return wtxState{}, nil
+		}
+		return wtxState{}, err
+	}
+	var state wtxState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return wtxState{}, err
+	}
+	return state, nil
+}
+
+// mutateState runs a read-modify-write of state.json under an flock on a
+// sidecar, so concurrent wtx processes don't drop each other's changes. fn
+// reports whether it changed anything; nothing is written otherwise.
+func mutateState(fn func(state *wtxState) bool) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := flockSidecar(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	state, err := readState()
+	if err != nil {
+		return err
+	}
+	if !fn(&state) {
+		return nil
+	}
+	return writeState(path, state)
+}
+
+// writeState replaces path through a unique temp file in the same directory,
+// so the rename is atomic and writers never share a temp name.
+func writeState(path string, state wtxState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	tmp, err := os.CreateTemp(filepath.Dir(path), stateFileName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func archivedBranchesForRepo(repoRoot string) ([]archivedBranch, error) {
+	repoRoot = strings.TrimSpace(repoRoot)
+	if repoRoot == "" {
+		return nil, errors.New("repo root required")
+	}
+	state, err := readState()
+	if err != nil {
+		return nil, err
+	}
+	archived := append([]archivedBranch(nil), state.Repos[repoRoot].Archived...)
+	sort.SliceStable(archived, func(i, j int) bool {
+		return archived[i].ArchivedAtUnix > archived[j].ArchivedAtUnix
+	})
+	return archived, nil
+}
+
+func recordArchivedBranch(repoRoot string, branch string, path string) error {
+	repoRoot = strings.TrimSpace(repoRoot)
+	branch = strings.TrimSpace(branch)
+	if repoRoot == "" || branch == "" || branch == "detached" {
+		return nil
+	}
+	return mutateState(func(state *wtxState) bool {
+		if state.Repos == nil {
+			state.Repos = map[string]repoState{}
+		}
+		repo := state.Repos[repoRoot]
+		kept := make([]archivedBranch, 0, len(repo.Archived)+1)
+		for _, entry := range repo.Archived {
+			if entry.Branch != branch {
+				kept = append(kept, entry)
+			}
+		}
+		kept = append(kept, archivedBranch{
+			Branch:         branch,
+			Path:           strings.TrimSpace(path),
+			ArchivedAtUnix: time.Now().Unix(),
+		})
+		repo.Archived = kept
+		state.Repos[repoRoot] = repo
+		return true
+	})
+}
+
+func clearArchivedBranch(repoRoot string, branch string) error {
+	repoRoot = strings.TrimSpace(repoRoot)
+	branch = strings.TrimSpace(branch)
+	if repoRoot == "" || branch == "" {
+		return nil
+	}
+	return mutateState(func(state *wtxState) bool {
+		repo, ok := state.Repos[repoRoot]
+		if !ok {
+			return false
+		}
+		kept := make([]archivedBranch, 0, len(repo.Archived))
+		for _, entry := range repo.Archived {
+			if entry.Branch != branch {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == len(repo.Archived) {
+			return false
+		}
+		repo.Archived = kept
+		if len(repo.Archived) == 0 {
+			delete(state.Repos, repoRoot)
+		} else {
+			state.Repos[repoRoot] = repo
+		}
+		return true
+	})
+}
+
+// worktreeNotes returns the notes for paths, keyed by path.
+func worktreeNotes(repoRoot string, paths []string) (map[string]string, error) {
+	state, err := readState()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(paths))
+	if len(state.Notes) == 0 {
+		return out, nil
+	}
+	for _, path := range paths {
+		id, err := worktreeID(repoRoot, path)
+		if err != nil {
+			continue
+		}
+		if note := state.Notes[id]; note != "" {
+			out[path] = note
+		}
+	}
+	return out, nil
+}
+
+// setWorktreeNote stores note for the worktree at path; an empty note removes
+// it.
+func setWorktreeNote(repoRoot string, path string, note string) error {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+	note = strings.TrimSpace(note)
+	return mutateState(func(state *wtxState) bool {
+		if note == "" {
+			if _, ok := state.Notes[id]; !ok {
+				return false
+			}
+			delete(state.Notes, id)
+			return true
+		}
+		if state.Notes == nil {
+			state.Notes = map[string]string{}
+		}
+		state.Notes[id] = note
+		return true
+	})
+}
+
+func recordAgentPane(repoRoot string, path string, pane agentPane) error {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+	return mutateState(func(state *wtxState) bool {
+		if state.AgentPanes == nil {
+			state.AgentPanes = map[string]agentPane{}
+		}
+		state.AgentPanes[id] = pane
+		return true
+	})
+}
+
+func agentPaneForWorktree(repoRoot string, path string) (agentPane, bool) {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return agentPane{}, false
+	}
+	state, err := readState()
+	if err != nil {
+		return agentPane{}, false
+	}
+	pane, ok := state.AgentPanes[id]
+	return pane, ok && strings.TrimSpace(pane.PaneID) != ""
+}
+
+func clearAgentPane(repoRoot string, path string) error {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+	return mutateState(func(state *wtxState) bool {
+		if _, ok := state.AgentPanes[id]; !ok {
+			return false
+		}
+		delete(state.AgentPanes, id)
+		return true
+	})
+}
+
+func recordAdoptedWorktree(repoRoot string, path string) error {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+	return mutateState(func(state *wtxState) bool {
+		if state.Adopted == nil {
+			state.Adopted = map[string]string{}
+		}
+		state.Adopted[id] = strings.TrimSpace(path)
+		return true
+	})
+}
+
+func worktreeAdopted(repoRoot string, path string) bool {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return false
+	}
+	state, err := readState()
+	if err != nil {
+		return false
+	}
+	_, ok := state.Adopted[id]
+	return ok
+}
+
+func clearAdoptedWorktree(repoRoot string, path string) error {
+	id, err := worktreeID(strings.TrimSpace(repoRoot), strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+	return mutateState(func(state *wtxState) bool {
+		if _, ok := state.Adopted[id]; !ok {
+			return false
+		}
+		delete(state.Adopted, id)
+		return true
+	})
+}
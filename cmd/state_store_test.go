@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestArchivedBranches_RecordAndClear(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := "/tmp/repo"
+
+	if err := recordArchivedBranch(repo, "feature/a", "/tmp/repo.wt/wt.1"); err != nil {
+		t.Fatalf("record feature/a: %v", err)
+	}
+	if err := recordArchivedBranch(repo, "feature/b", "/tmp/repo.wt/wt.2"); err != nil {
+		t.Fatalf("record feature/b: %v", err)
+	}
+	if err := recordArchivedBranch(repo, "feature/a", "/tmp/repo.wt/wt.3"); err != nil {
+		t.Fatalf("re-record feature/a: %v", err)
+	}
+	archived, err := archivedBranchesForRepo(repo)
+	if err != nil {
+		t.Fatalf("archivedBranchesForRepo: %v", err)
+	}
+	if len(archived) != 2 {
+		t.Fatalf("expected 2 archived branches, got %+v", archived)
+	}
+
+	if err := clearArchivedBranch(repo, "feature/a"); err != nil {
+		t.Fatalf("clear feature/a: %v", err)
+	}
+	archived, err = archivedBranchesForRepo(repo)
+	if err != nil {
+		t.Fatalf("archivedBranchesForRepo: %v", err)
+	}
+	if len(archived) != 1 || archived[0].Branch != "feature/b" {
+		t.Fatalf("expected only feature/b archived, got %+v", archived)
+	}
+}
+
+func TestArchivedBranches_IgnoresDetached(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := recordArchivedBranch("/tmp/repo", "detached", ""); err != nil {
+		t.Fatalf("record detached: %v", err)
+	}
+	archived, err := archivedBranchesForRepo("/tmp/repo")
+	if err != nil {
+		t.Fatalf("archivedBranchesForRepo: %v", err)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("expected no archived branches, got %+v", archived)
+	}
+}
+
+func TestWorktreeNotes_SetUpdateAndClear(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	wt := repo + ".wt/wt.1"
+
+	if err := setWorktreeNote(repo, wt, "  fixing the flaky test  "); err != nil {
+		t.Fatalf("set note: %v", err)
+	}
+	notes, err := worktreeNotes(repo, []string{wt, repo + ".wt/wt.2"})
+	if err != nil {
+		t.Fatalf("worktreeNotes: %v", err)
+	}
+	if notes[wt] != "fixing the flaky test" || len(notes) != 1 {
+		t.Fatalf("expected trimmed note for wt.1 only, got %+v", notes)
+	}
+	if err := setWorktreeNote(repo, wt, ""); err != nil {
+		t.Fatalf("clear note: %v", err)
+	}
+	if notes, _ := worktreeNotes(repo, []string{wt}); len(notes) != 0 {
+		t.Fatalf("expected note cleared, got %+v", notes)
+	}
+}
+
+func TestLiveAgentPane_ForgetsClosedPanes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "tmux"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("write fake tmux: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	repo := t.TempDir()
+	path := filepath.Join(repo, "wt.1")
+	want := agentPane{SessionID: "$1", WindowID: "@2", PaneID: "%3"}
+	if err := recordAgentPane(repo, path, want); err != nil {
+		t.Fatalf("recordAgentPane: %v", err)
+	}
+
+	alive := true
+	prev := tmuxPaneLocationFn
+	tmuxPaneLocationFn = func(paneID string) (agentPane, error) {
+		if !alive || paneID != want.PaneID {
+			return agentPane{}, errors.New("gone")
+		}
+		return want, nil
+	}
+	t.Cleanup(func() { tmuxPaneLocationFn = prev })
+
+	if got, ok := liveAgentPane(repo, path); !ok || got != want {
+		t.Fatalf("expected live pane %+v, got %+v ok=%v", want, got, ok)
+	}
+	alive = false
+	if _, ok := liveAgentPane(repo, path); ok {
+		t.Fatalf("expected closed pane to be reported gone")
+	}
+	if _, ok := agentPaneForWorktree(repo, path); ok {
+		t.Fatalf("expected closed pane to be forgotten")
+	}
+}
+
+func TestArchivedBranches_ConcurrentRecordsAllSurvive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := "/tmp/repo"
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := recordArchivedBranch(repo, "feature/"+strconv.Itoa(i), ""); err != nil {
+				t.Errorf("record %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	archived, err := archivedBranchesForRepo(repo)
+	if err != nil {
+		t.Fatalf("archivedBranchesForRepo: %v", err)
+	}
+	if len(archived) != 20 {
+		t.Fatalf("expected every concurrent record to survive, got %d", len(archived))
+	}
+}
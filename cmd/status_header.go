@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/sys/unix"
+)
+
+// statusHeaderEnabled is set by --status-header. Without tmux it runs the
+// agent behind a PTY and keeps a one-line branch/PR/CI header above it.
+var statusHeaderEnabled bool
+
+const statusHeaderRefresh = 5 * time.Second
+
+// runWithStatusHeader runs runCmd in workDir on a PTY one row shorter than the
+// terminal. The top row is reserved via a scroll region and origin mode, so
+// the agent's absolute cursor moves land below the header.
+func (r *Runner) runWithStatusHeader(worktreePath string, workDir string, branch string, lock *WorktreeLock, runCmd string) (RunResult, error) {
+	stdoutFD := int(os.Stdout.Fd())
+	size, err := unix.IoctlGetWinsize(stdoutFD, unix.TIOCGWINSZ)
+	if err != nil {
+		return RunResult{}, fmt.Errorf("status header needs a terminal: %w", err)
+	}
+	screen := &headerScreen{out: os.Stdout, rows: int(size.Row), cols: int(size.Col)}
+	started, err := r.runOnPTY(worktreePath, workDir, branch, lock, runCmd, stdoutFD, ptyView{
+		setSize:    setPTYSize,
+		copyOutput: screen.copyFrom,
+		start:      func() { screen.setup(buildTmuxStatusLine(worktreePath, "")) },
+		stop:       screen.teardown,
+		resized:    func(ws *unix.Winsize) { screen.resize(int(ws.Row), int(ws.Col)) },
+		tick:       func() { screen.drawHeader(buildTmuxStatusLine(worktreePath, "")) },
+		tickEvery:  statusHeaderRefresh,
+	})
+	if !started {
+		return RunResult{}, err
+	}
+	return RunResult{Started: true}, err
+}
+
+func setPTYSize(master *os.File, size *unix.Winsize) {
+	ws := *size
+	if ws.Row > 1 {
+		ws.Row--
+	}
+	_ = unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ, &ws)
+}
+
+func makeRaw(fd int) (func(), error) {
+	orig, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	raw := *orig
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { _ = unix.IoctlSetTermios(fd, ioctlSetTermios, orig) }, nil
+}
+
+// headerScreen serializes agent output and header redraws so a redraw never
+// lands in the middle of one of the agent's escape sequences.
+type headerScreen struct {
+	mu       sync.Mutex
+	out      io.Writer
+	rows     int
+	cols     int
+	header   string
+	escState int
+}
+
+func (s *headerScreen) setup(header string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = header
+	// Clear, reserve row 1, enable origin mode, then draw the header.
+	fmt.Fprintf(s.out, "\x1b[2J\x1b[2;%dr\x1b[?6h\x1b[H", s.rows)
+	s.writeHeaderLocked()
+}
+
+func (s *headerScreen) teardown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, "\x1b[?6l\x1b[r\x1b[%d;1H\r\n", s.rows)
+}
+
+func (s *headerScreen) resize(rows int, cols int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows, s.cols = rows, cols
+	fmt.Fprintf(s.out, "\x1b7\x1b[?6l\x1b[2;%dr\x1b8", s.rows)
+	s.writeHeaderLocked()
+}
+
+func (s *headerScreen) drawHeader(header string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = header
+	if s.escState != escText {
+		return
+	}
+	s.writeHeaderLocked()
+}
+
+func (s *headerScreen) writeHeaderLocked() {
+	line := headerLine(s.header, s.cols)
+	fmt.Fprintf(s.out, "\x1b7\x1b[?6l\x1b[1;1H\x1b[2K\x1b[7m%s\x1b[0m\x1b8", line)
+}
+
+func (s *headerScreen) copyFrom(r io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			s.mu.Lock()
+			_, _ = s.out.Write(buf[:n])
+			s.escState = scanEscapeState(buf[:n], s.escState)
+			s.mu.Unlock()
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logDebug("status header copy stopped", "err", err)
+			}
+			return
+		}
+	}
+}
+
+func headerLine(header string, cols int) string {
+	header = strings.TrimSpace(header)
+	if cols <= 0 {
+		return header
+	}
+	if lipgloss.Width(header) > cols {
+		runes := []rune(header)
+		for len(runes) > 0 && lipgloss.Width(string(runes)) > cols {
+			runes = runes[:len(runes)-1]
+		}
+		header = string(runes)
+	}
+	return header + strings.Repeat(" ", cols-lipgloss.Width(header))
+}
+
+const (
+	escText = iota
+	escStart
+	escCSI
+	escOSC
+	escOSCEsc
+)
+
+// scanEscapeState advances a small ANSI parser over chunk so header redraws
+// can wait until the agent's output is back at plain text.
+func scanEscapeState(chunk []byte, state int) int {
+	for _, c := range chunk {
+		switch state {
+		case escText:
+			if c == 0x1b {
+				state = escStart
+			}
+		case escStart:
+			switch c {
+			case '[':
+				state = escCSI
+			case ']':
+				state = escOSC
+			case 0x1b:
+			default:
+				state = escText
+			}
+		case escCSI:
+			if c >= 0x40 && c <= 0x7e {
+				state = escText
+			}
+		case escOSC:
+			switch c {
+			case 0x07:
+				state = escText
+			case 0x1b:
+				state = escOSCEsc
+			}
+		case escOSCEsc:
+			if c == '\\' {
+				state = escText
+			} else {
+				state = escOSC
+			}
+		}
+	}
+	return state
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestScanEscapeState(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   int
+	}{
+		{name: "plain text", chunks: []string{"hello"}, want: escText},
+		{name: "complete csi", chunks: []string{"\x1b[31mred"}, want: escText},
+		{name: "split csi", chunks: []string{"\x1b[3"}, want: escCSI},
+		{name: "split csi resumes", chunks: []string{"\x1b[3", "1m"}, want: escText},
+		{name: "osc until bel", chunks: []string{"\x1b]0;title", "\x07"}, want: escText},
+		{name: "osc with st", chunks: []string{"\x1b]8;;url\x1b", "\\"}, want: escText},
+		{name: "dangling esc", chunks: []string{"text\x1b"}, want: escStart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := escText
+			for _, chunk := range tt.chunks {
+				state = scanEscapeState([]byte(chunk), state)
+			}
+			if state != tt.want {
+				t.Fatalf("expected state %d, got %d", tt.want, state)
+			}
+		})
+	}
+}
+
+func TestHeaderScreenDefersRedrawInsideEscape(t *testing.T) {
+	var out bytes.Buffer
+	screen := &headerScreen{out: &out, rows: 24, cols: 20}
+	screen.copyFrom(strings.NewReader("\x1b[3"))
+	out.Reset()
+	screen.drawHeader("WTX  main")
+	if out.Len() != 0 {
+		t.Fatalf("expected redraw to wait for the escape sequence, got %q", out.String())
+	}
+	screen.copyFrom(strings.NewReader("1m"))
+	out.Reset()
+	screen.drawHeader("WTX  main")
+	if !strings.Contains(out.String(), "WTX  main") {
+		t.Fatalf("expected header redraw, got %q", out.String())
+	}
+	if got := headerLine("a very long header line indeed", 10); got != "a very lon" {
+		t.Fatalf("expected header truncated to width, got %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type switchOptions struct {
+	Create  bool
+	BaseRef string
+	Fetch   bool
+}
+
+func newSwitchCommand() *cobra.Command {
+	var opts switchOptions
+	cmd := &cobra.Command{
+		Use:   "switch <branch>",
+		Short: "Check out another branch in the current worktree",
+		Long: "Run from inside a managed worktree (<repo>.wt/wt.N, or one added with wtx adopt) to switch it to another branch in place, without creating a new directory.\n\n" +
+			"Refuses when the worktree has uncommitted changes, is mid-rebase or merge, or is locked by another session.\n" +
+			"With -b, creates the branch from --from (default: the matching base_ref_rules entry, then new_branch_base_ref, then the repo base ref).",
+		Example: strings.Join([]string{
+			"  wtx switch feature/auth-flow",
+			"  wtx switch -b hotfix/login --from origin/main --fetch",
+		}, "\n"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return nil
+			}
+			if len(args) == 0 {
+				return usageError(cmd, "missing branch argument")
+			}
+			return usageError(cmd, "too many arguments; provide exactly one branch name")
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.Create && (strings.TrimSpace(opts.BaseRef) != "" || opts.Fetch) {
+				return usageError(cmd, "--from and --fetch require -b")
+			}
+			return runSwitch(os.Stdout, "", args[0], opts)
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.Create, "create", "b", false, "Create a new branch")
+	cmd.Flags().StringVar(&opts.BaseRef, "from", "", "Base branch/ref for the new branch (requires -b)")
+	cmd.Flags().BoolVar(&opts.Fetch, "fetch", false, "Fetch the base ref first (requires -b)")
+	cmd.ValidArgsFunction = checkoutBranchCompletion
+	_ = cmd.RegisterFlagCompletionFunc("from", checkoutFromCompletion)
+	return cmd
+}
+
+// runSwitch checks branch out in the managed worktree containing dir, with
+// the same guards the open screen applies before reusing a worktree.
+func runSwitch(w io.Writer, dir string, branch string, opts switchOptions) error {
+	branch = strings.TrimSpace(branch)
+	if err := validateBranchName(branch); err != nil {
+		return err
+	}
+	gitPath, worktreePath, err := requireGitContext(dir)
+	if err != nil {
+		return err
+	}
+	primary, name, ok := switchableWorktree(worktreePath, gitPath)
+	if !ok {
+		return errors.New("wtx switch must run inside a managed worktree; use wtx checkout to pick one")
+	}
+	if current := currentBranchInWorktree(worktreePath); current == branch {
+		fmt.Fprintf(w, "%s is already on %s\n", name, branch)
+		return nil
+	}
+	lockMgr := NewLockManager()
+	// Hold the lock through the checkout so no other session can start an
+	// agent here mid-switch; this window's own agent already holds it.
+	if !lockMgr.HeldByCurrentOwner(primary, worktreePath) {
+		lock, err := lockMgr.Acquire(primary, worktreePath)
+		if err != nil {
+			return fmt.Errorf("%s is in use by another session", name)
+		}
+		defer lock.Release()
+	}
+	if dirty, err := worktreeDirty(worktreePath); err != nil {
+		return err
+	} else if dirty {
+		return fmt.Errorf("%s has uncommitted changes; commit or stash them before switching", name)
+	}
+
+	mgr := NewWorktreeManager(primary, lockMgr)
+	exists, err := branchExistsLocalOrRemote(primary, gitPath, branch)
+	if err != nil {
+		return err
+	}
+	if opts.Create {
+		if exists {
+			return fmt.Errorf("branch %q already exists locally or on a remote", branch)
+		}
+		baseRef := strings.TrimSpace(opts.BaseRef)
+		if baseRef == "" {
+			cfg, _ := loadConfigForDir(primary)
+			baseRef = newBranchBaseRef(cfg.BaseRefRules, branch, func() string {
+				if v := strings.TrimSpace(cfg.NewBranchBaseRef); v != "" {
+					return v
+				}
+				return mgr.ResolveBaseRefForNewBranch()
+			})
+		}
+		err = mgr.CheckoutNewBranch(worktreePath, branch, baseRef, opts.Fetch)
+	} else {
+		if !exists {
+			return fmt.Errorf("branch %q does not exist locally or on known remote-tracking refs; use -b to create it", branch)
+		}
+		err = mgr.CheckoutExistingBranch(worktreePath, branch)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Switched %s to %s\n", name, branch)
+	return nil
+}
+
+// switchableWorktree returns the primary checkout and display name for a
+// worktree wtx manages: one under <repo>.wt, or one adopted from outside it.
+func switchableWorktree(worktreePath string, gitPath string) (string, string, bool) {
+	if primary, name, ok := nestedWorktreeInfo(worktreePath, gitPath); ok {
+		return primary, name, true
+	}
+	primary := worktreeLayoutRoot(worktreePath, gitPath)
+	if primary == "" || sameRealPath(primary, worktreePath) || !worktreeAdopted(primary, worktreePath) {
+		return "", "", false
+	}
+	return primary, filepath.Base(worktreePath), true
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSwitch_ReusesCurrentWorktree(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	runTestGit(t, repo, "branch", "feature/b")
+	wt := addTestWorktree(t, repo, 1, "feature/a")
+
+	if err := runSwitch(&bytes.Buffer{}, repo, "feature/b", switchOptions{}); err == nil || !strings.Contains(err.Error(), "managed worktree") {
+		t.Fatalf("expected refusal outside a managed worktree, got %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runSwitch(&out, wt, "feature/b", switchOptions{}); err != nil {
+		t.Fatalf("runSwitch: %v", err)
+	}
+	if got := currentBranchInWorktree(wt); got != "feature/b" || !strings.Contains(out.String(), "Switched wt.1 to feature/b") {
+		t.Fatalf("expected wt.1 on feature/b, got %q (%q)", got, out.String())
+	}
+
+	writeTestFile(t, filepath.Join(wt, "scratch.txt"), "wip")
+	if err := runSwitch(&bytes.Buffer{}, wt, "feature/a", switchOptions{}); err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected dirty worktree to be refused, got %v", err)
+	}
+	if err := os.Remove(filepath.Join(wt, "scratch.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	if err := runSwitch(&bytes.Buffer{}, wt, "feature/c", switchOptions{}); err == nil || !strings.Contains(err.Error(), "use -b") {
+		t.Fatalf("expected missing branch error, got %v", err)
+	}
+	if err := runSwitch(&bytes.Buffer{}, wt, "feature/c", switchOptions{Create: true, BaseRef: "main"}); err != nil {
+		t.Fatalf("runSwitch -b: %v", err)
+	}
+	if got := currentBranchInWorktree(wt); got != "feature/c" {
+		t.Fatalf("expected wt.1 on new feature/c, got %q", got)
+	}
+}
+
+func TestRunSwitch_AdoptedWorktreeAndLockedByOthers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	outside := filepath.Join(filepath.Dir(repo), "elsewhere")
+	runTestGit(t, repo, "branch", "feature/b")
+	runTestGit(t, repo, "worktree", "add", "-q", "-b", "feature/a", outside)
+
+	if err := runSwitch(&bytes.Buffer{}, outside, "feature/b", switchOptions{}); err == nil || !strings.Contains(err.Error(), "managed worktree") {
+		t.Fatalf("expected refusal before adoption, got %v", err)
+	}
+	if err := recordAdoptedWorktree(repo, outside); err != nil {
+		t.Fatalf("adopt: %v", err)
+	}
+
+	sleeper := exec.Command("sleep", "30")
+	if err := sleeper.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer func() { _ = sleeper.Process.Kill(); _ = sleeper.Wait() }()
+	lockPath, err := NewLockManager().lockPath(repo, outside)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	payload, err := lockPayload(repo, outside, "explicit:teammate", sleeper.Process.Pid, "")
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir locks: %v", err)
+	}
+	if err := os.WriteFile(lockPath, payload, 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	if err := runSwitch(&bytes.Buffer{}, outside, "feature/b", switchOptions{}); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("expected a locked worktree to be refused, got %v", err)
+	}
+
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatalf("remove lock: %v", err)
+	}
+	if err := runSwitch(&bytes.Buffer{}, outside, "feature/b", switchOptions{}); err != nil {
+		t.Fatalf("runSwitch: %v", err)
+	}
+	if got := currentBranchInWorktree(outside); got != "feature/b" {
+		t.Fatalf("expected adopted worktree on feature/b, got %q", got)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("expected switch to release its lock, got %v", err)
+	}
+}
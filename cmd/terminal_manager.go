@@ -1,17 +1,33 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 )
 
 var (
 	tabTitleMu   sync.Mutex
 	lastTabTitle string
+	// tabTitleLabel is appended to every title wtx sets for the rest of the
+	// process, so tabs open on the same branch can be told apart.
+	tabTitleLabel string
+
+	openInNewTabFn = openCommandInNewTab
+	newTabGOOS     = runtime.GOOS
 )
 
+// inNewTabEnv marks the wtx a new tab runs, so it opens the agent in place
+// instead of spawning yet another tab.
+const inNewTabEnv = "WTX_IN_NEW_TAB"
+
 func setITermWTXTab() {
 	setITermTab("wtx")
 }
@@ -25,6 +41,36 @@ func setITermWTXBranchTab(branch string) {
 	setITermTab("wtx - " + branch)
 }
 
+// setTabTitleLabel sets the custom title label, stripped of control
+// characters; empty clears it. Under tmux it is also stored on the session for
+// set-titles-string.
+func setTabTitleLabel(label string) {
+	label = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, label))
+	tabTitleMu.Lock()
+	tabTitleLabel = label
+	tabTitleMu.Unlock()
+	setTmuxTitleLabel(label)
+}
+
+func currentTabTitleLabel() string {
+	tabTitleMu.Lock()
+	defer tabTitleMu.Unlock()
+	return tabTitleLabel
+}
+
+// withTabTitleLabel appends the custom label, e.g. "wtx - main - review".
+func withTabTitleLabel(title string) string {
+	if label := currentTabTitleLabel(); label != "" {
+		return title + " - " + label
+	}
+	return title
+}
+
 func setITermTab(title string) {
 	if iTermIntegrationDisabled() {
 		return
@@ -37,6 +83,7 @@ func setITermTab(title string) {
 	if title == "" {
 		title = "wtx"
 	}
+	title = withTabTitleLabel(title)
 	if shouldSkipTabTitleUpdate(title) {
 		return
 	}
@@ -64,6 +111,48 @@ func resetITermTabColor() {
 	writeTerminalEscape("\x1b]1337;SetTabColor=\x07")
 }
 
+const (
+	notifyBell   = "bell"
+	notifyOSC9   = "osc9"
+	notifyOSC777 = "osc777"
+
+	// notifyCompleteAfter keeps quick creates from ringing; only work long
+	// enough to switch away from is announced.
+	notifyCompleteAfter = 5 * time.Second
+)
+
+// notifyComplete announces that a create or fetch started at started has
+// finished, as configured by notify_on_complete. It writes straight to the
+// terminal rather than through the TUI's renderer.
+func notifyComplete(mode string, started time.Time, message string) {
+	if started.IsZero() || time.Since(started) < notifyCompleteAfter {
+		return
+	}
+	seq := completionNotification(mode, message)
+	if seq == "" {
+		return
+	}
+	if mode == notifyBell {
+		// tmux handles BEL itself, so it isn't wrapped for passthrough.
+		fmt.Fprint(os.Stdout, seq)
+		return
+	}
+	writeTerminalEscape(seq)
+}
+
+func completionNotification(mode string, message string) string {
+	message = strings.NewReplacer("\x07", "", "\x1b", "", ";", ",").Replace(message)
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case notifyBell:
+		return "\x07"
+	case notifyOSC9:
+		return "\x1b]9;" + message + "\x07"
+	case notifyOSC777:
+		return "\x1b]777;notify;wtx;" + message + "\x07"
+	}
+	return ""
+}
+
 func writeTerminalEscape(seq string) {
 	if strings.TrimSpace(seq) == "" {
 		return
@@ -86,3 +175,113 @@ func shouldSkipTabTitleUpdate(title string) bool {
 	lastTabTitle = title
 	return false
 }
+
+// newTabCommand is the shell line a new terminal tab runs to open branch in
+// worktreePath, taking over lock from this process. ok is false when
+// open_in_new_tab is off or the terminal can't be scripted, and the caller
+// should open in place.
+func newTabCommand(worktreePath string, branch string, lock *WorktreeLock) (string, bool) {
+	if envFlagEnabled(inNewTabEnv) || lock == nil || lock.Observer() {
+		return "", false
+	}
+	cfg, err := loadConfigForDir(worktreePath)
+	if err != nil || !cfg.OpenInNewTab {
+		return "", false
+	}
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "detached" || newTabGOOS != "darwin" {
+		return "", false
+	}
+	term := strings.TrimSpace(os.Getenv("TERM_PROGRAM"))
+	if !isITermTerminal(term) && term != "Apple_Terminal" {
+		return "", false
+	}
+	bin, err := resolveSelfBinary(os.Args)
+	if err != nil {
+		return "", false
+	}
+	command := "cd " + shellQuote(worktreePath) + " && " + inNewTabEnv + "=1 " + shellQuote(bin) +
+		" tab-takeover --worktree " + shellQuote(worktreePath) +
+		" --from-owner " + shellQuote(lock.ownerID) + " --from-pid " + strconv.Itoa(lock.pid)
+	if v := strings.TrimSpace(agentOverride); v != "" {
+		command += " --agent " + shellQuote(v)
+	}
+	return command, true
+}
+
+// newTabHandoffTimeout bounds how long the opener waits for the new tab's
+// wtx to take the lock over before running the agent in place instead.
+var newTabHandoffTimeout = 30 * time.Second
+
+// waitForLockHandoff reports whether lock has left this process within
+// timeout, i.e. the new tab's wtx took it over.
+func waitForLockHandoff(lock *WorktreeLock, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := readLockPayload(lock.path)
+		if err != nil || current.OwnerID != lock.ownerID || current.PID != lock.pid {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// runTabTakeover is what a spawned tab runs: it takes the worktree's lock
+// over from the wtx that opened the tab and launches the agent in place.
+func runTabTakeover(worktreePath string, fromOwnerID string, fromPID int) error {
+	_, repoRoot, err := requireGitContext(worktreePath)
+	if err != nil {
+		return err
+	}
+	lockMgr := NewLockManager()
+	lock, err := lockMgr.TakeOver(repoRoot, worktreePath, fromOwnerID, fromPID)
+	if err != nil {
+		return err
+	}
+	if _, err := NewRunner(lockMgr).RunInWorktree(worktreePath, currentBranchInWorktree(worktreePath), lock); err != nil {
+		lock.Release()
+		return err
+	}
+	return nil
+}
+
+// openCommandInNewTab runs command in a new iTerm tab, or a new Terminal
+// window since Terminal has no scriptable tabs.
+func openCommandInNewTab(command string) error {
+	if _, err := exec.LookPath("osascript"); err != nil {
+		return errors.New("osascript not found")
+	}
+	script := `
+on run argv
+	tell application "Terminal"
+		activate
+		do script (item 1 of argv)
+	end tell
+end run
+`
+	if isITermTerminal(os.Getenv("TERM_PROGRAM")) {
+		script = `
+on run argv
+	tell application "iTerm"
+		activate
+		if (count of windows) is 0 then
+			create window with default profile
+		end if
+		tell current window
+			create tab with default profile
+			tell current session
+				write text (item 1 of argv)
+			end tell
+		end tell
+	end tell
+end run
+`
+	}
+	if out, err := exec.Command("osascript", "-e", script, "--", command).CombinedOutput(); err != nil {
+		return commandErrorWithOutput(err, out)
+	}
+	return nil
+}
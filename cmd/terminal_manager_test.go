@@ -1,6 +1,12 @@
 package cmd
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestShouldSkipTabTitleUpdate_DedupesSameTitle(t *testing.T) {
 	tabTitleMu.Lock()
@@ -24,3 +30,89 @@ func TestShouldSkipTabTitleUpdate_AllowsDifferentTitle(t *testing.T) {
 		t.Fatalf("different title should not be skipped")
 	}
 }
+
+func TestCompletionNotification_Modes(t *testing.T) {
+	cases := map[string]string{
+		"bell":   "\x07",
+		"OSC9":   "\x1b]9;wtx: feature/a, done\x07",
+		"osc777": "\x1b]777;notify;wtx;wtx: feature/a, done\x07",
+		"":       "",
+		"beep":   "",
+	}
+	for mode, want := range cases {
+		if got := completionNotification(mode, "wtx: feature/a; done\x07"); got != want {
+			t.Fatalf("mode %q: expected %q, got %q", mode, want, got)
+		}
+	}
+}
+
+func TestNewTabCommand_SpawnedTabDoesNotRespawn(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	t.Setenv(inNewTabEnv, "")
+	oldGOOS := newTabGOOS
+	newTabGOOS = "darwin"
+	t.Cleanup(func() { newTabGOOS = oldGOOS })
+	if err := SaveConfig(Config{OpenInNewTab: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	worktree := t.TempDir()
+	lock := &WorktreeLock{ownerID: "explicit:opener", pid: 4242}
+
+	command, ok := newTabCommand(worktree, "feature/a", lock)
+	if !ok {
+		t.Fatalf("expected a new-tab command")
+	}
+	if !strings.Contains(command, inNewTabEnv+"=1 ") {
+		t.Fatalf("expected %s marker in %q", inNewTabEnv, command)
+	}
+	if !strings.Contains(command, "tab-takeover") || !strings.Contains(command, "--from-owner 'explicit:opener' --from-pid 4242") {
+		t.Fatalf("expected the new tab to take the lock over, got %q", command)
+	}
+	// The spawned tab's wtx sees the marker and runs the agent in place.
+	t.Setenv(inNewTabEnv, "1")
+	if command, ok := newTabCommand(worktree, "feature/a", lock); ok {
+		t.Fatalf("expected no new tab from inside a spawned tab, got %q", command)
+	}
+}
+
+func TestTakeOver_MovesHandedOverLockOnly(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	repo := t.TempDir()
+	worktree := t.TempDir()
+	m := NewLockManager()
+	lockPath, err := m.lockPath(repo, worktree)
+	if err != nil {
+		t.Fatalf("lock path: %v", err)
+	}
+	payload, err := lockPayload(repo, worktree, "explicit:opener", 4242, "review")
+	if err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(lockPath, payload, 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	opener := &WorktreeLock{path: lockPath, ownerID: "explicit:opener", pid: 4242}
+	if waitForLockHandoff(opener, 0) {
+		t.Fatalf("expected the lock to still be the opener's")
+	}
+
+	if _, err := m.TakeOver(repo, worktree, "explicit:someone-else", 4242); err == nil {
+		t.Fatalf("expected a takeover from the wrong owner to fail")
+	}
+	lock, err := m.TakeOver(repo, worktree, "explicit:opener", 4242)
+	if err != nil {
+		t.Fatalf("TakeOver: %v", err)
+	}
+	defer lock.Release()
+	current, err := readLockPayload(lockPath)
+	if err != nil || current.OwnerID != buildOwnerID() || current.PID != os.Getpid() || current.Label != "review" {
+		t.Fatalf("expected the lock to move to this process with its label, got %+v, %v", current, err)
+	}
+	if !waitForLockHandoff(opener, time.Second) {
+		t.Fatalf("expected the opener to see the handoff")
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// uiTheme is the palette every TUI style is built from. Colors may be
+// lipgloss.NoColor to leave the terminal's own color in place.
+type uiTheme struct {
+	Accent     lipgloss.TerminalColor
+	AccentText lipgloss.TerminalColor
+	Text       lipgloss.TerminalColor
+	Strong     lipgloss.TerminalColor
+	Title      lipgloss.TerminalColor
+	Dim        lipgloss.TerminalColor
+	Muted      lipgloss.TerminalColor
+	Error      lipgloss.TerminalColor
+	Warn       lipgloss.TerminalColor
+	Hint       lipgloss.TerminalColor
+	// ReverseSelection marks the selected row with reverse video, for themes
+	// where the accent color alone can't be told apart.
+	ReverseSelection bool
+}
+
+const defaultThemeName = "default"
+
+var uiThemes = map[string]uiTheme{
+	defaultThemeName: {
+		Accent:     lipgloss.Color("#7D56F4"),
+		AccentText: lipgloss.Color("#FFF7DB"),
+		Text:       lipgloss.Color("251"),
+		Strong:     lipgloss.Color("15"),
+		Title:      lipgloss.Color("252"),
+		Dim:        lipgloss.Color("245"),
+		Muted:      lipgloss.Color("241"),
+		Error:      lipgloss.Color("1"),
+		Warn:       lipgloss.Color("3"),
+		Hint:       lipgloss.Color("#E8DFA5"),
+	},
+	"high-contrast": {
+		Accent:           lipgloss.Color("11"),
+		AccentText:       lipgloss.Color("0"),
+		Text:             lipgloss.Color("15"),
+		Strong:           lipgloss.Color("15"),
+		Title:            lipgloss.Color("15"),
+		Dim:              lipgloss.Color("252"),
+		Muted:            lipgloss.Color("248"),
+		Error:            lipgloss.Color("9"),
+		Warn:             lipgloss.Color("11"),
+		Hint:             lipgloss.Color("14"),
+		ReverseSelection: true,
+	},
+	"mono": {
+		Accent:           lipgloss.NoColor{},
+		AccentText:       lipgloss.NoColor{},
+		Text:             lipgloss.NoColor{},
+		Strong:           lipgloss.NoColor{},
+		Title:            lipgloss.NoColor{},
+		Dim:              lipgloss.NoColor{},
+		Muted:            lipgloss.NoColor{},
+		Error:            lipgloss.NoColor{},
+		Warn:             lipgloss.NoColor{},
+		Hint:             lipgloss.NoColor{},
+		ReverseSelection: true,
+	},
+}
+
+var (
+	themeOnce   sync.Once
+	activeTheme = uiThemes[defaultThemeName]
+)
+
+func init() {
+	applyTheme(activeTheme)
+}
+
+func normalizeThemeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := uiThemes[name]; ok {
+		return name
+	}
+	return defaultThemeName
+}
+
+// currentTheme loads the configured theme once and restyles the shared
+// styles to match.
+func currentTheme() uiTheme {
+	themeOnce.Do(func() {
+		if cfg, err := LoadConfig(); err == nil {
+			applyTheme(uiThemes[normalizeThemeName(cfg.Theme)])
+		}
+	})
+	return activeTheme
+}
+
+// selectedStyle is the accent style for the highlighted item in a list.
+func (t uiTheme) selectedStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Accent).Bold(true).Reverse(t.ReverseSelection)
+}
+
+func (t uiTheme) bannerStyle() lipgloss.Style {
+	style := lipgloss.NewStyle().Bold(true).Foreground(t.AccentText).Background(t.Accent).Padding(0, 1)
+	if _, ok := t.Accent.(lipgloss.NoColor); ok {
+		style = style.Reverse(true)
+	}
+	return style
+}
+
+func applyTheme(t uiTheme) {
+	activeTheme = t
+	baseStyle = lipgloss.NewStyle()
+	bannerStyle = t.bannerStyle()
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error).Bold(true)
+	secondaryStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	actionNormalStyle = lipgloss.NewStyle().Foreground(t.Text)
+	actionSelectedStyle = t.selectedStyle()
+	selectorNormalStyle = lipgloss.NewStyle().Foreground(t.Text)
+	selectorSelectedStyle = t.selectedStyle()
+	selectorDisabledStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	selectorDisabledSelectedStyle = t.selectedStyle()
+	selectorHeaderStyle = lipgloss.NewStyle().Foreground(t.Strong).Bold(true)
+	branchStyle = lipgloss.NewStyle().Foreground(t.Strong).Bold(true)
+	branchInlineStyle = lipgloss.NewStyle().Bold(true)
+	warnStyle = lipgloss.NewStyle().Foreground(t.Warn).Bold(true)
+	tmuxStatusDisabledHintStyle = lipgloss.NewStyle().Foreground(t.Hint)
+	updateHintStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	inputStyle = lipgloss.NewStyle().Padding(0, 1)
+}
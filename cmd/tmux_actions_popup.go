@@ -168,10 +168,11 @@ func (m tmuxActionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m tmuxActionsModel) View() string {
 	var b strings.Builder
-	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	disabledStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	theme := currentTheme()
+	selectedStyle := theme.selectedStyle()
+	normalStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	disabledStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Dim)
 
 	queryLine := "/" + m.query
 	if strings.TrimSpace(m.query) == "" {
@@ -205,7 +206,7 @@ func (m tmuxActionsModel) View() string {
 	b.WriteString(dimStyle.Render("enter run • ↑/↓ navigate • esc cancel"))
 	if m.updateHint != "" {
 		b.WriteString("\n")
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(m.updateHint))
+		b.WriteString(lipgloss.NewStyle().Foreground(currentTheme().Muted).Render(m.updateHint))
 	}
 	return b.String()
 }
@@ -386,12 +387,14 @@ func runTmuxAction(basePath string, sourcePane string, action tmuxAction, rename
 	case tmuxActionPR:
 		cmd := exec.Command("gh", "pr", "view", "--web")
 		cmd.Dir = basePath
+		cmd.Env = ghCommandEnv(basePath)
 		out, err := cmd.CombinedOutput()
 		if err != nil {
 			msg := commandErrorMessage(err, out)
 			if isNoPRForCurrentBranchMessage(msg) {
 				fallback := exec.Command("gh", "pr", "list", "--state", "open", "--author", "@me", "--web")
 				fallback.Dir = basePath
+				fallback.Env = ghCommandEnv(basePath)
 				fallbackOut, fallbackErr := fallback.CombinedOutput()
 				if fallbackErr == nil {
 					return nil
@@ -427,7 +430,7 @@ func renameCurrentBranch(basePath string, renameTo string) error {
 	timeout := renameCurrentBranchTimeout
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	cmd := exec.CommandContext(ctx, "git", "branch", "-m", renameTo)
+	cmd := exec.CommandContext(ctx, gitBinary(), "branch", "-m", renameTo)
 	cmd.Dir = basePath
 	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
 	out, err := cmd.CombinedOutput()
@@ -519,9 +522,10 @@ func (m renameBranchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m renameBranchModel) View() string {
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
-	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	theme := currentTheme()
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	errStyle := lipgloss.NewStyle().Foreground(theme.Error)
+	dimStyle := lipgloss.NewStyle().Foreground(theme.Dim)
 
 	var b strings.Builder
 	b.WriteString(titleStyle.Render("Rename branch to"))
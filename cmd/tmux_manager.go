@@ -7,11 +7,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/charmbracelet/lipgloss"
 )
 
 const tmuxStatusIntervalSeconds = "10"
@@ -203,6 +202,15 @@ func splitCommandPane(worktreePath string, runCmd string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+func newCommandWindow(worktreePath string, name string, runCmd string) (string, error) {
+	cmd := exec.Command("tmux", "new-window", "-d", "-n", name, "-c", worktreePath, "-P", "-F", "#{pane_id}", "/bin/sh", "-lc", runCmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 func tmuxAvailable() bool {
 	if tmuxIntegrationDisabled() {
 		return false
@@ -265,12 +273,7 @@ func renderBanner(branch string, path string, ghSummary string) string {
 	if strings.TrimSpace(ghSummary) != "" {
 		label = label + "  " + strings.TrimSpace(ghSummary)
 	}
-	style := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FFF7DB")).
-		Background(lipgloss.Color("#7D56F4")).
-		Padding(0, 1)
-	return style.Render(label)
+	return currentTheme().bannerStyle().Render(label)
 }
 
 func setStatusBanner(banner string) {
@@ -314,12 +317,28 @@ func setDynamicWorktreeStatus(worktreePath string) {
 	tmuxSetOption(sessionID, "status-left", " "+cmd+" ")
 	tmuxSetOption(sessionID, "status-right", " ^A actions | ^S split | ^P PR | ^L IDE#{?#{>:#{window_panes},1}, | ⌥↑/⌥↓ move | ⌥⇧↑/⌥⇧↓ resize,} ")
 	tmuxSetOption(sessionID, "status-right-length", "132")
-	titleCmd := "#(" + shellQuote(bin) + " tmux-title --worktree " + shellQuote(worktreePath) + ")"
+	titleCmd := "#(" + shellQuote(bin) + " tmux-title --worktree " + shellQuote(worktreePath) + ")" +
+		"#{?" + tmuxTitleLabelOption + ", - #{" + tmuxTitleLabelOption + "},}"
 	tmuxSetOption(sessionID, "set-titles", "on")
 	tmuxSetOption(sessionID, "set-titles-string", titleCmd)
 	configureTmuxActionBindings(sessionID, resolveAgentLifecycleBinary())
 }
 
+// tmuxTitleLabelOption holds the custom title label on the session; the
+// title format reads it, so changes show without re-running tmux-title.
+const tmuxTitleLabelOption = "@wtx_title_label"
+
+func setTmuxTitleLabel(label string) {
+	if !tmuxAvailable() {
+		return
+	}
+	sessionID, err := currentSessionID()
+	if err != nil {
+		return
+	}
+	tmuxSetOption(sessionID, tmuxTitleLabelOption, label)
+}
+
 func clearScreen() {
 	if tmuxAvailable() {
 		_ = exec.Command("tmux", "clear-history").Run()
@@ -353,6 +372,17 @@ func shellQuote(value string) string {
 	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
+var plainShellWord = regexp.MustCompile(`^[A-Za-z0-9_./:@%+=,-]+$`)
+
+// shellWord quotes value only when the shell would otherwise split or expand
+// it, for commands meant to be read as well as run.
+func shellWord(value string) string {
+	if plainShellWord.MatchString(value) {
+		return value
+	}
+	return shellQuote(value)
+}
+
 func ensureWTXSessionDefaults() {
 	if tmuxIntegrationDisabled() {
 		return
@@ -747,9 +777,10 @@ func shouldDisableTmuxInputEnhancements(terminalProgram string) bool {
 }
 
 type tmuxAgentState struct {
-	State        string `json:"state"`
-	ExitCode     int    `json:"exit_code"`
-	ExitedAtUnix int64  `json:"exited_at_unix"`
+	State         string `json:"state"`
+	ExitCode      int    `json:"exit_code"`
+	ExitedAtUnix  int64  `json:"exited_at_unix"`
+	StartedAtUnix int64  `json:"started_at_unix,omitempty"`
 }
 
 func runTmuxAgentStart(args []string) error {
@@ -757,10 +788,16 @@ func runTmuxAgentStart(args []string) error {
 	if strings.TrimSpace(worktreePath) == "" {
 		return nil
 	}
+	recordAgentLaunched(worktreePath, currentBranchInWorktree(worktreePath))
+	if lockObserve {
+		// The status line tracks the worktree's writer agent, not observers.
+		return nil
+	}
 	return writeTmuxAgentState(worktreePath, tmuxAgentState{
-		State:        "running",
-		ExitCode:     0,
-		ExitedAtUnix: 0,
+		State:         "running",
+		ExitCode:      0,
+		ExitedAtUnix:  0,
+		StartedAtUnix: time.Now().Unix(),
 	})
 }
 
@@ -771,6 +808,19 @@ func runTmuxAgentExit(args []string) error {
 	}
 	exitCode := parseIntArg(args, "--code", 0)
 	forceUnlock := parseBoolArg(args, "--force-unlock")
+	var started time.Time
+	if prev, ok := readTmuxAgentState(worktreePath); ok && prev.StartedAtUnix > 0 && !lockObserve {
+		started = time.Unix(prev.StartedAtUnix, 0)
+	}
+	recordAgentExited(worktreePath, currentBranchInWorktree(worktreePath), started, exitCode)
+	if lockObserve {
+		// Runs inside the agent's pane, whose shell the observer lock is bound to.
+		if _, repoRoot, err := requireGitContext(worktreePath); err == nil {
+			_ = NewLockManager().ReleaseObserver(repoRoot, worktreePath, os.Getppid())
+		}
+		pauseForAgentOutput(worktreePath, os.Stdin, os.Stdout)
+		return nil
+	}
 	if _, repoRoot, err := requireGitContext(worktreePath); err == nil && strings.TrimSpace(repoRoot) != "" {
 		lockMgr := NewLockManager()
 		_ = lockMgr.ReleaseIfOwned(repoRoot, worktreePath)
@@ -778,11 +828,13 @@ func runTmuxAgentExit(args []string) error {
 			_ = lockMgr.ForceUnlock(repoRoot, worktreePath)
 		}
 	}
-	return writeTmuxAgentState(worktreePath, tmuxAgentState{
+	err := writeTmuxAgentState(worktreePath, tmuxAgentState{
 		State:        "exited",
 		ExitCode:     exitCode,
 		ExitedAtUnix: time.Now().Unix(),
 	})
+	pauseForAgentOutput(worktreePath, os.Stdin, os.Stdout)
+	return err
 }
 
 func parseBoolArg(args []string, key string) bool {
@@ -863,7 +915,7 @@ func tmuxAgentStatePath(worktreePath string) (string, error) {
 	if worktreePath == "" {
 		return "", os.ErrInvalid
 	}
-	repoRoot, err := repoRootForDir(worktreePath, "git")
+	repoRoot, err := repoRootForDir(worktreePath, "")
 	if err != nil {
 		return "", err
 	}
@@ -871,11 +923,11 @@ func tmuxAgentStatePath(worktreePath string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
+	home, err := wtxHomeDir()
+	if err != nil {
 		return "", os.ErrNotExist
 	}
-	return filepath.Join(home, ".wtx", "agent-state", worktreeID+".json"), nil
+	return filepath.Join(home, "agent-state", worktreeID+".json"), nil
 }
 
 func fileLooksExecutable(path string) bool {
@@ -20,7 +20,11 @@ type ghStatusCacheEntry struct {
 
 func runTmuxStatus(args []string) error {
 	worktreePath := parseWorktreeArg(args)
-	fmt.Print(buildTmuxStatusLine(worktreePath))
+	idle := ""
+	if worktreePath != "" {
+		idle = enforceLockIdle(worktreePath)
+	}
+	fmt.Print(buildTmuxStatusLine(worktreePath, idle))
 	return nil
 }
 
@@ -39,13 +43,18 @@ func parseWorktreeArg(args []string) string {
 	return ""
 }
 
-func buildTmuxStatusLine(worktreePath string) string {
+// buildTmuxStatusLine renders the status line; idle is the lock idle label
+// from enforceLockIdle, if any.
+func buildTmuxStatusLine(worktreePath string, idle string) string {
 	label := "WTX"
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
 		return label
 	}
 	branch := currentBranchInWorktree(worktreePath)
+	if cfg, err := loadConfigForDir(worktreePath); err == nil && strings.TrimSpace(cfg.TmuxStatusFormat) != "" {
+		return renderTmuxStatusFormat(cfg.TmuxStatusFormat, worktreePath, branch, idle)
+	}
 	if branch != "" {
 		label += "  " + branch
 	}
@@ -54,9 +63,45 @@ func buildTmuxStatusLine(worktreePath string) string {
 	if agent := strings.TrimSpace(tmuxAgentSummary(worktreePath)); agent != "" {
 		label += "  " + agent
 	}
+	if idle != "" {
+		label += "  " + idle
+	}
 	return label
 }
 
+// renderTmuxStatusFormat fills tmux_status_format. GitHub and agent lookups
+// only run for placeholders the template uses.
+func renderTmuxStatusFormat(format string, worktreePath string, branch string, idle string) string {
+	pairs := []string{"{branch}", branch, "{path}", worktreePath, "{idle}", idle}
+	if strings.Contains(format, "{pr}") || strings.Contains(format, "{ci}") || strings.Contains(format, "{gh}") || strings.Contains(format, "{review}") {
+		pr, ci, gh, review := splitGHSummary(ghSummaryForBranchCached(worktreePath, branch))
+		pairs = append(pairs, "{pr}", pr, "{ci}", ci, "{gh}", gh, "{review}", review)
+	}
+	if strings.Contains(format, "{agent}") {
+		pairs = append(pairs, "{agent}", strings.TrimSpace(tmuxAgentSummary(worktreePath)))
+	}
+	return strings.TrimSpace(strings.NewReplacer(pairs...).Replace(format))
+}
+
+// splitGHSummary takes a "PR x | CI y | GH z | Review w" summary apart; any
+// part it can't find is "-".
+func splitGHSummary(summary string) (string, string, string, string) {
+	parts := map[string]string{}
+	for _, part := range strings.Split(summary, " | ") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if ok && strings.TrimSpace(value) != "" {
+			parts[name] = strings.TrimSpace(value)
+		}
+	}
+	get := func(name string) string {
+		if v, ok := parts[name]; ok {
+			return v
+		}
+		return "-"
+	}
+	return get("PR"), get("CI"), get("GH"), get("Review")
+}
+
 func buildTmuxTitle(worktreePath string) string {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
@@ -70,7 +115,7 @@ func buildTmuxTitle(worktreePath string) string {
 }
 
 func currentBranchInWorktree(worktreePath string) string {
-	branch, err := gitOutputInDir(worktreePath, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	branch, err := gitOutputInDir(worktreePath, gitBinary(), "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return ""
 	}
@@ -170,12 +215,12 @@ func writeCachedGHSummary(repoRoot string, branch string, summary string) error
 }
 
 func ghStatusCachePath(repoRoot string, branch string) (string, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", fmt.Errorf("HOME not set")
+	home, err := wtxHomeDir()
+	if err != nil {
+		return "", err
 	}
 	key := hashString(strings.TrimSpace(repoRoot) + "|" + strings.TrimSpace(branch))
-	return filepath.Join(home, ".wtx", "status-cache", key+".json"), nil
+	return filepath.Join(home, "status-cache", key+".json"), nil
 }
 
 func prLabel(pr PRData) string {
@@ -56,3 +56,21 @@ func TestReviewLabel_NormalizesRequiredWhenApprovalsAreHigher(t *testing.T) {
 		t.Fatalf("expected 2/2 label, got %q", got)
 	}
 }
+
+func TestSplitGHSummary(t *testing.T) {
+	pr, ci, gh, review := splitGHSummary("PR #12 | CI ok 3/3 | GH waiting for checks | Review 1/2 u:0")
+	if pr != "#12" || ci != "ok 3/3" || gh != "waiting for checks" || review != "1/2 u:0" {
+		t.Fatalf("unexpected parts %q %q %q %q", pr, ci, gh, review)
+	}
+	pr, ci, gh, review = splitGHSummary(defaultGHSummary)
+	if pr != "-" || ci != "-" || gh != "-" || review != "-" {
+		t.Fatalf("expected placeholders for default summary, got %q %q %q %q", pr, ci, gh, review)
+	}
+}
+
+func TestRenderTmuxStatusFormat_FillsPlaceholders(t *testing.T) {
+	got := renderTmuxStatusFormat(" #[fg=blue]{branch}#[default] {path} ", "/tmp/repo.wt/wt.1", "feature/x", "")
+	if got != "#[fg=blue]feature/x#[default] /tmp/repo.wt/wt.1" {
+		t.Fatalf("unexpected status %q", got)
+	}
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -9,6 +12,7 @@ import (
 	uiview "github.com/aixolotls/wtx/ui"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -21,6 +25,10 @@ type model struct {
 	runner                *Runner
 	status                WorktreeStatus
 	listIndex             int
+	listGroupByPR         bool
+	listMarked            map[string]bool
+	showHelp              bool
+	listMultiOpening      bool
 	ready                 bool
 	width                 int
 	height                int
@@ -42,15 +50,45 @@ type model struct {
 	creatingBranch        string
 	creatingBaseRef       string
 	creatingExisting      bool
+	creatingDetached      bool
+	creatingPR            int
+	confirmDeleteClean    bool
+	compactSelector       bool
+	relativePaths         bool
+	autoFetch             bool
 	creatingStartedAt     time.Time
 	deletePath            string
 	deleteBranch          string
 	unlockPath            string
 	unlockBranch          string
+	resetPath             string
+	resetBranch           string
+	protectedPath         string
+	protectedBranch       string
+	warnProtected         bool
+	confirmBeforeCreate   bool
+	actionOrder           []string
+	defaultAction         actionKind
+	branchPrefix          string
+	baseRefRules          []BaseRefRule
+	notifyOnComplete      string
+	logPath               string
+	logBranch             string
+	logViewport           viewport.Model
+	showExactTimes        bool
+	protectedPatterns     []string
 	actionBranch          string
 	actionIndex           int
 	actionCreate          bool
+	actionDetach          bool
+	actionPR              bool
+	notePath              string
+	noteInput             textinput.Model
+	lockLabelPath         string
+	lockLabelInput        textinput.Model
+	titleLabelInput       textinput.Model
 	branchOptions         []string
+	branchNameOptions     []string
 	branchSuggestions     []string
 	branchIndex           int
 	pendingPath           string
@@ -60,12 +98,14 @@ type model struct {
 	autoActionPath        string
 	openLoading           bool
 	openLoadErr           string
+	openLoadStage         string
 	openSelected          int
 	openTypeahead         string
 	openTypeaheadAt       time.Time
 	openSearchAllActive   bool
 	openBranches          []openBranchOption
 	openLockedBranches    []openBranchOption
+	openArchivedBranches  []openBranchOption
 	openRecentBranches    []openBranchOption
 	openRecentLocked      []openBranchOption
 	openAllBranches       []openBranchOption
@@ -91,9 +131,21 @@ type model struct {
 	openFormBranchPtr     *string
 	openFormBaseRefPtr    *string
 	openFormFetchPtr      *bool
+	openFormAutoBase      string
+	openBaseRefInput      textinput.Model
+	openBaseRefOptions    []string
+	openBaseRefFiltered   []string
+	openBaseRefIndex      int
+	openBaseRefLoading    bool
+	openIssueInput        textinput.Model
+	openIssues            []ghIssue
+	openIssuesFiltered    []ghIssue
+	openIssueIndex        int
+	openIssuesLoading     bool
 	confirmForm           *huh.Form
 	confirmResult         bool
 	confirmKind           confirmKind
+	attachPane            agentPane
 	openCreating          bool
 	openCreatingStartedAt time.Time
 }
@@ -109,6 +161,11 @@ func newModel() model {
 	m := model{mgr: mgr, orchestrator: orchestrator, runner: NewRunner(lockMgr)}
 	m.branchInput = newBranchInput()
 	m.newBranchInput = newCreateBranchInput()
+	m.openBaseRefInput = newBaseRefInput()
+	m.openIssueInput = newIssueInput()
+	m.noteInput = newNoteInput()
+	m.lockLabelInput = newLockLabelInput()
+	m.titleLabelInput = newTitleLabelInput()
 	m.spinner = newSpinner()
 	m.ghSpinner = newGHSpinner()
 	m.ghPendingByBranch = map[string]bool{}
@@ -117,6 +174,10 @@ func newModel() model {
 	m.openStage = openStageMain
 	m.openSelected = 0
 	m.openDefaultFetch = true
+	m.confirmDeleteClean = true
+	m.warnProtected = true
+	currentTheme()
+	m.openLoadStage = openLoadStageWorktrees
 	if cfg, err := LoadConfig(); err == nil {
 		if strings.TrimSpace(cfg.NewBranchBaseRef) != "" {
 			m.openDefaultBaseRef = strings.TrimSpace(cfg.NewBranchBaseRef)
@@ -124,6 +185,23 @@ func newModel() model {
 		if cfg.NewBranchFetchFirst != nil {
 			m.openDefaultFetch = *cfg.NewBranchFetchFirst
 		}
+		if cfg.ConfirmDeleteCleanWorktree != nil {
+			m.confirmDeleteClean = *cfg.ConfirmDeleteCleanWorktree
+		}
+		if cfg.WarnProtectedBranch != nil {
+			m.warnProtected = *cfg.WarnProtectedBranch
+		}
+		m.protectedPatterns = cfg.ProtectedBranches
+		m.confirmBeforeCreate = cfg.ConfirmBeforeCreate
+		m.actionOrder = cfg.ActionOrder
+		m.defaultAction = defaultWorktreeAction(cfg.DefaultWorktreeAction)
+		m.branchPrefix = cfg.BranchPrefix
+		m.baseRefRules = cfg.BaseRefRules
+		m.notifyOnComplete = cfg.NotifyOnComplete
+		m.showExactTimes = cfg.ShowExactTimes
+		m.compactSelector = cfg.CompactSelector
+		m.relativePaths = cfg.RelativePaths
+		m.autoFetch = cfg.AutoFetch
 	}
 	return m
 }
@@ -153,8 +231,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if m.showHelp {
+			return m.updateHelpOverlay(keyMsg)
+		}
+		if keyMsg.String() == "?" && m.helpAvailable() {
+			m.showHelp = true
+			return m, nil
+		}
+	}
 	if m.openNewBranchForm != nil {
 		applyFormMsg := func(formMsg tea.Msg) (tea.Model, tea.Cmd) {
+			leavingBranch := openFormFocusedKey(m.openNewBranchForm) == openNewBranchNameKey
 			form, cmd := m.openNewBranchForm.Update(formMsg)
 			if f, ok := form.(*huh.Form); ok {
 				m.openNewBranchForm = f
@@ -162,12 +250,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.openNewBranchForm.State == huh.StateCompleted || m.openNewBranchForm.State == huh.StateAborted {
 				return m.handleOpenNewBranchFormDone()
 			}
+			if leavingBranch && openFormFocusedKey(m.openNewBranchForm) == openNewBaseRefKey && m.applyOpenBaseRefRule() {
+				if input, ok := m.openNewBranchForm.GetFocusedField().(*huh.Input); ok {
+					input.Value(m.openFormBaseRefPtr)
+					// huh caches the group's render; redraw it with the new base.
+					m.openNewBranchForm.Update(nil)
+				}
+			}
 			return m, cmd
 		}
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			if isTabKey(keyMsg) {
-				if m.autofillOpenNewBranchDraftIfEmpty() {
-					m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr)
+				if m.completeOpenNewBranchName() || m.autofillOpenNewBranchDraftIfEmpty() {
+					m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr, m.branchNameOptions)
 					return m, m.openNewBranchForm.Init()
 				}
 				return applyFormMsg(tea.KeyMsg{Type: tea.KeyTab})
@@ -199,8 +294,39 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			case "ctrl+r":
 				m.openLoading = true
+				m.openLoadStage = openLoadStageWorktrees
 				m.openLoadErr = ""
 				return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
+			case "ctrl+b":
+				m.captureOpenNewBranchFormValues()
+				m.openNewBranchForm = nil
+				m.openStage = openStagePickBaseRef
+				m.openBaseRefInput.SetValue("")
+				m.openBaseRefInput.Focus()
+				m.openBaseRefIndex = 0
+				m.openBaseRefLoading = true
+				m.errMsg = ""
+				return m, loadOpenBaseRefOptionsCmd(m.mgr)
+			case "ctrl+y":
+				m.captureOpenNewBranchFormValues()
+				branch, baseRef := "", ""
+				if m.openFormBranchPtr != nil {
+					branch = *m.openFormBranchPtr
+				}
+				if m.openFormBaseRefPtr != nil {
+					baseRef = *m.openFormBaseRefPtr
+				}
+				return m.copyWorktreeAddCommand(branch, baseRef)
+			case "ctrl+g":
+				m.captureOpenNewBranchFormValues()
+				m.openNewBranchForm = nil
+				m.openStage = openStagePickIssue
+				m.openIssueInput.SetValue("")
+				m.openIssueInput.Focus()
+				m.openIssueIndex = 0
+				m.openIssuesLoading = true
+				m.errMsg = ""
+				return m, loadOpenIssuesCmd(m.status.RepoRoot)
 			case "esc":
 				m.openNewBranchForm = nil
 				m.openStage = openStageMain
@@ -224,12 +350,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateHint = strings.TrimSpace(msg.hint)
 		m.updateHintIsError = msg.isError
 		return m, nil
+	case openScreenWorktreesMsg:
+		m.openLoadStage = openLoadStageStatus
+		return m, loadOpenBranchesCmd(m.mgr, msg.status)
 	case openScreenLoadedMsg:
 		m.ready = true
 		m.status = msg.status
 		m.errMsg = ""
 		if msg.err != nil {
 			m.openLoading = false
+			m.openLoadStage = ""
 			m.openBranches = nil
 			m.openRecentBranches = nil
 			m.openSlots = nil
@@ -245,6 +375,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.openRecentLocked = msg.lockedBranches
 		m.openBranches = msg.branches
 		m.openLockedBranches = msg.lockedBranches
+		m.openArchivedBranches = msg.archivedBranches
 		m.openSlots = msg.slots
 		m.openPRBranches = msg.prBranches
 		m.openTypeahead = ""
@@ -259,26 +390,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.openStage == openStageMain {
 			m.newBranchInput.Blur()
 		}
-		m.openSelected = clampOpenSelection(m.openSelected, len(m.openBranches))
+		m.openSelected = clampOpenSelection(m.openSelected, len(m.openBranches)+len(m.openArchivedBranches))
 		m.openFetchID = msg.fetchID
 		m.openLoading = true
-		var paths []string
-		for _, slot := range msg.slots {
-			if slot.Path != "" {
-				paths = append(paths, slot.Path)
-			}
-		}
+		paths := openSlotPaths(msg.slots)
 		cmds := []tea.Cmd{m.ghSpinner.Tick}
 		if len(paths) > 0 {
 			cmds = append(cmds, fetchDirtyStatusCmd(paths))
 		}
 		if len(m.openPRBranches) == 0 {
 			m.openLoading = false
+			m.openLoadStage = ""
 			m.openLoadErr = ""
 			return m, tea.Batch(cmds...)
 		}
+		m.openLoadStage = openLoadStagePRs
 		cmds = append(cmds, fetchOpenPRDataCmd(m.orchestrator, m.status.RepoRoot, m.openPRBranches, msg.fetchID))
 		return m, tea.Batch(cmds...)
+	case openBaseRefOptionsMsg:
+		if m.openStage != openStagePickBaseRef {
+			return m, nil
+		}
+		m.openBaseRefLoading = false
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			return m, nil
+		}
+		m.openBaseRefOptions = msg.refs
+		m.openBaseRefFiltered = filterBranches(m.openBaseRefOptions, m.openBaseRefInput.Value())
+		m.openBaseRefIndex = clampOpenBaseRefIndex(m.openBaseRefIndex, len(m.openBaseRefFiltered))
+		return m, nil
+	case openIssuesLoadedMsg:
+		if m.openStage != openStagePickIssue {
+			return m, nil
+		}
+		m.openIssuesLoading = false
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			return m, nil
+		}
+		m.openIssues = msg.issues
+		m.openIssuesFiltered = filterIssues(m.openIssues, m.openIssueInput.Value())
+		m.openIssueIndex = clampOpenBaseRefIndex(m.openIssueIndex, len(m.openIssuesFiltered))
+		return m, nil
 	case openAllBranchesLoadedMsg:
 		if msg.err != nil {
 			if strings.TrimSpace(m.openTypeahead) != "" {
@@ -306,6 +460,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.openLoading = false
+		m.openLoadStage = ""
 		if msg.err != nil {
 			m.openLoadErr = msg.err.Error()
 			return m, nil
@@ -316,13 +471,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		applyPRDataToOpenState(&m.openBranches, &m.openLockedBranches, &m.openSlots, msg.byBranch)
+		applyPRDataToOpenState(&m.openArchivedBranches, nil, nil, msg.byBranch)
 		m.openRecentBranches = m.openBranches
 		m.openRecentLocked = m.openLockedBranches
 		return m, nil
 	case openScreenDirtyMsg:
 		for i := range m.openSlots {
-			if dirty, ok := msg.dirtyByPath[m.openSlots[i].Path]; ok {
-				m.openSlots[i].Dirty = dirty
+			if changes, ok := msg.changesByPath[m.openSlots[i].Path]; ok {
+				m.openSlots[i].Dirty = changes.Files > 0
+				m.openSlots[i].Changes = changes
 			}
 		}
 		return m, nil
@@ -331,29 +488,68 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.errMsg = msg.err.Error()
 			return m, nil
 		}
+		m.recordOpenSlotEvent(eventWorktreeDeleted, msg.path)
 		m.errMsg = ""
 		m.openLoading = true
+		m.openLoadStage = openLoadStageWorktrees
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 	case openUnlockWorktreeDoneMsg:
 		if msg.err != nil {
 			m.errMsg = msg.err.Error()
 			return m, nil
 		}
+		m.recordOpenSlotEvent(eventWorktreeUnlock, msg.path)
+		m.errMsg = ""
+		m.openLoading = true
+		m.openLoadStage = openLoadStageWorktrees
+		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
+	case openDiscardChangesDoneMsg:
+		if msg.err != nil {
+			m.errMsg = "Discard failed: " + msg.err.Error()
+			return m, refreshOpenDirtyCmd(m.openSlots)
+		}
+		m.errMsg = ""
+		m.warnMsg = "Discarded changes in " + msg.path + "."
+		return m, refreshOpenDirtyCmd(m.openSlots)
+	case resetToRemoteDoneMsg:
+		m.warnMsg = ""
+		if msg.err != nil {
+			m.errMsg = msg.err.Error()
+			return m, nil
+		}
+		m.errMsg = ""
+		m.warnMsg = "Reset " + msg.branch + " to remote."
+		return m, fetchStatusCmd(m.orchestrator)
+	case openRepairWorktreesDoneMsg:
+		m.warnMsg = ""
+		if msg.err != nil {
+			m.errMsg = "Repair failed: " + msg.err.Error()
+			return m, nil
+		}
 		m.errMsg = ""
+		m.warnMsg = "Worktrees repaired."
+		if msg.output != "" {
+			m.warnMsg = msg.output
+		}
 		m.openLoading = true
+		m.openLoadStage = openLoadStageWorktrees
+		m.openLoadErr = ""
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 	case openCreateWorktreeDoneMsg:
 		if msg.err != nil {
 			m.errMsg = msg.err.Error()
 			return m, nil
 		}
+		recordEvent(m.status.RepoRoot, wtxEvent{Event: eventWorktreeCreated, Branch: msg.created.Branch})
 		m.errMsg = ""
 		m.openLoading = true
+		m.openLoadStage = openLoadStageWorktrees
 		m.openDebugCreating = false
 		m.newBranchInput.Blur()
 		m.newBranchInput.SetValue("")
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 	case openUseReadyMsg:
+		notifyComplete(m.notifyOnComplete, m.openCreatingStartedAt, completionMessage(msg.branch, msg.err))
 		m.openCreating = false
 		m.openCreatingStartedAt = time.Time{}
 		if msg.err != nil {
@@ -362,6 +558,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.errMsg = ""
 		m.warnMsg = ""
+		_ = clearArchivedBranch(m.status.RepoRoot, msg.branch)
 		m.pendingPath = msg.path
 		m.pendingBranch = msg.branch
 		m.pendingOpenShell = msg.openShell
@@ -374,6 +571,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case statusMsg:
 		m.status = WorktreeStatus(msg)
+		m.status.GroupByPR = m.listGroupByPR
 		m.listIndex = clampListIndex(m.listIndex, m.status)
 		if m.autoActionPath != "" {
 			if idx, wt, ok := findWorktreeByPath(m.status, m.autoActionPath); ok {
@@ -410,6 +608,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		force := m.forceGHRefresh
 		m.forceGHRefresh = false
 		cmd := fetchGHDataCmd(m.orchestrator, m.status, key, force)
+		if m.autoFetch {
+			cmd = tea.Batch(cmd, autoFetchCmd(m.status.RepoRoot))
+		}
 		return m, tea.Batch(cmd, m.ghSpinner.Tick, pollGHTickCmd())
 	case ghDataMsg:
 		if strings.TrimSpace(msg.repoRoot) == "" || strings.TrimSpace(m.status.RepoRoot) == "" {
@@ -433,6 +634,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ghFetchingKey = ""
 		m.listIndex = clampListIndex(m.listIndex, m.status)
 		return m, nil
+	case branchPRDataMsg:
+		delete(m.ghPendingByBranch, msg.branch)
+		if msg.repoRoot != m.status.RepoRoot {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.ghWarnMsg = ghWarningFromErr(msg.err)
+			return m, nil
+		}
+		merged := make(map[string]PRData, len(m.ghDataByBranch)+1)
+		for branch, data := range m.ghDataByBranch {
+			merged[branch] = data
+		}
+		if data, ok := msg.byBranch[msg.branch]; ok {
+			merged[msg.branch] = data
+		} else {
+			delete(merged, msg.branch)
+		}
+		m.ghDataByBranch = merged
+		applyPRDataToStatus(&m.status, m.ghDataByBranch)
+		return m, nil
+	case tea.FocusMsg:
+		return m, m.focusRefreshCmd()
 	case pollStatusTickMsg:
 		if m.mode == modeList {
 			return m, tea.Batch(fetchStatusCmd(m.orchestrator), pollStatusTickCmd())
@@ -447,19 +671,83 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case createWorktreeDoneMsg:
+		notifyComplete(m.notifyOnComplete, m.creatingStartedAt, completionMessage(msg.created.Branch, msg.err))
 		m.mode = modeList
 		m.creatingBranch = ""
 		m.creatingBaseRef = ""
 		m.creatingExisting = false
+		m.creatingDetached = false
+		m.creatingPR = 0
 		m.creatingStartedAt = time.Time{}
 		m.actionCreate = false
+		m.actionDetach = false
+		m.actionPR = false
 		if msg.err != nil {
 			m.errMsg = msg.err.Error()
 			return m, nil
 		}
 		m.errMsg = ""
+		recordEvent(m.status.RepoRoot, wtxEvent{Event: eventWorktreeCreated, Branch: msg.created.Branch})
 		m.autoActionPath = strings.TrimSpace(msg.created.Path)
 		return m, fetchStatusCmd(m.orchestrator)
+	case autoFetchDoneMsg:
+		if msg.err != nil {
+			logDebug("auto fetch failed", "repo", msg.repoRoot, "err", msg.err)
+			return m, nil
+		}
+		if msg.repoRoot != m.status.RepoRoot {
+			return m, nil
+		}
+		return m, fetchStatusCmd(m.orchestrator)
+	case clearNoticeMsg:
+		if m.warnMsg == msg.text {
+			m.warnMsg = ""
+		}
+		return m, nil
+	case agentAttachDoneMsg:
+		if msg.err != nil {
+			m.errMsg = "Attach failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.errMsg = ""
+		return m, fetchStatusCmd(m.orchestrator)
+	case reviewRequestDoneMsg:
+		m.warnMsg = ""
+		if msg.err != nil {
+			m.errMsg = "Review request failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.errMsg = ""
+		m.warnMsg = fmt.Sprintf("Re-requested review on %s from %s.", msg.branch, strings.Join(msg.reviewers, ", "))
+		m.ghFetchingKey = ""
+		m.forceGHRefresh = true
+		return m, nil
+	case ciRerunDoneMsg:
+		m.warnMsg = ""
+		if msg.err != nil {
+			m.errMsg = "Re-run failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.errMsg = ""
+		m.warnMsg = fmt.Sprintf("Re-running %d failed workflow run(s) for %s.", msg.runs, msg.branch)
+		m.ghFetchingKey = ""
+		m.forceGHRefresh = true
+		return m, nil
+	case listMultiOpenDoneMsg:
+		m.listMultiOpening = false
+		m.listMarked = nil
+		m.warnMsg = ""
+		m.errMsg = ""
+		if len(msg.started) > 0 {
+			m.warnMsg = "Started in tmux windows: " + strings.Join(msg.started, ", ")
+		}
+		if len(msg.warnings) > 0 {
+			m.warnMsg = strings.TrimSpace(m.warnMsg + " (" + strings.Join(msg.warnings, "; ") + ")")
+		}
+		if len(msg.failed) > 0 {
+			m.errMsg = "Failed to start: " + strings.Join(msg.failed, "; ")
+		}
+		return m, fetchStatusCmd(m.orchestrator)
 	case spinner.TickMsg:
 		cmds := make([]tea.Cmd, 0, 2)
 		if m.mode == modeCreating {
@@ -469,7 +757,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, cmd)
 			}
 		}
-		if m.mode == modeOpen && m.openLoading {
+		if m.mode == modeOpen && (m.openLoading || !m.ready) {
 			var cmd tea.Cmd
 			m.ghSpinner, cmd = m.ghSpinner.Update(msg)
 			if cmd != nil {
@@ -497,8 +785,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.logViewport.Width = msg.Width
+		m.logViewport.Height = m.logViewportHeight()
 		return m, nil
+	case worktreeLogMsg:
+		return m.updateWorktreeLog(msg)
 	case tea.KeyMsg:
+		if m.mode == modeLog {
+			return m.updateWorktreeLog(msg)
+		}
 		if m.mode == modeOpen {
 			switch msg.String() {
 			case "q", "ctrl+c":
@@ -510,10 +805,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if m.openShowDebug {
 				if m.openDebugCreating {
-					if isTabKey(msg) && strings.TrimSpace(m.newBranchInput.Value()) == "" {
-						m.newBranchInput.SetValue(draftBranchName(time.Now()))
-						m.errMsg = ""
-						return m, nil
+					if isTabKey(msg) {
+						if draft, ok := draftBranchNameFor(m.newBranchInput.Value(), m.branchPrefix, time.Now()); ok {
+							m.newBranchInput.SetValue(draft)
+							m.errMsg = ""
+							return m, nil
+						}
 					}
 					switch msg.Type {
 					case tea.KeyEnter:
@@ -522,8 +819,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.errMsg = "Branch name required."
 							return m, nil
 						}
+						if err := validateBranchName(branch); err != nil {
+							m.errMsg = err.Error()
+							return m, nil
+						}
 						m.errMsg = ""
-						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote))
+						return m, createOpenWorktreeCmd(m.mgr, branch, m.newBranchBaseRef(branch))
 					case tea.KeyEsc:
 						m.openDebugCreating = false
 						m.newBranchInput.Blur()
@@ -537,8 +838,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.errMsg = "Branch name required."
 							return m, nil
 						}
+						if err := validateBranchName(branch); err != nil {
+							m.errMsg = err.Error()
+							return m, nil
+						}
 						m.errMsg = ""
-						return m, createOpenWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote))
+						return m, createOpenWorktreeCmd(m.mgr, branch, m.newBranchBaseRef(branch))
 					}
 					var cmd tea.Cmd
 					m.newBranchInput, cmd = m.newBranchInput.Update(msg)
@@ -572,13 +877,81 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.openPickConfirmBranch = slot.Branch
 					m.confirmResult = false
 					m.confirmKind = confirmOpenDebugDelete
+					warning := unpushedCommitsWarning(m.mgr, slot.Path)
+					m.errMsg = ""
+					if m.canSkipDeleteConfirm(slot.Path, warning) {
+						m.confirmResult = true
+						return m.handleConfirmDone()
+					}
 					m.confirmForm = newConfirmForm(
 						"Delete selected worktree?",
-						fmt.Sprintf("%s\n%s", slot.Branch, slot.Path),
+						fmt.Sprintf("%s\n%s", slot.Branch, slot.Path)+warning,
+						&m.confirmResult,
+					)
+					return m.startConfirm()
+				case "a":
+					slot, ok := selectedOpenDebugSlot(m.openSlots, m.openDebugIndex)
+					if !ok {
+						m.errMsg = "No worktree selected in debug list."
+						return m, nil
+					}
+					if slot.Locked {
+						m.errMsg = "Cannot archive a worktree that is in use. Unlock it first."
+						return m, nil
+					}
+					if slot.Dirty {
+						m.errMsg = "Cannot archive an unclean worktree."
+						return m, nil
+					}
+					if strings.TrimSpace(slot.Branch) == "" || slot.Branch == "detached" {
+						m.errMsg = "Cannot archive a worktree without a branch."
+						return m, nil
+					}
+					m.openPickConfirmPath = slot.Path
+					m.openPickConfirmBranch = slot.Branch
+					m.confirmResult = false
+					m.confirmKind = confirmOpenDebugArchive
+					m.confirmForm = newConfirmForm(
+						"Archive selected worktree?",
+						fmt.Sprintf("Removes the worktree and keeps branch %s.\n%s", slot.Branch, slot.Path),
+						&m.confirmResult,
+					)
+					m.errMsg = ""
+					return m.startConfirm()
+				case "x":
+					slot, ok := selectedOpenDebugSlot(m.openSlots, m.openDebugIndex)
+					if !ok {
+						m.errMsg = "No worktree selected in debug list."
+						return m, nil
+					}
+					if slot.Locked {
+						m.errMsg = "Cannot discard changes in a worktree that is in use. Unlock it first."
+						return m, nil
+					}
+					changes, err := worktreeChangeStats(slot.Path, false)
+					if err != nil {
+						m.errMsg = err.Error()
+						return m, nil
+					}
+					if changes.Files == 0 {
+						m.errMsg = "Worktree has no changes to discard."
+						return m, nil
+					}
+					if err := m.mgr.CanDeleteWorktree(slot.Path); err != nil {
+						m.errMsg = err.Error()
+						return m, nil
+					}
+					m.openPickConfirmPath = slot.Path
+					m.openPickConfirmBranch = slot.Branch
+					m.confirmResult = false
+					m.confirmKind = confirmOpenDebugDiscard
+					m.confirmForm = newConfirmForm(
+						fmt.Sprintf("Discard %s in %s?", changes.label(), slot.Branch),
+						fmt.Sprintf("Runs git reset --hard && git clean -fd in\n%s\nUncommitted and untracked changes are lost for good.", slot.Path),
 						&m.confirmResult,
 					)
 					m.errMsg = ""
-					return m, m.confirmForm.Init()
+					return m.startConfirm()
 				case "u":
 					slot, ok := selectedOpenDebugSlot(m.openSlots, m.openDebugIndex)
 					if !ok {
@@ -595,25 +968,97 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.confirmKind = confirmOpenDebugUnlock
 					m.confirmForm = newConfirmForm(
 						"Force unlock selected worktree?",
-						fmt.Sprintf("%s\n%s", slot.Branch, slot.Path),
+						fmt.Sprintf("%s\n%s", slot.Branch, slot.Path)+ownSessionWarning(slot.Mine),
 						&m.confirmResult,
 					)
 					m.errMsg = ""
-					return m, m.confirmForm.Init()
+					return m.startConfirm()
 				case "n":
 					m.openDebugCreating = true
-					m.newBranchInput.SetValue("")
+					m.newBranchInput.SetValue(m.branchPrefix)
+					m.newBranchInput.CursorEnd()
 					m.newBranchInput.Focus()
 					m.errMsg = ""
 					return m, nil
 				case "ctrl+r":
 					m.openLoading = true
+					m.openLoadStage = openLoadStageWorktrees
 					m.openLoadErr = ""
 					m.openTypeahead = ""
 					return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
+				case "ctrl+l":
+					return m, refreshOpenDirtyCmd(m.openSlots)
+				case "A":
+					m.relativePaths = !m.relativePaths
+					return m, nil
+				case "R":
+					m.errMsg = ""
+					m.warnMsg = "Repairing worktrees..."
+					return m, repairOpenWorktreesCmd(m.mgr)
 				}
 				return m, nil
 			}
+			if m.openStage == openStagePickBaseRef {
+				switch msg.String() {
+				case "esc":
+					return m.returnToOpenNewBranchForm()
+				case "up":
+					m.openBaseRefIndex = clampOpenBaseRefIndex(m.openBaseRefIndex-1, len(m.openBaseRefFiltered))
+					return m, nil
+				case "down":
+					m.openBaseRefIndex = clampOpenBaseRefIndex(m.openBaseRefIndex+1, len(m.openBaseRefFiltered))
+					return m, nil
+				case "enter":
+					ref, ok := selectedBranch(m.openBaseRefFiltered, m.openBaseRefIndex)
+					if !ok {
+						ref = strings.TrimSpace(m.openBaseRefInput.Value())
+					}
+					if ref == "" {
+						m.errMsg = "Select a base ref."
+						return m, nil
+					}
+					if m.openFormBaseRefPtr != nil {
+						*m.openFormBaseRefPtr = ref
+					}
+					return m.returnToOpenNewBranchForm()
+				}
+				var cmd tea.Cmd
+				m.openBaseRefInput, cmd = m.openBaseRefInput.Update(msg)
+				m.openBaseRefFiltered = filterBranches(m.openBaseRefOptions, m.openBaseRefInput.Value())
+				m.openBaseRefIndex = clampOpenBaseRefIndex(m.openBaseRefIndex, len(m.openBaseRefFiltered))
+				return m, cmd
+			}
+			if m.openStage == openStagePickIssue {
+				switch msg.String() {
+				case "esc":
+					return m.returnToOpenNewBranchForm()
+				case "up":
+					m.openIssueIndex = clampOpenBaseRefIndex(m.openIssueIndex-1, len(m.openIssuesFiltered))
+					return m, nil
+				case "down":
+					m.openIssueIndex = clampOpenBaseRefIndex(m.openIssueIndex+1, len(m.openIssuesFiltered))
+					return m, nil
+				case "enter":
+					if m.openIssueIndex < 0 || m.openIssueIndex >= len(m.openIssuesFiltered) {
+						m.errMsg = "Select an issue."
+						return m, nil
+					}
+					branch, err := issueBranchName(m.openIssuesFiltered[m.openIssueIndex])
+					if err != nil {
+						m.errMsg = err.Error()
+						return m, nil
+					}
+					if m.openFormBranchPtr != nil {
+						*m.openFormBranchPtr = branch
+					}
+					return m.returnToOpenNewBranchForm()
+				}
+				var cmd tea.Cmd
+				m.openIssueInput, cmd = m.openIssueInput.Update(msg)
+				m.openIssuesFiltered = filterIssues(m.openIssues, m.openIssueInput.Value())
+				m.openIssueIndex = clampOpenBaseRefIndex(m.openIssueIndex, len(m.openIssuesFiltered))
+				return m, cmd
+			}
 			if m.openStage == openStageNewBranchConfig {
 				switch msg.String() {
 				case "esc":
@@ -630,8 +1075,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switch msg.String() {
 				case "ctrl+r":
 					m.openLoading = true
+					m.openLoadStage = openLoadStageWorktrees
 					m.openLoadErr = ""
 					return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), openPickRefreshTickCmd(), m.ghSpinner.Tick)
+				case "ctrl+l":
+					return m, refreshOpenDirtyCmd(m.openSlots)
 				case "esc":
 					m.openStage = openStageMain
 					return m, nil
@@ -643,9 +1091,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				case "enter":
 					if m.openPickIndex == 0 {
-						m.openCreating = true
-						m.openCreatingStartedAt = time.Now()
-						return m, tea.Batch(m.spinner.Tick, openCmdForCreateTarget(m))
+						return m.startCreateTarget(nil)
 					}
 					slot, ok := selectedOpenDebugSlot(m.openSlots, m.openPickIndex-1)
 					if !ok {
@@ -653,6 +1099,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, nil
 					}
 					if slot.Locked {
+						if pane, ok := liveAgentPane(m.status.RepoRoot, slot.Path); ok {
+							return m.confirmAttachAgent(pane, slot.Branch, slot.Path)
+						}
 						m.openPickConfirmPath = slot.Path
 						m.openPickConfirmBranch = slot.Branch
 						m.confirmResult = false
@@ -662,7 +1111,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							fmt.Sprintf("%s\n%s", slot.Branch, slot.Path),
 							&m.confirmResult,
 						)
-						return m, m.confirmForm.Init()
+						return m.startConfirm()
 					}
 					if slot.Dirty && strings.TrimSpace(slot.Branch) != strings.TrimSpace(m.openTargetBranch) {
 						m.warnMsg = "Worktree is unclean. Clean it first."
@@ -680,41 +1129,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if msg.String() == "ctrl+r" {
 				m.openLoading = true
+				m.openLoadStage = openLoadStageWorktrees
 				m.openLoadErr = ""
 				m.openTypeahead = ""
 				return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), m.ghSpinner.Tick)
 			}
+			if msg.String() == "ctrl+l" {
+				return m, refreshOpenDirtyCmd(m.openSlots)
+			}
+			if msg.String() == "ctrl+y" {
+				branch, ok := m.openSelectedBranchName()
+				if !ok {
+					m.errMsg = "Select a branch to copy its git command."
+					return m, nil
+				}
+				return m.copyWorktreeAddCommand(branch, "")
+			}
 			switch msg.String() {
 			case "up":
-				filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
-				m.openSelected = moveOpenSelection(m.openSelected, -1, filtered)
+				m.openSelected = moveOpenSelection(m.openSelected, -1, m.openSelectableIndices())
 				return m, nil
 			case "down":
-				filtered := openFilteredIndices(m.openTypeahead, m.openBranches)
-				m.openSelected = moveOpenSelection(m.openSelected, 1, filtered)
+				m.openSelected = moveOpenSelection(m.openSelected, 1, m.openSelectableIndices())
 				return m, nil
 			case "enter":
 				if m.openSelected == 0 {
-					defaultBase := resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
 					branch := ""
-					baseRef := defaultBase
+					if cfg, err := LoadConfig(); err == nil {
+						branch = withBranchPrefix(m.branchPrefix, prefillBranchName(cfg))
+					}
+					baseRef := m.newBranchBaseRef(branch)
 					fetch := normalizeFetchForBaseRef(baseRef, m.openDefaultFetch)
 					m.openStage = openStageNewBranchConfig
 					m.openFormBranchPtr = &branch
 					m.openFormBaseRefPtr = &baseRef
+					m.openFormAutoBase = baseRef
 					m.openFormFetchPtr = &fetch
-					m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr)
+					m.branchNameOptions = branchNameSuggestions(m.status.RepoRoot)
+					m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr, m.branchNameOptions)
 					m.openTypeahead = ""
 					m.errMsg = ""
 					return m, m.openNewBranchForm.Init()
 				}
-				index := m.openSelected - 1
-				if index < 0 || index >= len(m.openBranches) {
-					m.errMsg = "No branch selected."
-					return m, nil
-				}
-				branch := strings.TrimSpace(m.openBranches[index].Name)
-				if branch == "" {
+				branch, ok := m.openSelectedBranchName()
+				if !ok {
 					m.errMsg = "No branch selected."
 					return m, nil
 				}
@@ -802,11 +1260,73 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.mode == modeDelete || m.mode == modeUnlock {
 			return m, nil
 		}
-		if m.mode == modeBranchName {
-			if isTabKey(msg) && strings.TrimSpace(m.newBranchInput.Value()) == "" {
-				m.newBranchInput.SetValue(draftBranchName(time.Now()))
+		if m.mode == modeBranchName && m.actionPR {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.mode = modeAction
+				m.actionPR = false
+				m.newBranchInput.Blur()
+				m.newBranchInput.SetValue("")
+				m.errMsg = ""
+				return m, nil
+			case tea.KeyEnter:
+				number, err := parsePRNumber(strings.TrimPrefix(strings.TrimSpace(m.newBranchInput.Value()), "#"))
+				if err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				m.mode = modeCreating
+				m.creatingBranch = ""
+				m.creatingBaseRef = ""
+				m.creatingExisting = false
+				m.creatingPR = number
+				m.creatingStartedAt = time.Now()
+				m.newBranchInput.Blur()
+				m.newBranchInput.SetValue("")
+				m.errMsg = ""
+				return m, tea.Batch(m.spinner.Tick, checkoutPRWorktreeCmd(m.mgr, number))
+			}
+			var cmd tea.Cmd
+			m.newBranchInput, cmd = m.newBranchInput.Update(msg)
+			return m, cmd
+		}
+		if m.mode == modeBranchName && m.actionDetach {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.mode = modeAction
+				m.actionDetach = false
+				m.newBranchInput.Blur()
+				m.newBranchInput.SetValue("")
 				m.errMsg = ""
 				return m, nil
+			case tea.KeyEnter:
+				ref := strings.TrimSpace(m.newBranchInput.Value())
+				if ref == "" {
+					m.errMsg = "Tag or commit required."
+					return m, nil
+				}
+				m.mode = modeCreating
+				m.creatingBranch = ref
+				m.creatingBaseRef = ""
+				m.creatingExisting = false
+				m.creatingDetached = true
+				m.creatingStartedAt = time.Now()
+				m.newBranchInput.Blur()
+				m.newBranchInput.SetValue("")
+				m.errMsg = ""
+				return m, tea.Batch(m.spinner.Tick, createDetachedWorktreeCmd(m.mgr, ref))
+			}
+			var cmd tea.Cmd
+			m.newBranchInput, cmd = m.newBranchInput.Update(msg)
+			return m, cmd
+		}
+		if m.mode == modeBranchName {
+			if isTabKey(msg) {
+				if draft, ok := draftBranchNameFor(m.newBranchInput.Value(), m.branchPrefix, time.Now()); ok {
+					m.newBranchInput.SetValue(draft)
+					m.errMsg = ""
+					return m, nil
+				}
 			}
 			switch msg.Type {
 			case tea.KeyEsc:
@@ -821,6 +1341,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.errMsg = "Branch name required."
 					return m, nil
 				}
+				if err := validateBranchName(branch); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				if containsBranch(m.branchNameOptions, branch) {
+					return m.useExistingBranch(branch)
+				}
 				if !m.actionCreate {
 					row, ok := selectedWorktree(m.status, m.listIndex)
 					if !ok {
@@ -832,7 +1359,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.errMsg = err.Error()
 						return m, nil
 					}
-					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch); err != nil {
+					if err := m.mgr.CheckoutNewBranch(row.Path, branch, m.newBranchBaseRef(branch), m.openDefaultFetch); err != nil {
 						lock.Release()
 						m.errMsg = err.Error()
 						return m, nil
@@ -847,7 +1374,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.mode = modeCreating
 				m.creatingBranch = branch
-				m.creatingBaseRef = resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+				m.creatingBaseRef = m.newBranchBaseRef(branch)
 				m.creatingExisting = false
 				m.creatingStartedAt = time.Now()
 				m.newBranchInput.Blur()
@@ -855,7 +1382,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, tea.Batch(
 					m.spinner.Tick,
-					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)),
+					createWorktreeCmd(m.mgr, branch, m.newBranchBaseRef(branch)),
 				)
 			}
 			switch msg.String() {
@@ -871,6 +1398,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.errMsg = "Branch name required."
 					return m, nil
 				}
+				if err := validateBranchName(branch); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
 				if !m.actionCreate {
 					row, ok := selectedWorktree(m.status, m.listIndex)
 					if !ok {
@@ -882,7 +1413,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.errMsg = err.Error()
 						return m, nil
 					}
-					if err := m.mgr.CheckoutNewBranch(row.Path, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.openDefaultFetch); err != nil {
+					if err := m.mgr.CheckoutNewBranch(row.Path, branch, m.newBranchBaseRef(branch), m.openDefaultFetch); err != nil {
 						lock.Release()
 						m.errMsg = err.Error()
 						return m, nil
@@ -897,7 +1428,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.mode = modeCreating
 				m.creatingBranch = branch
-				m.creatingBaseRef = resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+				m.creatingBaseRef = m.newBranchBaseRef(branch)
 				m.creatingExisting = false
 				m.creatingStartedAt = time.Now()
 				m.newBranchInput.Blur()
@@ -905,7 +1436,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, tea.Batch(
 					m.spinner.Tick,
-					createWorktreeCmd(m.mgr, branch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)),
+					createWorktreeCmd(m.mgr, branch, m.newBranchBaseRef(branch)),
 				)
 			}
 			var cmd tea.Cmd
@@ -926,43 +1457,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "down", "j":
-				if m.actionIndex < len(currentActionItems(m.actionBranch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.actionCreate))-1 {
+				if m.actionIndex < len(m.actionKinds())-1 {
 					m.actionIndex++
 				}
 				return m, nil
 			case "enter":
-				if m.actionCreate {
-					if m.actionIndex == 0 {
-						m.mode = modeBranchName
-						m.newBranchInput.SetValue("")
-						m.newBranchInput.Focus()
-						m.errMsg = ""
+				kinds := m.actionKinds()
+				if m.actionIndex < 0 || m.actionIndex >= len(kinds) {
+					return m, nil
+				}
+				kind := kinds[m.actionIndex]
+				if !m.actionCreate && (kind == actionNewBranch || kind == actionExistingBranch) {
+					if row, ok := selectedWorktree(m.status, m.listIndex); ok && row.GitState != "" {
+						m.errMsg = gitStateSwitchError(filepath.Base(row.Path), row.GitState).Error()
 						return m, nil
 					}
-					if m.actionIndex == 1 {
-						options, err := availableBranchOptions(m.status, m.mgr, true)
-						if err != nil {
-							m.errMsg = err.Error()
-							return m, nil
-						}
-						m.mode = modeBranchPick
-						m.branchOptions = options
-						m.branchSuggestions = filterBranches(m.branchOptions, "")
-						m.branchIndex = 0
-						m.branchInput.SetValue("")
-						m.branchInput.Focus()
-						return m, nil
-					}
-				}
-				if m.actionIndex == 1 {
-					m.mode = modeBranchName
-					m.newBranchInput.SetValue("")
-					m.newBranchInput.Focus()
-					m.errMsg = ""
-					return m, nil
 				}
-				if m.actionIndex == 2 {
-					options, err := availableBranchOptions(m.status, m.mgr, false)
+				switch kind {
+				case actionNewBranch:
+					return m.startBranchNameInput(), nil
+				case actionExistingBranch:
+					options, err := availableBranchOptions(m.status, m.mgr, m.actionCreate)
 					if err != nil {
 						m.errMsg = err.Error()
 						return m, nil
@@ -974,32 +1489,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.branchInput.SetValue("")
 					m.branchInput.Focus()
 					return m, nil
-				}
-				if m.actionIndex == 3 {
+				case actionDetached:
+					m.mode = modeBranchName
+					m.actionDetach = true
+					m.newBranchInput.ShowSuggestions = false
+					m.newBranchInput.SetValue("")
+					m.newBranchInput.Focus()
+					m.errMsg = ""
+					return m, nil
+				case actionCheckoutPR:
+					m.mode = modeBranchName
+					m.actionPR = true
+					m.newBranchInput.ShowSuggestions = false
+					m.newBranchInput.SetValue("")
+					m.newBranchInput.Focus()
+					m.errMsg = ""
+					return m, nil
+				case actionShell:
 					if row, ok := selectedWorktree(m.status, m.listIndex); ok {
-						m.errMsg = ""
-						m.warnMsg = ""
-						m.pendingPath = row.Path
-						m.pendingBranch = row.Branch
-						m.pendingOpenShell = true
-						m.pendingLock = nil
-						return m, tea.Quit
+						return m.openShell(row)
 					}
-				}
-				if m.actionIndex == 0 {
+				case actionLog:
 					if row, ok := selectedWorktree(m.status, m.listIndex); ok {
-						m.errMsg = ""
-						m.warnMsg = ""
-						lock, err := m.mgr.AcquireWorktreeLock(row.Path)
-						if err != nil {
-							m.errMsg = err.Error()
-							return m, nil
-						}
-						m.pendingPath = row.Path
-						m.pendingBranch = row.Branch
-						m.pendingOpenShell = false
-						m.pendingLock = lock
-						return m, tea.Quit
+						return m.startWorktreeLog(row)
+					}
+				case actionUse:
+					if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+						return m.useSelectedWorktree(row)
 					}
 				}
 				m.errMsg = "Not implemented yet."
@@ -1011,6 +1527,83 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.mode == modeNote {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.mode = modeList
+				m.notePath = ""
+				m.noteInput.Blur()
+				m.errMsg = ""
+				return m, nil
+			case tea.KeyEnter:
+				note := strings.TrimSpace(m.noteInput.Value())
+				if err := setWorktreeNote(m.status.RepoRoot, m.notePath, note); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				for i := range m.status.Worktrees {
+					if m.status.Worktrees[i].Path == m.notePath {
+						m.status.Worktrees[i].Note = note
+					}
+				}
+				m.mode = modeList
+				m.notePath = ""
+				m.noteInput.Blur()
+				m.errMsg = ""
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.noteInput, cmd = m.noteInput.Update(msg)
+			return m, cmd
+		}
+		if m.mode == modeLockLabel {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.mode = modeList
+				m.lockLabelPath = ""
+				m.lockLabelInput.Blur()
+				m.errMsg = ""
+				return m, nil
+			case tea.KeyEnter:
+				label := strings.TrimSpace(m.lockLabelInput.Value())
+				if err := m.mgr.SetLockLabel(m.lockLabelPath, label); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				for i := range m.status.Worktrees {
+					if m.status.Worktrees[i].Path == m.lockLabelPath {
+						m.status.Worktrees[i].LockLabel = label
+					}
+				}
+				m.mode = modeList
+				m.lockLabelPath = ""
+				m.lockLabelInput.Blur()
+				m.errMsg = ""
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.lockLabelInput, cmd = m.lockLabelInput.Update(msg)
+			return m, cmd
+		}
+		if m.mode == modeTitleLabel {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.mode = modeList
+				m.titleLabelInput.Blur()
+				m.errMsg = ""
+				return m, nil
+			case tea.KeyEnter:
+				setTabTitleLabel(m.titleLabelInput.Value())
+				m.mode = modeList
+				m.titleLabelInput.Blur()
+				m.errMsg = ""
+				syncTabTitleWithSelection(m)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.titleLabelInput, cmd = m.titleLabelInput.Update(msg)
+			return m, cmd
+		}
 		if m.mode == modeBranchPick {
 			switch msg.String() {
 			case "esc":
@@ -1030,70 +1623,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "enter":
-				if m.actionCreate {
-					branch, ok := selectedBranch(m.branchSuggestions, m.branchIndex)
-					if !ok {
-						m.errMsg = "Select an existing branch."
-						return m, nil
-					}
-					if wt, reusable, reason := reusableWorktreeForBranch(m.status, branch); reusable {
-						lock, err := m.mgr.AcquireWorktreeLock(wt.Path)
-						if err != nil {
-							m.errMsg = err.Error()
-							return m, nil
-						}
-						m.errMsg = ""
-						m.warnMsg = ""
-						m.pendingPath = wt.Path
-						m.pendingBranch = wt.Branch
-						m.pendingOpenShell = false
-						m.pendingLock = lock
-						return m, tea.Quit
-					} else if reason != "" {
-						m.errMsg = reason
-						return m, nil
-					}
-					m.mode = modeCreating
-					m.creatingBranch = branch
-					m.creatingBaseRef = ""
-					m.creatingExisting = true
-					m.creatingStartedAt = time.Now()
-					m.branchInput.Blur()
-					m.branchSuggestions = nil
-					m.branchIndex = 0
-					m.errMsg = ""
-					return m, tea.Batch(
-						m.spinner.Tick,
-						createWorktreeFromExistingCmd(m.mgr, branch),
-					)
-				}
 				branch, ok := selectedBranch(m.branchSuggestions, m.branchIndex)
 				if !ok {
 					m.errMsg = "Select an existing branch."
 					return m, nil
 				}
-				row, ok := selectedWorktree(m.status, m.listIndex)
-				if !ok {
-					m.errMsg = "No worktree selected."
-					return m, nil
-				}
-				lock, err := m.mgr.AcquireWorktreeLock(row.Path)
-				if err != nil {
-					m.errMsg = err.Error()
-					return m, nil
-				}
-				if err := m.mgr.CheckoutExistingBranch(row.Path, branch); err != nil {
-					lock.Release()
-					m.errMsg = err.Error()
-					return m, nil
-				}
-				m.errMsg = ""
-				m.warnMsg = ""
-				m.pendingPath = row.Path
-				m.pendingBranch = branch
-				m.pendingOpenShell = false
-				m.pendingLock = lock
-				return m, tea.Quit
+				return m.useExistingBranch(branch)
 			}
 			var cmd tea.Cmd
 			m.branchInput, cmd = m.branchInput.Update(msg)
@@ -1103,6 +1638,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 		}
+		if row, ok := selectedWorktree(m.status, m.listIndex); ok && row.LinkedRepo != "" && !linkedRowKey(msg.String()) {
+			m.errMsg = fmt.Sprintf("%s is in %s; run wtx there to manage its worktrees.", worktreeBranchLabel(row), filepath.Base(row.LinkedRepo))
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -1115,6 +1654,70 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ghWarnMsg = ""
 			m.forceGHRefresh = true
 			return m, fetchStatusCmd(m.orchestrator)
+		case "f":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				branch := strings.TrimSpace(row.Branch)
+				if branch == "" || branch == "detached" {
+					m.errMsg = "No branch to refresh."
+					return m, nil
+				}
+				if m.ghFetchingKey != "" {
+					m.errMsg = "GitHub data is already refreshing."
+					return m, nil
+				}
+				if m.ghPendingByBranch == nil {
+					m.ghPendingByBranch = map[string]bool{}
+				}
+				m.ghPendingByBranch[branch] = true
+				m.errMsg = ""
+				return m, tea.Batch(refreshBranchPRDataCmd(m.orchestrator, m.status.RepoRoot, branch), m.ghSpinner.Tick)
+			}
+		case " ":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if isOrphanedPath(m.status, row.Path) || !row.Available {
+					m.errMsg = "Worktree is currently in use."
+					return m, nil
+				}
+				if m.listMarked == nil {
+					m.listMarked = map[string]bool{}
+				}
+				if m.listMarked[row.Path] {
+					delete(m.listMarked, row.Path)
+				} else {
+					m.listMarked[row.Path] = true
+				}
+				m.errMsg = ""
+			}
+			return m, nil
+		case "esc":
+			m.listMarked = nil
+			return m, nil
+		case "o":
+			targets := markedWorktrees(m.status, m.listMarked)
+			if len(targets) == 0 {
+				m.errMsg = "Mark worktrees with space first."
+				return m, nil
+			}
+			if m.listMultiOpening {
+				return m, nil
+			}
+			if !tmuxAvailable() {
+				m.errMsg = "Opening multiple worktrees requires tmux."
+				return m, nil
+			}
+			m.listMultiOpening = true
+			m.errMsg = ""
+			m.warnMsg = ""
+			return m, openMarkedWorktreesCmd(m.mgr, m.runner, targets)
+		case "g":
+			selectedPath := currentWorktreePath(m.status, m.listIndex)
+			m.listGroupByPR = !m.listGroupByPR
+			m.status.GroupByPR = m.listGroupByPR
+			if idx, _, ok := findWorktreeByPath(m.status, selectedPath); ok {
+				m.listIndex = idx
+			}
+			m.listIndex = clampListIndex(m.listIndex, m.status)
+			return m, nil
 		case "up", "k":
 			if m.listIndex > 0 {
 				m.listIndex--
@@ -1126,7 +1729,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.listIndex++
 			}
 			return m, nil
-		case "enter":
+		case "enter", "a":
 			if isCreateRow(m.listIndex, m.status) {
 				m.mode = modeAction
 				m.actionCreate = true
@@ -1140,10 +1743,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.errMsg = "Cannot open actions for orphaned worktree."
 					return m, nil
 				}
+				if !row.Available && lockObserve {
+					// Observer locks share the worktree, so skip actions that switch branches.
+					return m.observeWorktree(row)
+				}
 				if !row.Available {
+					if pane, ok := liveAgentPane(worktreeRepoRoot(m.status, row), row.Path); ok {
+						return m.confirmAttachAgent(pane, row.Branch, row.Path)
+					}
 					m.errMsg = "Worktree is currently in use."
 					return m, nil
 				}
+				if row.LinkedRepo != "" && (msg.String() == "a" || m.defaultAction != actionShell) {
+					// The actions menu acts on this repo, so linked rows just open.
+					return m.useSelectedWorktree(row)
+				}
+				if msg.String() == "enter" {
+					switch m.defaultAction {
+					case actionUse:
+						return m.useSelectedWorktree(row)
+					case actionShell:
+						return m.openShell(row)
+					}
+				}
 				m.mode = modeAction
 				m.actionCreate = false
 				m.actionBranch = row.Branch
@@ -1151,19 +1773,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, nil
 			}
+		case "n":
+			if !m.status.InRepo {
+				return m, nil
+			}
+			m.actionCreate = true
+			m.actionBranch = ""
+			m.actionIndex = 0
+			return m.startBranchNameInput(), nil
+		case "e":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				m.mode = modeNote
+				m.notePath = row.Path
+				m.noteInput.SetValue(row.Note)
+				m.noteInput.CursorEnd()
+				m.noteInput.Focus()
+				m.errMsg = ""
+				return m, nil
+			}
+		case "L":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if !row.LockedByMe {
+					m.errMsg = "Only locks held by this session can be labeled."
+					return m, nil
+				}
+				m.mode = modeLockLabel
+				m.lockLabelPath = row.Path
+				m.lockLabelInput.SetValue(row.LockLabel)
+				m.lockLabelInput.CursorEnd()
+				m.lockLabelInput.Focus()
+				m.errMsg = ""
+				return m, nil
+			}
+		case "T":
+			m.mode = modeTitleLabel
+			m.titleLabelInput.SetValue(currentTabTitleLabel())
+			m.titleLabelInput.CursorEnd()
+			m.titleLabelInput.Focus()
+			m.errMsg = ""
+			return m, nil
 		case "s":
 			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
 				if isOrphanedPath(m.status, row.Path) {
 					m.errMsg = "Cannot open shell for orphaned worktree."
 					return m, nil
 				}
-				m.errMsg = ""
-				m.warnMsg = ""
-				m.pendingPath = row.Path
-				m.pendingBranch = row.Branch
-				m.pendingOpenShell = true
-				m.pendingLock = nil
-				return m, tea.Quit
+				return m.openShell(row)
 			}
 		case "d":
 			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
@@ -1176,15 +1831,39 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.deleteBranch = row.Branch
 				m.confirmResult = false
 				m.confirmKind = confirmDelete
+				warning := unpushedCommitsWarning(m.mgr, row.Path)
+				m.errMsg = ""
+				if m.canSkipDeleteConfirm(row.Path, warning) {
+					m.confirmResult = true
+					return m.handleConfirmDone()
+				}
 				m.confirmForm = newConfirmForm(
 					"Delete worktree?",
-					fmt.Sprintf("%s\n%s", row.Branch, row.Path),
+					fmt.Sprintf("%s\n%s", row.Branch, row.Path)+warning,
 					&m.confirmResult,
 				)
+				return m.startConfirm()
+			}
+		case "y":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if row.Branch == "" || row.Branch == "detached" {
+					m.errMsg = "No branch on selected worktree."
+					return m, nil
+				}
+				return m.copyWorktreeAddCommand(row.Branch, "")
+			}
+		case "P":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if strings.TrimSpace(row.PRURL) == "" {
+					m.errMsg = "No PR URL for selected worktree."
+					return m, nil
+				}
+				copyToClipboardFn(row.PRURL)
 				m.errMsg = ""
-				return m, m.confirmForm.Init()
+				m.warnMsg = "Copied " + row.PRURL
+				return m, clearNoticeCmd(m.warnMsg)
 			}
-		case "p", "P":
+		case "p":
 			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
 				if strings.TrimSpace(row.PRURL) == "" {
 					m.errMsg = "No PR URL for selected worktree."
@@ -1197,6 +1876,135 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.errMsg = ""
 				return m, nil
 			}
+		case "b":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if row.Branch == "" || row.Branch == "detached" {
+					m.errMsg = "Compare view needs a branch."
+					return m, nil
+				}
+				owner, name, err := resolveGitHubRepo(m.status.RepoRoot)
+				if err != nil {
+					m.errMsg = "Compare view needs a GitHub origin: " + err.Error()
+					return m, nil
+				}
+				link := compareURL(owner, name, m.status.BaseRef, row.Branch)
+				if link == "" {
+					m.errMsg = "No base ref to compare against."
+					return m, nil
+				}
+				if err := m.runner.OpenURL(link); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				m.errMsg = ""
+				return m, nil
+			}
+		case "c":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if !row.HasPR || row.CIState != PRCIFail {
+					m.errMsg = "No failing CI checks for selected worktree."
+					return m, nil
+				}
+				m.errMsg = ""
+				m.warnMsg = "Re-running failed checks for " + row.Branch + "..."
+				return m, rerunFailedChecksCmd(m.status.RepoRoot, row.Branch, row.PRNumber)
+			}
+		case "C":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if !row.HasPR || row.CIState != PRCIFail {
+					m.errMsg = "No failing CI checks for selected worktree."
+					return m, nil
+				}
+				link := strings.TrimSpace(row.CIFailingURL)
+				if link == "" {
+					link = actionsURLForPR(row.PRURL)
+				}
+				if link == "" {
+					m.errMsg = "No CI link for selected worktree."
+					return m, nil
+				}
+				if err := m.runner.OpenURL(link); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				m.errMsg = ""
+				return m, nil
+			}
+		case "v":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if row.UnresolvedComments <= 0 {
+					m.errMsg = "No unresolved comments for selected worktree."
+					return m, nil
+				}
+				if strings.TrimSpace(row.LatestCommentURL) == "" {
+					m.errMsg = "Comment links are off; run: wtx config set fetch_comment_links true"
+					return m, nil
+				}
+				if err := m.runner.OpenURL(row.LatestCommentURL); err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				m.errMsg = ""
+				return m, nil
+			}
+		case "w":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if !row.HasPR || row.PRNumber <= 0 {
+					m.errMsg = "No PR for selected worktree."
+					return m, nil
+				}
+				if len(row.Reviewers) == 0 {
+					if !fetchReviewersEnabled(m.status.RepoRoot) {
+						m.errMsg = "Reviewer lookup is off; run: wtx config set fetch_reviewers true"
+						return m, nil
+					}
+					m.errMsg = "No reviewers to re-request for selected worktree."
+					return m, nil
+				}
+				m.errMsg = ""
+				m.warnMsg = "Re-requesting review for " + row.Branch + "..."
+				return m, rerequestReviewCmd(m.status.RepoRoot, row.Branch, row.PRNumber, row.Reviewers)
+			}
+		case "A":
+			m.relativePaths = !m.relativePaths
+			return m, nil
+		case "t":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if isOrphanedPath(m.status, row.Path) {
+					m.errMsg = "Cannot set upstream for orphaned worktree."
+					return m, nil
+				}
+				upstream, err := m.mgr.SetUpstream(row.Branch)
+				if err != nil {
+					m.errMsg = err.Error()
+					return m, nil
+				}
+				m.errMsg = ""
+				m.warnMsg = "Tracking " + upstream + "."
+				return m, fetchStatusCmd(m.orchestrator)
+			}
+		case "R":
+			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
+				if !row.RemoteDiverged {
+					m.errMsg = "Branch matches its remote."
+					return m, nil
+				}
+				if !row.Available {
+					m.errMsg = "Worktree is currently in use."
+					return m, nil
+				}
+				m.resetPath = row.Path
+				m.resetBranch = row.Branch
+				m.confirmResult = false
+				m.confirmKind = confirmResetRemote
+				m.confirmForm = newConfirmForm(
+					"Reset to remote?",
+					fmt.Sprintf("Fetches and runs git reset --hard to the remote %s.\nLocal-only commits will be lost.\n%s", row.Branch, row.Path),
+					&m.confirmResult,
+				)
+				m.errMsg = ""
+				return m.startConfirm()
+			}
 		case "u":
 			if row, ok := selectedWorktree(m.status, m.listIndex); ok {
 				if isOrphanedPath(m.status, row.Path) {
@@ -1214,17 +2022,194 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.confirmKind = confirmUnlock
 				m.confirmForm = newConfirmForm(
 					"Unlock worktree?",
-					fmt.Sprintf("%s\n%s", row.Branch, row.Path),
+					fmt.Sprintf("%s\n%s", row.Branch, row.Path)+ownSessionWarning(row.LockedByMe),
 					&m.confirmResult,
 				)
 				m.errMsg = ""
-				return m, m.confirmForm.Init()
+				return m.startConfirm()
 			}
 		}
 	}
 	return m, nil
 }
 
+func (m model) startConfirm() (tea.Model, tea.Cmd) {
+	if confirmSkippedByYes(m.confirmKind) {
+		m.confirmResult = true
+		return m.handleConfirmDone()
+	}
+	return m, m.confirmForm.Init()
+}
+
+// confirmAttachAgent offers to jump to the agent already running in the
+// worktree instead of starting a second one.
+func (m model) confirmAttachAgent(pane agentPane, branch string, path string) (tea.Model, tea.Cmd) {
+	m.attachPane = pane
+	m.confirmResult = true
+	m.confirmKind = confirmAttachAgent
+	m.confirmForm = newConfirmForm(
+		"Attach to the running agent?",
+		fmt.Sprintf("An agent is already running for this worktree.\n%s\n%s", branch, path),
+		&m.confirmResult,
+	)
+	m.errMsg = ""
+	return m.startConfirm()
+}
+
+// useExistingBranch opens branch in a reused or new worktree when creating,
+// or checks it out in the selected worktree otherwise.
+func (m model) useExistingBranch(branch string) (tea.Model, tea.Cmd) {
+	if m.actionCreate {
+		if wt, reusable, reason := reusableWorktreeForBranch(m.status, branch); reusable {
+			lock, err := m.mgr.AcquireWorktreeLock(wt.Path)
+			if err != nil {
+				m.errMsg = err.Error()
+				return m, nil
+			}
+			m.errMsg = ""
+			m.warnMsg = ""
+			m.pendingPath = wt.Path
+			m.pendingBranch = wt.Branch
+			m.pendingOpenShell = false
+			m.pendingLock = lock
+			return m, tea.Quit
+		} else if reason != "" {
+			m.errMsg = reason
+			return m, nil
+		}
+		m.mode = modeCreating
+		m.creatingBranch = branch
+		m.creatingBaseRef = ""
+		m.creatingExisting = true
+		m.creatingStartedAt = time.Now()
+		m.branchInput.Blur()
+		m.branchSuggestions = nil
+		m.branchIndex = 0
+		m.newBranchInput.Blur()
+		m.newBranchInput.SetValue("")
+		m.errMsg = ""
+		return m, tea.Batch(
+			m.spinner.Tick,
+			createWorktreeFromExistingCmd(m.mgr, branch),
+		)
+	}
+	row, ok := selectedWorktree(m.status, m.listIndex)
+	if !ok {
+		m.errMsg = "No worktree selected."
+		return m, nil
+	}
+	if other, ok := worktreeForBranch(m.status.Worktrees, branch, row.Path); ok {
+		if other.Available && !isOrphanedPath(m.status, other.Path) {
+			return m.useWorktree(other.Path, other.Branch)
+		}
+		m.errMsg = (&branchCheckedOutError{Branch: branch, Path: other.Path}).Error() + ", which is in use."
+		return m, nil
+	}
+	lock, err := m.mgr.AcquireWorktreeLock(row.Path)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+	if err := m.mgr.CheckoutExistingBranch(row.Path, branch); err != nil {
+		lock.Release()
+		m.errMsg = err.Error()
+		return m, nil
+	}
+	m.errMsg = ""
+	m.warnMsg = ""
+	m.pendingPath = row.Path
+	m.pendingBranch = branch
+	m.pendingOpenShell = false
+	m.pendingLock = lock
+	return m, tea.Quit
+}
+
+// useSelectedWorktree runs the Use action on row, asking first when the
+// branch is protected.
+func (m model) useSelectedWorktree(row WorktreeInfo) (tea.Model, tea.Cmd) {
+	if m.warnProtected && isProtectedBranch(row.Branch, m.status.BaseRef, m.status.BaseRemote, m.protectedPatterns) {
+		m.protectedPath = row.Path
+		m.protectedBranch = row.Branch
+		m.confirmResult = false
+		m.confirmKind = confirmUseProtected
+		m.confirmForm = newConfirmForm(
+			"Use protected branch "+row.Branch+"?",
+			"The agent would commit straight to "+row.Branch+".\nCheck out a new branch instead unless that's intended.",
+			&m.confirmResult,
+		)
+		m.errMsg = ""
+		return m.startConfirm()
+	}
+	return m.useWorktree(row.Path, row.Branch)
+}
+
+// openShell quits to a shell in row without locking it.
+func (m model) openShell(row WorktreeInfo) (tea.Model, tea.Cmd) {
+	m.errMsg = ""
+	m.warnMsg = ""
+	m.pendingPath = row.Path
+	m.pendingBranch = row.Branch
+	m.pendingOpenShell = true
+	m.pendingLock = nil
+	return m, tea.Quit
+}
+
+// useWorktree locks the worktree and quits to run the agent on its current
+// branch.
+func (m model) useWorktree(path string, branch string) (tea.Model, tea.Cmd) {
+	m.errMsg = ""
+	m.warnMsg = ""
+	mgr := m.mgr
+	if _, row, ok := findWorktreeByPath(m.status, path); ok && row.LinkedRepo != "" {
+		mgr = NewWorktreeManager(row.LinkedRepo, m.mgr.lockMgr)
+	}
+	lock, err := mgr.AcquireWorktreeLock(path)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+	m.pendingPath = path
+	m.pendingBranch = branch
+	m.pendingOpenShell = false
+	m.pendingLock = lock
+	return m, tea.Quit
+}
+
+// observeWorktree launches the agent in a worktree another agent holds, under
+// an observer lock that leaves the writer's lock and branch alone.
+func (m model) observeWorktree(row WorktreeInfo) (tea.Model, tea.Cmd) {
+	mgr := m.mgr
+	if row.LinkedRepo != "" {
+		mgr = NewWorktreeManager(row.LinkedRepo, m.mgr.lockMgr)
+	}
+	lock, err := mgr.AcquireWorktreeObserverLock(row.Path)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
+	}
+	m.errMsg = ""
+	m.warnMsg = ""
+	m.pendingPath = row.Path
+	m.pendingBranch = row.Branch
+	m.pendingOpenShell = false
+	m.pendingLock = lock
+	return m, tea.Quit
+}
+
+// startBranchNameInput opens the new-branch input with local and remote
+// branch names offered as tab completions.
+func (m model) startBranchNameInput() model {
+	m.mode = modeBranchName
+	m.branchNameOptions = branchNameSuggestions(m.status.RepoRoot)
+	m.newBranchInput.SetSuggestions(m.branchNameOptions)
+	m.newBranchInput.ShowSuggestions = true
+	m.newBranchInput.SetValue(m.branchPrefix)
+	m.newBranchInput.CursorEnd()
+	m.newBranchInput.Focus()
+	m.errMsg = ""
+	return m
+}
+
 func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 	kind := m.confirmKind
 	confirmed := m.confirmResult
@@ -1236,6 +2221,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 	case confirmDelete:
 		m.mode = modeList
 		path := m.deletePath
+		branch := m.deleteBranch
 		m.deletePath = ""
 		m.deleteBranch = ""
 		m.errMsg = ""
@@ -1247,10 +2233,12 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			m.errMsg = err.Error()
 			return m, nil
 		}
+		recordEvent(m.status.RepoRoot, wtxEvent{Event: eventWorktreeDeleted, Branch: branch})
 		return m, fetchStatusCmd(m.orchestrator)
 	case confirmUnlock:
 		m.mode = modeList
 		path := m.unlockPath
+		branch := m.unlockBranch
 		m.unlockPath = ""
 		m.unlockBranch = ""
 		m.errMsg = ""
@@ -1261,7 +2249,35 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			m.errMsg = err.Error()
 			return m, nil
 		}
+		recordEvent(m.status.RepoRoot, wtxEvent{Event: eventWorktreeUnlock, Branch: branch})
 		return m, fetchStatusCmd(m.orchestrator)
+	case confirmUseProtected:
+		path := m.protectedPath
+		branch := m.protectedBranch
+		m.protectedPath = ""
+		m.protectedBranch = ""
+		if !confirmed {
+			return m, nil
+		}
+		return m.useWorktree(path, branch)
+	case confirmAttachAgent:
+		pane := m.attachPane
+		m.attachPane = agentPane{}
+		if !confirmed {
+			return m, nil
+		}
+		return m, attachAgentPaneCmd(pane)
+	case confirmResetRemote:
+		path := m.resetPath
+		branch := m.resetBranch
+		m.resetPath = ""
+		m.resetBranch = ""
+		m.errMsg = ""
+		if !confirmed {
+			return m, nil
+		}
+		m.warnMsg = "Resetting " + branch + " to remote..."
+		return m, resetToRemoteCmd(m.mgr, path, branch)
 	case confirmOpenDebugDelete:
 		path := m.openPickConfirmPath
 		m.openPickConfirmPath = ""
@@ -1270,6 +2286,22 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, deleteOpenWorktreeCmd(m.mgr, path)
+	case confirmOpenDebugArchive:
+		path := m.openPickConfirmPath
+		m.openPickConfirmPath = ""
+		m.openPickConfirmBranch = ""
+		if !confirmed {
+			return m, nil
+		}
+		return m, archiveOpenWorktreeCmd(m.mgr, path)
+	case confirmOpenDebugDiscard:
+		path := m.openPickConfirmPath
+		m.openPickConfirmPath = ""
+		m.openPickConfirmBranch = ""
+		if !confirmed {
+			return m, nil
+		}
+		return m, discardOpenWorktreeChangesCmd(m.mgr, path)
 	case confirmOpenDebugUnlock:
 		path := m.openPickConfirmPath
 		m.openPickConfirmPath = ""
@@ -1280,6 +2312,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 		return m, unlockOpenWorktreeCmd(m.mgr, path)
 	case confirmOpenPickLocked:
 		path := m.openPickConfirmPath
+		branch := m.openPickConfirmBranch
 		m.openPickConfirmPath = ""
 		m.openPickConfirmBranch = ""
 		if !confirmed {
@@ -1289,6 +2322,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			m.errMsg = err.Error()
 			return m, nil
 		}
+		recordEvent(m.status.RepoRoot, wtxEvent{Event: eventWorktreeUnlock, Branch: branch})
 		if slot, ok := findOpenSlotByPath(m.openSlots, path); ok && slot.Dirty {
 			m.warnMsg = "Worktree is unclean. Clean it first."
 			m.pendingPath = slot.Path
@@ -1303,6 +2337,7 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			return m, tea.Batch(m.spinner.Tick, openCmdForTargetOnSlot(m, slot))
 		}
 		m.openLoading = true
+		m.openLoadStage = openLoadStageWorktrees
 		return m, tea.Batch(loadOpenScreenCmd(m.orchestrator, m.mgr), openPickRefreshTickCmd(), m.ghSpinner.Tick)
 	case confirmOpenBaseDefault:
 		var saveCmd tea.Cmd
@@ -1324,6 +2359,13 @@ func (m model) handleConfirmDone() (tea.Model, tea.Cmd) {
 			return m, m.confirmForm.Init()
 		}
 		return m.continueOpenTargetSelection(saveCmd)
+	case confirmCreateWorktree:
+		if !confirmed {
+			return m, nil
+		}
+		m.openCreating = true
+		m.openCreatingStartedAt = time.Now()
+		return m, tea.Batch(m.spinner.Tick, openCmdForCreateTarget(m))
 	case confirmOpenFetchDefault:
 		var saveCmd tea.Cmd
 		if confirmed {
@@ -1354,6 +2396,8 @@ func (m model) handleOpenNewBranchFormDone() (tea.Model, tea.Cmd) {
 }
 
 func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
+	m.captureOpenNewBranchFormValues()
+	m.applyOpenBaseRefRule()
 	branch := ""
 	base := ""
 	fetch := m.openDefaultFetch
@@ -1366,34 +2410,20 @@ func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
 	if m.openFormFetchPtr != nil {
 		fetch = *m.openFormFetchPtr
 	}
-	if m.openNewBranchForm != nil {
-		if focused := m.openNewBranchForm.GetFocusedField(); focused != nil {
-			switch focused.GetKey() {
-			case openNewBranchNameKey:
-				if v := strings.TrimSpace(fmt.Sprint(focused.GetValue())); v != "" {
-					branch = v
-				}
-			case openNewBaseRefKey:
-				if v := strings.TrimSpace(fmt.Sprint(focused.GetValue())); v != "" {
-					base = v
-				}
-			case openNewFetchKey:
-				if v, ok := focused.GetValue().(bool); ok {
-					fetch = v
-				}
-			}
-		}
-	}
 	if branch == "" {
 		m.errMsg = "Branch name required."
 		return m, nil
 	}
-	if base == "" {
-		base = m.openDefaultBaseRef
+	if err := validateBranchName(branch); err != nil {
+		m.errMsg = err.Error()
+		return m, nil
 	}
-	if strings.TrimSpace(base) == "" {
-		base = resolveNewBranchBaseRef("", m.status.BaseRef, m.status.HasRemote)
+	if base == "" {
+		base = m.newBranchBaseRef(branch)
 	}
+	// A base picked by base_ref_rules is not a new default worth saving.
+	_, matched := baseRefForBranch(m.baseRefRules, branch)
+	ruleBase := matched && base == m.openFormAutoBase
 	fetch = normalizeFetchForBaseRef(base, fetch)
 	m.openTargetBranch = branch
 	m.openTargetIsNew = true
@@ -1405,7 +2435,14 @@ func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
 	m.openFormFetchPtr = nil
 	m.openStage = openStageMain
 	m.errMsg = ""
-	if m.openTargetBaseRef != m.openDefaultBaseRef {
+	if containsBranch(m.branchNameOptions, branch) {
+		m.openTargetIsNew = false
+		m.openTargetBaseRef = ""
+		m.openTargetFetch = false
+		m.warnMsg = fmt.Sprintf("%s already exists; checking it out instead of creating it.", branch)
+		return m.continueOpenTargetSelection(nil)
+	}
+	if m.openTargetBaseRef != m.openDefaultBaseRef && !ruleBase {
 		m.confirmResult = false
 		m.confirmKind = confirmOpenBaseDefault
 		m.confirmForm = newConfirmForm(
@@ -1428,6 +2465,88 @@ func (m model) submitOpenNewBranchForm() (tea.Model, tea.Cmd) {
 	return m.continueOpenTargetSelection(nil)
 }
 
+// captureOpenNewBranchFormValues copies the focused field's in-progress value
+// into the form pointers before the form is submitted or torn down.
+func (m *model) captureOpenNewBranchFormValues() {
+	if m.openNewBranchForm == nil {
+		return
+	}
+	focused := m.openNewBranchForm.GetFocusedField()
+	if focused == nil {
+		return
+	}
+	switch focused.GetKey() {
+	case openNewBranchNameKey:
+		if v := strings.TrimSpace(fmt.Sprint(focused.GetValue())); v != "" && m.openFormBranchPtr != nil {
+			*m.openFormBranchPtr = v
+		}
+	case openNewBaseRefKey:
+		if v := strings.TrimSpace(fmt.Sprint(focused.GetValue())); v != "" && m.openFormBaseRefPtr != nil {
+			*m.openFormBaseRefPtr = v
+		}
+	case openNewFetchKey:
+		if v, ok := focused.GetValue().(bool); ok && m.openFormFetchPtr != nil {
+			*m.openFormFetchPtr = v
+		}
+	}
+}
+
+func (m model) returnToOpenNewBranchForm() (tea.Model, tea.Cmd) {
+	m.openBaseRefInput.Blur()
+	m.openBaseRefLoading = false
+	m.openIssueInput.Blur()
+	m.openIssuesLoading = false
+	m.errMsg = ""
+	if m.openFormBranchPtr == nil || m.openFormBaseRefPtr == nil || m.openFormFetchPtr == nil {
+		m.openStage = openStageMain
+		return m, nil
+	}
+	m.openStage = openStageNewBranchConfig
+	m.applyOpenBaseRefRule()
+	m.openNewBranchForm = newOpenNewBranchForm(m.openFormBranchPtr, m.openFormBaseRefPtr, m.openFormFetchPtr, m.branchNameOptions)
+	return m, m.openNewBranchForm.Init()
+}
+
+// applyOpenBaseRefRule points the form's base ref at the base_ref_rules match
+// for the branch name, but only while the base is still the one wtx filled in,
+// so a ref the user typed or picked is never replaced. It reports whether the
+// base changed.
+// newBranchBaseRef is the default base for a new branch named branch; see
+// the package-level newBranchBaseRef.
+func (m model) newBranchBaseRef(branch string) string {
+	return newBranchBaseRef(m.baseRefRules, branch, func() string {
+		return resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+	})
+}
+
+func (m *model) applyOpenBaseRefRule() bool {
+	if m.openFormBranchPtr == nil || m.openFormBaseRefPtr == nil {
+		return false
+	}
+	current := strings.TrimSpace(*m.openFormBaseRefPtr)
+	if current != m.openFormAutoBase {
+		return false
+	}
+	base := m.newBranchBaseRef(*m.openFormBranchPtr)
+	if base == current {
+		return false
+	}
+	*m.openFormBaseRefPtr = base
+	m.openFormAutoBase = base
+	return true
+}
+
+func openFormFocusedKey(form *huh.Form) string {
+	if form == nil {
+		return ""
+	}
+	field := form.GetFocusedField()
+	if field == nil {
+		return ""
+	}
+	return field.GetKey()
+}
+
 func normalizeFetchForBaseRef(baseRef string, fetch bool) bool {
 	if looksLikeLocalBranchRef(baseRef) {
 		return false
@@ -1469,6 +2588,59 @@ func (m model) continueOpenTargetSelection(saveCmd tea.Cmd) (tea.Model, tea.Cmd)
 	return m, tea.Batch(cmds...)
 }
 
+// completeOpenNewBranchName completes the branch field to the first known
+// branch it prefixes, so the form can be rebuilt with the full name.
+func (m *model) completeOpenNewBranchName() bool {
+	if m == nil || m.openNewBranchForm == nil || m.openFormBranchPtr == nil {
+		return false
+	}
+	focused := m.openNewBranchForm.GetFocusedField()
+	if focused == nil || focused.GetKey() != openNewBranchNameKey {
+		return false
+	}
+	m.captureOpenNewBranchFormValues()
+	completed, ok := completeBranchName(*m.openFormBranchPtr, m.branchNameOptions)
+	if !ok {
+		return false
+	}
+	*m.openFormBranchPtr = completed
+	m.errMsg = ""
+	return true
+}
+
+// startCreateTarget creates a worktree for the open target, first showing a
+// summary to confirm when confirm_before_create is set.
+func (m model) startCreateTarget(saveCmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if m.confirmBeforeCreate && m.openTargetIsNew {
+		m.confirmResult = true
+		m.confirmKind = confirmCreateWorktree
+		m.confirmForm = newConfirmForm("Create this worktree?", m.createTargetSummary(), &m.confirmResult)
+		return m, tea.Batch(saveCmd, m.confirmForm.Init())
+	}
+	m.openCreating = true
+	m.openCreatingStartedAt = time.Now()
+	return m, tea.Batch(saveCmd, m.spinner.Tick, openCmdForCreateTarget(m))
+}
+
+func (m model) createTargetSummary() string {
+	fetch := "no"
+	if m.openTargetFetch {
+		fetch = "yes"
+	}
+	path := "a new worktree"
+	if m.status.RepoRoot != "" {
+		if next, err := nextWorktreePath(worktreeLayoutRoot(m.status.RepoRoot, gitBinary())); err == nil {
+			path = next
+		}
+	}
+	agent := defaultAgentCommand
+	if cfg, err := LoadConfig(); err == nil && strings.TrimSpace(cfg.AgentCommand) != "" {
+		agent = strings.TrimSpace(cfg.AgentCommand)
+	}
+	return fmt.Sprintf("Create branch %s from %s\nFetch first: %s\nIn directory %s\nThen launch %s",
+		m.openTargetBranch, m.openTargetBaseRef, fetch, path, agent)
+}
+
 func (m *model) autofillOpenNewBranchDraftIfEmpty() bool {
 	if m == nil || m.openNewBranchForm == nil {
 		return false
@@ -1476,11 +2648,11 @@ func (m *model) autofillOpenNewBranchDraftIfEmpty() bool {
 	if m.openFormBranchPtr == nil {
 		return false
 	}
-	current := strings.TrimSpace(*m.openFormBranchPtr)
-	if current != "" {
+	draft, ok := draftBranchNameFor(*m.openFormBranchPtr, m.branchPrefix, time.Now())
+	if !ok {
 		return false
 	}
-	*m.openFormBranchPtr = draftBranchName(time.Now())
+	*m.openFormBranchPtr = draft
 	m.errMsg = ""
 	return true
 }
@@ -1516,14 +2688,21 @@ func syncTabTitleWithSelection(m model) {
 }
 func (m model) View() string {
 	var b strings.Builder
-	showTopBar := m.ready && m.status.InRepo && m.mode == modeList
+	compact := m.compactLayout()
+	showTopBar := m.ready && m.status.InRepo && m.mode == modeList && !compact
 	if showTopBar {
-		b.WriteString(renderViewHeader())
+		b.WriteString(renderViewHeader(m.status))
 		b.WriteString("\n\n")
 	}
 
 	if !m.ready {
-		b.WriteString("Loading...\n")
+		stage := m.openLoadStage
+		if stage == "" {
+			stage = "Loading..."
+		}
+		b.WriteString(m.ghSpinner.View() + " " + stage + "\n")
+		b.WriteString(secondaryStyle.Render("Press q to cancel."))
+		b.WriteString("\n")
 		return b.String()
 	}
 
@@ -1547,6 +2726,15 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	if banner := nestedWorktreeBanner(m.status); banner != "" {
+		b.WriteString(warnStyle.Render(banner))
+		b.WriteString("\n\n")
+	}
+
+	if m.showHelp {
+		return m.renderHelpOverlay()
+	}
+
 	if m.confirmForm != nil {
 		b.WriteString(m.confirmForm.View())
 		return b.String()
@@ -1557,13 +2745,18 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	if m.mode == modeLog {
+		b.WriteString(m.renderWorktreeLog())
+		return b.String()
+	}
+
 	if m.mode == modeAction {
 		title := "Worktree actions:"
 		if m.actionCreate {
 			title = "New worktree actions:"
 		}
 		b.WriteString(title + "\n")
-		for i, item := range currentActionItems(m.actionBranch, resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote), m.actionCreate) {
+		for i, item := range actionMenuItems(m.actionKinds(), m.actionBranch, m.newBranchBaseRef(m.branchPrefix)) {
 			line := "  " + actionNormalStyle.Render(item)
 			if i == m.actionIndex {
 				line = "  " + actionSelectedStyle.Render(item)
@@ -1578,6 +2771,28 @@ func (m model) View() string {
 		b.WriteString("\nPress enter to select, esc to cancel.\n")
 		return b.String()
 	}
+	if m.mode == modeBranchName && m.actionPR {
+		b.WriteString("Pull request number:\n")
+		b.WriteString(inputStyle.Render(m.newBranchInput.View()))
+		b.WriteString("\n")
+		if m.errMsg != "" {
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nPress enter to check out, esc to cancel.\n")
+		return b.String()
+	}
+	if m.mode == modeBranchName && m.actionDetach {
+		b.WriteString("Tag or commit to detach at:\n")
+		b.WriteString(inputStyle.Render(m.newBranchInput.View()))
+		b.WriteString("\n")
+		if m.errMsg != "" {
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nPress enter to create, esc to cancel.\n")
+		return b.String()
+	}
 	if m.mode == modeBranchName {
 		title := "New branch name:"
 		if m.actionCreate {
@@ -1586,11 +2801,43 @@ func (m model) View() string {
 		b.WriteString(title + "\n")
 		b.WriteString(inputStyle.Render(m.newBranchInput.View()))
 		b.WriteString("\n")
+		if branch := strings.TrimSpace(m.newBranchInput.Value()); containsBranch(m.branchNameOptions, branch) {
+			b.WriteString(warnStyle.Render(branch + " already exists; enter checks it out instead of creating it."))
+			b.WriteString("\n")
+		}
 		if m.errMsg != "" {
 			b.WriteString(errorStyle.Render(m.errMsg))
 			b.WriteString("\n")
 		}
-		b.WriteString("\nPress tab to generate draft-<ts>, enter to create, esc to cancel.\n")
+		b.WriteString("\nPress tab to complete a branch (or generate draft-<ts> when empty), enter to create, esc to cancel.\n")
+		return b.String()
+	}
+	if m.mode == modeNote {
+		b.WriteString("Note for " + m.notePath + ":\n")
+		b.WriteString(inputStyle.Render(m.noteInput.View()))
+		b.WriteString("\n")
+		if m.errMsg != "" {
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nPress enter to save (empty clears), esc to cancel.\n")
+		return b.String()
+	}
+	if m.mode == modeLockLabel {
+		b.WriteString("Lock label for " + m.lockLabelPath + ":\n")
+		b.WriteString(inputStyle.Render(m.lockLabelInput.View()))
+		b.WriteString("\n")
+		if m.errMsg != "" {
+			b.WriteString(errorStyle.Render(m.errMsg))
+			b.WriteString("\n")
+		}
+		b.WriteString("\nPress enter to save (empty clears), esc to cancel.\n")
+		return b.String()
+	}
+	if m.mode == modeTitleLabel {
+		b.WriteString("Tab title label for this session:\n")
+		b.WriteString(inputStyle.Render(m.titleLabelInput.View()))
+		b.WriteString("\n\nPress enter to save (empty clears), esc to cancel.\n")
 		return b.String()
 	}
 	if m.mode == modeBranchPick {
@@ -1612,8 +2859,12 @@ func (m model) View() string {
 		b.WriteString("\nPress enter to select, esc to cancel.\n")
 		return b.String()
 	}
-	b.WriteString(baseStyle.Render(renderSelector(m.status, m.listIndex, m.ghPendingByBranch, m.ghSpinner.View())))
-	b.WriteString("\n")
+	if compact {
+		b.WriteString(baseStyle.Render(renderCompactSelector(m.status, m.listIndex, m.width, m.ghPendingByBranch, m.listMarked, m.ghSpinner.View())))
+	} else {
+		b.WriteString(baseStyle.Render(renderSelector(m.status, m.listIndex, m.width, m.ghPendingByBranch, m.listMarked, m.ghSpinner.View())))
+		b.WriteString("\n")
+	}
 	if m.status.Err != nil {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.status.Err)))
 		b.WriteString("\n")
@@ -1649,35 +2900,199 @@ func (m model) View() string {
 			b.WriteString("\n")
 		}
 	}
-	selectedPath := currentWorktreePath(m.status, m.listIndex)
-	if selectedPath != "" {
+	if compact {
+		return b.String()
+	}
+	if wt, ok := selectedWorktree(m.status, m.listIndex); ok {
 		b.WriteString("\n")
-		b.WriteString(secondaryStyle.Render(selectedPath))
+		b.WriteString(secondaryStyle.Render(worktreeDetailLine(wt, displayWorktreePath(m.status.RepoRoot, wt.Path, m.relativePaths), formatLastUsed(wt.LastUsedUnix, time.Now(), m.showExactTimes), m.width)))
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	help := "Press r to refresh, q to quit."
+	help := "Press r to refresh, g to group by PR status, ? for all keys, q to quit."
 	if m.mode == modeCreating {
 		help = "Creating worktree..."
 	} else if isCreateRow(m.listIndex, m.status) {
-		help = "Press enter for actions, r to refresh, q to quit."
+		help = "Press enter for actions, r to refresh, g to group by PR status, ? for all keys, q to quit."
 	} else if wt, ok := selectedWorktree(m.status, m.listIndex); ok {
 		prHint := ""
 		if strings.TrimSpace(wt.PRURL) != "" {
-			prHint = ", p to open PR"
+			prHint = ", p to open PR, P to copy its URL"
+		}
+		if wt.LockedByMe {
+			prHint += ", L to label your lock"
 		}
-		if !wt.Available && !isOrphanedPath(m.status, wt.Path) {
-			help = "Press u to unlock, d to delete" + prHint + ", r to refresh, q to quit."
+		if strings.TrimSpace(wt.LatestCommentURL) != "" && wt.UnresolvedComments > 0 {
+			prHint += ", v to open the newest comment"
+		}
+		if len(wt.Reviewers) > 0 {
+			prHint += ", w to re-request review"
+		}
+		if len(m.listMarked) > 0 {
+			help = fmt.Sprintf("Press space to mark, o to open %d marked in tmux windows, esc to clear marks, q to quit.", len(m.listMarked))
+		} else if !wt.Available && !isOrphanedPath(m.status, wt.Path) {
+			help = "Press u to unlock, d to delete" + prHint + ", r to refresh, g to group by PR status, ? for all keys, q to quit."
 		} else {
-			help = "Press enter for actions, s for shell, d to delete" + prHint + ", r to refresh, q to quit."
+			resetHint := ""
+			if wt.HasPR && wt.CIState == PRCIFail {
+				resetHint = ", c to re-run failed checks, C to open the failing run"
+			}
+			if wt.RemoteDiverged {
+				resetHint += ", R to reset to remote"
+			}
+			if strings.TrimSpace(wt.Upstream) == "" && m.status.HasRemote && wt.Branch != "detached" {
+				resetHint += ", t to track the remote branch"
+			}
+			if m.status.HasRemote && wt.Branch != "detached" {
+				resetHint += ", b to open the compare view"
+			}
+			help = "Press " + m.enterHint() + ", n for new worktree, s for shell, e to edit note, space to mark, d to delete" + prHint + resetHint + ", r to refresh, f to refresh this PR, g to group by PR status, A for absolute/relative paths, ? for all keys, q to quit."
+			if wt.RemoteBranchGone {
+				help = wt.Upstream + " was deleted; press d to delete this worktree if it's done. " + help
+			}
 		}
 	}
 	b.WriteString(help + "\n")
 	return b.String()
 }
-func renderViewHeader() string {
-	return lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("Worktrees")
+
+// enterHint describes enter on an available worktree row.
+func (m model) enterHint() string {
+	switch m.defaultAction {
+	case actionUse:
+		return "enter to use, a for actions"
+	case actionShell:
+		return "enter for shell, a for actions"
+	}
+	return "enter for actions"
+}
+
+// worktreeDetailLine is shown under the selector for the selected worktree:
+// branch, upstream, path, last use, and HEAD subject, cut to the terminal
+// width.
+func worktreeDetailLine(wt WorktreeInfo, path string, lastUsed string, width int) string {
+	line := worktreeBranchLabel(wt)
+	if upstream := strings.TrimSpace(wt.Upstream); upstream != "" {
+		line += " → " + upstream
+	} else if wt.Branch != "detached" {
+		line += " (no upstream)"
+	}
+	line += "  " + path
+	if lastUsed != "" {
+		line += "  used " + lastUsed
+	}
+	if subject := strings.TrimSpace(wt.HeadSubject); subject != "" {
+		line += "  " + subject
+	}
+	if width > 1 {
+		line = truncateLockField(line, width-1)
+	}
+	return line
+}
+
+// canSkipDeleteConfirm reports whether confirm_delete_clean_worktree=false
+// lets path be deleted without a prompt: it must be clean, have no unpushed
+// commits, and not be the last managed worktree.
+func (m model) canSkipDeleteConfirm(path string, unpushedWarning string) bool {
+	if m.confirmDeleteClean || unpushedWarning != "" || isOrphanedPath(m.status, path) {
+		return false
+	}
+	if dirty, err := worktreeDirty(path); err != nil || dirty {
+		return false
+	}
+	root := m.status.PrimaryRoot
+	if strings.TrimSpace(root) == "" {
+		root = m.status.RepoRoot
+	}
+	managed := 0
+	for _, wt := range m.status.Worktrees {
+		if ensureManagedWorktreePath(root, wt.Path) == nil {
+			managed++
+		}
+	}
+	return managed > 1
+}
+
+// ownSessionWarning flags unlock confirmations for a lock held by this
+// terminal or tmux session, which is usually a still-running agent.
+func ownSessionWarning(mine bool) string {
+	if !mine {
+		return ""
+	}
+	return "\nWarning: this is your own active session."
+}
+
+// unpushedCommitsWarning is appended to delete confirmations so committed but
+// unpushed work isn't removed unnoticed; it is empty when nothing is at risk.
+func unpushedCommitsWarning(mgr *WorktreeManager, path string) string {
+	if mgr == nil {
+		return ""
+	}
+	count, ref, err := mgr.UnpushedCommits(path)
+	if err != nil || count == 0 {
+		return ""
+	}
+	noun := "commits"
+	if count == 1 {
+		noun = "commit"
+	}
+	return fmt.Sprintf("\nWarning: %d %s not on %s.", count, noun, ref)
+}
+
+func nestedWorktreeBanner(status WorktreeStatus) string {
+	if strings.TrimSpace(status.NestedWorktree) == "" {
+		return ""
+	}
+	return fmt.Sprintf("You're inside %s of %s (%s).", status.NestedWorktree, filepath.Base(status.PrimaryRoot), status.PrimaryRoot)
+}
+
+func renderViewHeader(status WorktreeStatus) string {
+	title, detail := worktreeSummary(status)
+	header := lipgloss.NewStyle().Foreground(currentTheme().Title).Render(title)
+	if detail == "" {
+		return header
+	}
+	return header + secondaryStyle.Render(": "+detail)
+}
+
+// worktreeSummary counts worktrees by state for the list header, e.g.
+// "5 worktrees" and "2 free, 2 in use, 1 orphaned — 3 PRs open".
+func worktreeSummary(status WorktreeStatus) (string, string) {
+	total := len(status.Worktrees)
+	if total == 0 {
+		return "Worktrees", ""
+	}
+	var free, inUse, orphaned, openPRs int
+	for _, wt := range status.Worktrees {
+		switch {
+		case isOrphanedPath(status, wt.Path):
+			orphaned++
+		case wt.Available:
+			free++
+		default:
+			inUse++
+		}
+		if prStatus := strings.ToLower(wt.PRStatus); wt.HasPR && prStatus != "merged" && prStatus != "closed" {
+			openPRs++
+		}
+	}
+	title := fmt.Sprintf("%d worktrees", total)
+	if total == 1 {
+		title = "1 worktree"
+	}
+	detail := fmt.Sprintf("%d free, %d in use", free, inUse)
+	if orphaned > 0 {
+		detail += fmt.Sprintf(", %d orphaned", orphaned)
+	}
+	switch openPRs {
+	case 0:
+	case 1:
+		detail += " — 1 PR open"
+	default:
+		detail += fmt.Sprintf(" — %d PRs open", openPRs)
+	}
+	return title, detail
 }
 
 func renderCreateProgress(m model) string {
@@ -1692,9 +3107,15 @@ func renderCreateProgress(m model) string {
 	if m.creatingExisting {
 		return fmt.Sprintf("Provisioning worktree for %s%s...", branchStyle.Render(branch), elapsed)
 	}
+	if m.creatingPR > 0 {
+		return fmt.Sprintf("Checking out PR %s%s...", branchStyle.Render(fmt.Sprintf("#%d", m.creatingPR)), elapsed)
+	}
+	if m.creatingDetached {
+		return fmt.Sprintf("Provisioning detached worktree at %s%s...", branchStyle.Render(branch), elapsed)
+	}
 	base := strings.TrimSpace(m.creatingBaseRef)
 	if base == "" {
-		base = resolveNewBranchBaseRef(m.openDefaultBaseRef, m.status.BaseRef, m.status.HasRemote)
+		base = m.newBranchBaseRef(m.creatingBranch)
 	}
 	if base != "" {
 		return fmt.Sprintf("Provisioning %s from %s%s...", branchStyle.Render(branch), branchInlineStyle.Render(base), elapsed)
@@ -1720,6 +3141,14 @@ type ghDataMsg struct {
 	fetchedByBranch bool
 	err             error
 }
+
+// branchPRDataMsg carries a forced re-fetch of a single branch's PR data.
+type branchPRDataMsg struct {
+	repoRoot string
+	branch   string
+	byBranch map[string]PRData
+	err      error
+}
 type createWorktreeDoneMsg struct {
 	created WorktreeInfo
 	err     error
@@ -1728,6 +3157,27 @@ type openDeleteWorktreeDoneMsg struct {
 	path string
 	err  error
 }
+type openDiscardChangesDoneMsg struct {
+	path string
+	err  error
+}
+
+type resetToRemoteDoneMsg struct {
+	branch string
+	err    error
+}
+
+type openRepairWorktreesDoneMsg struct {
+	output string
+	err    error
+}
+
+type ciRerunDoneMsg struct {
+	branch string
+	runs   int
+	err    error
+}
+
 type openUnlockWorktreeDoneMsg struct {
 	path string
 	err  error
@@ -1743,17 +3193,75 @@ type openUseReadyMsg struct {
 	openShell bool
 	err       error
 }
+type listMultiOpenDoneMsg struct {
+	started  []string
+	failed   []string
+	warnings []string
+}
+
 type openDefaultsSavedMsg struct {
 	err error
 }
 
+// focusRefreshCmd reloads what the poll tick would when the terminal regains
+// focus. Screens the poll leaves alone only get their dirty state refreshed.
+func (m model) focusRefreshCmd() tea.Cmd {
+	switch m.mode {
+	case modeList:
+		return fetchStatusCmd(m.orchestrator)
+	case modeOpen:
+		if m.openCreating || m.openLoading {
+			return nil
+		}
+		if m.openStage == openStageMain && !m.openShowDebug && strings.TrimSpace(m.openTypeahead) == "" {
+			return loadOpenScreenCmd(m.orchestrator, m.mgr)
+		}
+		return refreshOpenDirtyCmd(m.openSlots)
+	}
+	return nil
+}
+
+func completionMessage(branch string, err error) string {
+	if err != nil {
+		return "wtx: worktree failed: " + err.Error()
+	}
+	return "wtx: " + strings.TrimSpace(branch) + " is ready"
+}
+
 func fetchStatusCmd(orchestrator *WorktreeOrchestrator) tea.Cmd {
 	return func() tea.Msg {
 		if orchestrator == nil {
 			return statusMsg(WorktreeStatus{})
 		}
-		return statusMsg(orchestrator.Status())
+		return statusMsg(orchestrator.StatusWithLinked())
+	}
+}
+
+// clearNoticeMsg clears a transient warnMsg notice unless it was replaced.
+type clearNoticeMsg struct {
+	text string
+}
+
+var copyToClipboardFn = termenv.Copy
+
+// copyWorktreeAddCommand copies the git worktree add equivalent of creating a
+// worktree for branch, for sharing with people who don't use wtx.
+func (m model) copyWorktreeAddCommand(branch string, baseRef string) (tea.Model, tea.Cmd) {
+	command, err := m.mgr.WorktreeAddCommand(branch, baseRef)
+	if err != nil {
+		m.errMsg = err.Error()
+		return m, nil
 	}
+	copyToClipboardFn(command)
+	m.errMsg = ""
+	m.warnMsg = "Copied " + command
+	return m, clearNoticeCmd(m.warnMsg)
+}
+
+func clearNoticeCmd(text string) tea.Cmd {
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearNoticeMsg{text: text}
+	})
 }
 
 func pollStatusTickCmd() tea.Cmd {
@@ -1774,6 +3282,13 @@ func openPickRefreshTickCmd() tea.Cmd {
 	})
 }
 
+func refreshBranchPRDataCmd(orchestrator *WorktreeOrchestrator, repoRoot string, branch string) tea.Cmd {
+	return func() tea.Msg {
+		byBranch, err := orchestrator.PRDataForBranchesWithError(repoRoot, []string{branch}, true)
+		return branchPRDataMsg{repoRoot: repoRoot, branch: branch, byBranch: byBranch, err: err}
+	}
+}
+
 func fetchGHDataCmd(orchestrator *WorktreeOrchestrator, status WorktreeStatus, key string, force bool) tea.Cmd {
 	return func() tea.Msg {
 		var byBranch map[string]PRData
@@ -1802,6 +3317,13 @@ func createWorktreeCmd(mgr *WorktreeManager, branch string, baseRef string) tea.
 	}
 }
 
+func createDetachedWorktreeCmd(mgr *WorktreeManager, ref string) tea.Cmd {
+	return func() tea.Msg {
+		created, err := mgr.CreateDetachedWorktree(ref)
+		return createWorktreeDoneMsg{created: created, err: err}
+	}
+}
+
 func createWorktreeFromExistingCmd(mgr *WorktreeManager, branch string) tea.Cmd {
 	return func() tea.Msg {
 		created, err := mgr.CreateWorktreeFromBranch(branch)
@@ -1809,6 +3331,15 @@ func createWorktreeFromExistingCmd(mgr *WorktreeManager, branch string) tea.Cmd
 	}
 }
 
+// recordOpenSlotEvent records event for the open-screen slot at path.
+func (m model) recordOpenSlotEvent(event string, path string) {
+	branch := ""
+	if slot, ok := findOpenSlotByPath(m.openSlots, path); ok {
+		branch = slot.Branch
+	}
+	recordEvent(m.status.RepoRoot, wtxEvent{Event: event, Branch: branch})
+}
+
 func deleteOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
@@ -1819,6 +3350,49 @@ func deleteOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
 	}
 }
 
+func archiveOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return openDeleteWorktreeDoneMsg{path: path, err: fmt.Errorf("worktree manager unavailable")}
+		}
+		_, err := mgr.ArchiveWorktree(path)
+		return openDeleteWorktreeDoneMsg{path: path, err: err}
+	}
+}
+
+// resetToRemoteCmd runs ResetToRemote off the UI loop, since it fetches.
+func resetToRemoteCmd(mgr *WorktreeManager, path string, branch string) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return resetToRemoteDoneMsg{branch: branch, err: fmt.Errorf("worktree manager unavailable")}
+		}
+		return resetToRemoteDoneMsg{branch: branch, err: mgr.ResetToRemote(path, branch)}
+	}
+}
+
+func discardOpenWorktreeChangesCmd(mgr *WorktreeManager, path string) tea.Cmd {
+	return func() tea.Msg {
+		if mgr == nil {
+			return openDiscardChangesDoneMsg{path: path, err: fmt.Errorf("worktree manager unavailable")}
+		}
+		return openDiscardChangesDoneMsg{path: path, err: mgr.DiscardChanges(path)}
+	}
+}
+
+func rerunFailedChecksCmd(repoRoot string, branch string, prNumber int) tea.Cmd {
+	return func() tea.Msg {
+		runs, err := rerunFailedPRChecks(repoRoot, prNumber)
+		return ciRerunDoneMsg{branch: branch, runs: runs, err: err}
+	}
+}
+
+func repairOpenWorktreesCmd(mgr *WorktreeManager) tea.Cmd {
+	return func() tea.Msg {
+		out, err := mgr.RepairWorktrees()
+		return openRepairWorktreesDoneMsg{output: out, err: err}
+	}
+}
+
 func unlockOpenWorktreeCmd(mgr *WorktreeManager, path string) tea.Cmd {
 	return func() tea.Msg {
 		if mgr == nil {
@@ -1910,9 +3484,68 @@ func createAndUseNewWorktreeCmd(mgr *WorktreeManager, branch string, baseRef str
 	}
 }
 
-func saveOpenDefaultsCmd(baseRef string, fetch bool) tea.Cmd {
+func openMarkedWorktreesCmd(mgr *WorktreeManager, runner *Runner, targets []WorktreeInfo) tea.Cmd {
 	return func() tea.Msg {
+		done := listMultiOpenDoneMsg{}
+		// Check the agent before locking anything, so a missing program fails
+		// once here instead of in every tmux window.
+		checked := map[string]error{}
+		agentReady := func(runCmd string) error {
+			if runCmd == "" {
+				return errors.New("agent command not configured; run wtx config")
+			}
+			if err, ok := checked[runCmd]; ok {
+				return err
+			}
+			checked[runCmd] = checkAgentInstalled(runCmd)
+			return checked[runCmd]
+		}
 		cfg, err := LoadConfig()
+		if err == nil {
+			err = agentReady(strings.TrimSpace(cfg.AgentCommand))
+		}
+		if err != nil {
+			done.failed = append(done.failed, err.Error())
+			return done
+		}
+		for _, wt := range targets {
+			cfg, err := loadConfigForDir(wt.Path)
+			if err == nil {
+				err = agentReady(strings.TrimSpace(cfg.AgentCommand))
+			}
+			if err != nil {
+				done.failed = append(done.failed, wt.Branch+": "+err.Error())
+				continue
+			}
+			runCmd := strings.TrimSpace(cfg.AgentCommand)
+			workDir, warning := agentWorkDir(wt.Path, cfg.AgentSubdir)
+			if warning != "" {
+				done.warnings = append(done.warnings, wt.Branch+": "+warning)
+			}
+			lock, err := mgr.AcquireWorktreeLock(wt.Path)
+			if err != nil {
+				done.failed = append(done.failed, wt.Branch+": "+err.Error())
+				continue
+			}
+			env, envErr := loadWorktreeEnv(wt.Path)
+			if envErr != nil {
+				done.warnings = append(done.warnings, wt.Branch+": "+envErr.Error())
+			}
+			agentCmd := withAgentEnv(runner.expandAgentCommand(runCmd, cfg, wt.Path, wt.Branch), env)
+			if err := runner.StartInTmuxWindow(wt.Path, workDir, wt.Branch, lock, agentCmd); err != nil {
+				lock.Release()
+				done.failed = append(done.failed, wt.Branch+": "+err.Error())
+				continue
+			}
+			done.started = append(done.started, wt.Branch)
+		}
+		return done
+	}
+}
+
+func saveOpenDefaultsCmd(baseRef string, fetch bool) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := loadGlobalConfig()
 		if err != nil {
 			exists, exErr := ConfigExists()
 			if exErr != nil {
@@ -1936,10 +3569,21 @@ func saveOpenDefaultsCmd(baseRef string, fetch bool) tea.Cmd {
 	}
 }
 
-func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[string]bool, loadingGlyph string) string {
+func renderSelector(status WorktreeStatus, cursor int, width int, pendingByBranch map[string]bool, marked map[string]bool, loadingGlyph string) string {
+	if !status.InRepo {
+		return ""
+	}
+	return uiview.RenderWorktreeSelector(selectorRows(status, pendingByBranch, marked, loadingGlyph), cursor, width, viewStyles())
+}
+
+func renderCompactSelector(status WorktreeStatus, cursor int, width int, pendingByBranch map[string]bool, marked map[string]bool, loadingGlyph string) string {
 	if !status.InRepo {
 		return ""
 	}
+	return uiview.RenderCompactWorktreeSelector(selectorRows(status, pendingByBranch, marked, loadingGlyph), cursor, width, viewStyles())
+}
+
+func selectorRows(status WorktreeStatus, pendingByBranch map[string]bool, marked map[string]bool, loadingGlyph string) []uiview.WorktreeRow {
 	rows := make([]uiview.WorktreeRow, 0, len(status.Worktrees)+1)
 	orphaned := make(map[string]bool, len(status.Orphaned))
 	for _, wt := range status.Orphaned {
@@ -1947,60 +3591,87 @@ func renderSelector(status WorktreeStatus, cursor int, pendingByBranch map[strin
 	}
 	worktrees := worktreesForDisplay(status)
 	for _, wt := range worktrees {
-		label := wt.Branch
+		branch := worktreeBranchLabel(wt)
+		label := branch
 		disabled := false
 		if orphaned[wt.Path] {
-			label = fmt.Sprintf("%s (orphaned)", wt.Branch)
+			label = fmt.Sprintf("%s (orphaned)", branch)
 			disabled = true
+		} else if wt.LockedByMe {
+			label = branch + lockStateSuffix("yours", wt.LockLabel)
+			disabled = !wt.Available
 		} else if !wt.Available {
-			label = wt.Branch + " (in use)"
+			label = branch + lockStateSuffix("in use", wt.LockLabel)
 			disabled = true
+		} else if marked[wt.Path] {
+			label = "[x] " + branch
+		}
+		if wt.RemoteDiverged {
+			label += " (remote changed)"
+		}
+		if wt.RemoteBranchGone {
+			label += " (remote gone)"
+		}
+		if wt.GitState != "" {
+			label += " (" + wt.GitState + ")"
+		}
+		pending := pendingByBranch[prDataKey(wt)]
+		group := ""
+		if status.GroupByPR {
+			group = prStatusGroupLabel(prStatusSortBucket(wt))
+		} else if len(status.Linked) > 0 {
+			group = filepath.Base(worktreeRepoRoot(status, wt))
 		}
-		pending := pendingByBranch[strings.TrimSpace(wt.Branch)]
 		rows = append(rows, uiview.WorktreeRow{
 			BranchLabel:     label,
 			PRLabel:         formatPRLabel(wt, pending, loadingGlyph),
 			CILabel:         formatCILabel(wt, pending, loadingGlyph),
 			ReviewLabel:     formatReviewLabel(wt, pending, loadingGlyph),
+			Note:            wt.Note,
 			CommentsLabel:   formatCommentsLabel(wt, pending, loadingGlyph),
 			UnresolvedLabel: formatUnresolvedLabel(wt, pending, loadingGlyph),
 			PRStatusLabel:   formatPRStatusLabel(wt, pending, loadingGlyph),
+			Group:           group,
 			Disabled:        disabled,
 		})
 	}
 	rows = append(rows, uiview.WorktreeRow{BranchLabel: "+ New worktree"})
-	return uiview.RenderWorktreeSelector(rows, cursor, viewStyles())
+	return rows
+}
+
+// listChromeLines approximates the non-row lines of the full list view: top
+// bar, table header, detail line, help, and a couple of messages.
+const listChromeLines = 10
+
+// compactLayout reports whether the list uses the one-line-per-worktree
+// selector: always with compact_selector, otherwise once the full view would
+// overflow the terminal height.
+func (m model) compactLayout() bool {
+	if m.compactSelector {
+		return true
+	}
+	return m.height > 0 && m.height < selectorRowCount(m.status)+listChromeLines
 }
 
+// Styles shared by the TUI screens; applyTheme sets them from the palette.
 var (
-	baseStyle   = lipgloss.NewStyle()
-	bannerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FFF7DB")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
-	errorStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
-	secondaryStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-	actionNormalStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	actionSelectedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
-	selectorNormalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("251"))
-	selectorSelectedStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#7D56F4")).
-				Bold(true)
-	selectorDisabledStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("241"))
-	selectorDisabledSelectedStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#7D56F4")).
-					Bold(true)
-	selectorHeaderStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
-	branchStyle                 = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
-	branchInlineStyle           = lipgloss.NewStyle().Bold(true)
-	warnStyle                   = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
-	tmuxStatusDisabledHintStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#E8DFA5"))
-	updateHintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("239"))
-	inputStyle      = lipgloss.NewStyle().
-			Padding(0, 1)
+	baseStyle                     lipgloss.Style
+	bannerStyle                   lipgloss.Style
+	errorStyle                    lipgloss.Style
+	secondaryStyle                lipgloss.Style
+	actionNormalStyle             lipgloss.Style
+	actionSelectedStyle           lipgloss.Style
+	selectorNormalStyle           lipgloss.Style
+	selectorSelectedStyle         lipgloss.Style
+	selectorDisabledStyle         lipgloss.Style
+	selectorDisabledSelectedStyle lipgloss.Style
+	selectorHeaderStyle           lipgloss.Style
+	branchStyle                   lipgloss.Style
+	branchInlineStyle             lipgloss.Style
+	warnStyle                     lipgloss.Style
+	tmuxStatusDisabledHintStyle   lipgloss.Style
+	updateHintStyle               lipgloss.Style
+	inputStyle                    lipgloss.Style
 )
 
 func renderUpdateHint(hint string, isError bool) string {
@@ -2039,6 +3710,10 @@ const (
 	modeAction
 	modeBranchName
 	modeBranchPick
+	modeNote
+	modeLockLabel
+	modeLog
+	modeTitleLabel
 )
 
 type openStage int
@@ -2047,8 +3722,34 @@ const (
 	openStageMain openStage = iota
 	openStageNewBranchConfig
 	openStagePickWorktree
+	openStagePickBaseRef
+	openStagePickIssue
 )
 
+func newNoteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "what is this worktree for?"
+	ti.CharLimit = 120
+	ti.Width = 60
+	return ti
+}
+
+func newLockLabelInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "what are you using it for?"
+	ti.CharLimit = 60
+	ti.Width = 60
+	return ti
+}
+
+func newTitleLabelInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. review"
+	ti.CharLimit = 40
+	ti.Width = 40
+	return ti
+}
+
 func newBranchInput() textinput.Model {
 	ti := textinput.New()
 	ti.Placeholder = "branch name"
@@ -2057,6 +3758,22 @@ func newBranchInput() textinput.Model {
 	return ti
 }
 
+func newBaseRefInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "filter remote branches"
+	ti.CharLimit = 200
+	ti.Width = 40
+	return ti
+}
+
+func newIssueInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "filter open issues"
+	ti.CharLimit = 200
+	ti.Width = 40
+	return ti
+}
+
 func newCreateBranchInput() textinput.Model {
 	ti := textinput.New()
 	ti.Placeholder = "tab to generate draft name"
@@ -2086,6 +3803,14 @@ func selectedWorktree(status WorktreeStatus, cursor int) (WorktreeInfo, bool) {
 	return worktrees[cursor], true
 }
 
+// lockStateSuffix renders " (in use)" or, with a lock label, " (in use: label)".
+func lockStateSuffix(state string, label string) string {
+	if label = strings.TrimSpace(label); label != "" {
+		return " (" + state + ": " + label + ")"
+	}
+	return " (" + state + ")"
+}
+
 func isOrphanedPath(status WorktreeStatus, path string) bool {
 	for _, wt := range status.Orphaned {
 		if wt.Path == path {
@@ -2095,35 +3820,89 @@ func isOrphanedPath(status WorktreeStatus, path string) bool {
 	return false
 }
 
-func actionItems(branch string, baseRef string) []string {
-	base := strings.TrimSpace(baseRef)
-	if base == "" {
-		base = "main"
+// actionKind names an action menu entry; action_order in the config lists
+// these to reorder the menu.
+type actionKind string
+
+const (
+	actionUse            actionKind = "use"
+	actionNewBranch      actionKind = "new_branch"
+	actionExistingBranch actionKind = "existing_branch"
+	actionShell          actionKind = "shell"
+	actionDetached       actionKind = "detached"
+	actionCheckoutPR     actionKind = "pr"
+	actionLog            actionKind = "log"
+)
+
+var (
+	defaultActionKinds       = []actionKind{actionUse, actionNewBranch, actionExistingBranch, actionShell, actionLog}
+	defaultCreateActionKinds = []actionKind{actionNewBranch, actionExistingBranch, actionDetached, actionCheckoutPR}
+)
+
+// defaultWorktreeAction maps default_worktree_action to the action enter
+// runs; anything but use or shell keeps the menu.
+func defaultWorktreeAction(name string) actionKind {
+	switch kind := actionKind(strings.ToLower(strings.TrimSpace(name))); kind {
+	case actionUse, actionShell:
+		return kind
+	}
+	return ""
+}
+
+func (m model) actionKinds() []actionKind {
+	if m.actionCreate {
+		return orderActionKinds(defaultCreateActionKinds, m.actionOrder)
+	}
+	return orderActionKinds(defaultActionKinds, m.actionOrder)
+}
+
+// orderActionKinds puts the listed kinds first, in the configured order, and
+// appends the rest in their default order. Kinds the menu doesn't offer are
+// ignored.
+func orderActionKinds(defaults []actionKind, order []string) []actionKind {
+	out := make([]actionKind, 0, len(defaults))
+	used := map[actionKind]bool{}
+	for _, name := range order {
+		kind := actionKind(strings.ToLower(strings.TrimSpace(name)))
+		if used[kind] || !slices.Contains(defaults, kind) {
+			continue
+		}
+		used[kind] = true
+		out = append(out, kind)
 	}
-	return []string{
-		"Use " + branchInlineStyle.Render(branch),
-		"Checkout new branch from " + branchInlineStyle.Render(base),
-		"Choose an existing branch",
-		"Open shell here",
+	for _, kind := range defaults {
+		if !used[kind] {
+			out = append(out, kind)
+		}
 	}
+	return out
 }
 
-func createActionItems(baseRef string) []string {
+func actionMenuItems(kinds []actionKind, branch string, baseRef string) []string {
 	base := strings.TrimSpace(baseRef)
 	if base == "" {
 		base = "main"
 	}
-	return []string{
-		"Checkout new branch from " + branchInlineStyle.Render(base),
-		"Choose an existing branch",
-	}
-}
-
-func currentActionItems(branch string, baseRef string, create bool) []string {
-	if create {
-		return createActionItems(baseRef)
+	items := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		switch kind {
+		case actionUse:
+			items = append(items, "Use "+branchInlineStyle.Render(branch))
+		case actionNewBranch:
+			items = append(items, "Checkout new branch from "+branchInlineStyle.Render(base))
+		case actionExistingBranch:
+			items = append(items, "Choose an existing branch")
+		case actionShell:
+			items = append(items, "Open shell here")
+		case actionDetached:
+			items = append(items, "Detached at a tag or commit")
+		case actionCheckoutPR:
+			items = append(items, "Checkout PR...")
+		case actionLog:
+			items = append(items, "View log")
+		}
 	}
-	return actionItems(branch, baseRef)
+	return items
 }
 
 func currentWorktreePath(status WorktreeStatus, cursor int) string {
@@ -2198,6 +3977,9 @@ func formatCILabel(wt WorktreeInfo, pending bool, loadingGlyph string) string {
 	}
 	switch wt.CIState {
 	case PRCISuccess:
+		if names := strings.TrimSpace(wt.CIFailingNames); names != "" {
+			return fmt.Sprintf("✓ %d/%d (%s failing)", wt.CIDone, wt.CITotal, names)
+		}
 		return fmt.Sprintf("✓ %d/%d", wt.CIDone, wt.CITotal)
 	case PRCIFail:
 		if names := strings.TrimSpace(wt.CIFailingNames); names != "" {
@@ -2289,8 +4071,6 @@ func filterBranches(options []string, query string) []string {
 	return out
 }
 
-const maxBranchSuggestions = 15
-
 func availableBranchOptions(status WorktreeStatus, mgr *WorktreeManager, includeInUse bool) ([]string, error) {
 	options, err := mgr.ListLocalBranchesByRecentUse()
 	if err != nil {
@@ -2304,6 +4084,7 @@ func availableBranchOptions(status WorktreeStatus, mgr *WorktreeManager, include
 		}
 		inUse[name] = true
 	}
+	options = excludeBranches(options, configuredExcludeBranchPatterns(), inUse)
 	filtered := make([]string, 0, len(options))
 	for _, opt := range options {
 		if !includeInUse && inUse[opt] {
@@ -2311,8 +4092,8 @@ func availableBranchOptions(status WorktreeStatus, mgr *WorktreeManager, include
 		}
 		filtered = append(filtered, opt)
 	}
-	if len(filtered) > maxBranchSuggestions {
-		filtered = filtered[:maxBranchSuggestions]
+	if limit := configuredMainScreenBranchLimit(); len(filtered) > limit {
+		filtered = filtered[:limit]
 	}
 	if len(filtered) == 0 {
 		if includeInUse {
@@ -2359,6 +4140,19 @@ func selectedBranch(suggestions []string, index int) (string, bool) {
 	return value, value != ""
 }
 
+func markedWorktrees(status WorktreeStatus, marked map[string]bool) []WorktreeInfo {
+	if len(marked) == 0 {
+		return nil
+	}
+	out := make([]WorktreeInfo, 0, len(marked))
+	for _, wt := range worktreesForDisplay(status) {
+		if marked[wt.Path] && wt.Available && !isOrphanedPath(status, wt.Path) {
+			out = append(out, wt)
+		}
+	}
+	return out
+}
+
 func selectorRowCount(status WorktreeStatus) int {
 	if !status.InRepo {
 		return 0
@@ -2367,9 +4161,9 @@ func selectorRowCount(status WorktreeStatus) int {
 }
 
 func pendingBranchesByName(status WorktreeStatus) map[string]bool {
-	out := make(map[string]bool, len(status.Worktrees))
-	for _, wt := range status.Worktrees {
-		name := strings.TrimSpace(wt.Branch)
+	out := make(map[string]bool, len(status.Worktrees)+len(status.Linked))
+	for _, wt := range append(append([]WorktreeInfo{}, status.Worktrees...), status.Linked...) {
+		name := prDataKey(wt)
 		if name == "" {
 			continue
 		}
@@ -2383,10 +4177,10 @@ func ghDataKeyForStatus(status WorktreeStatus) string {
 	if repo == "" || !status.InRepo {
 		return ""
 	}
-	branches := make([]string, 0, len(status.Worktrees))
-	seen := make(map[string]bool, len(status.Worktrees))
-	for _, wt := range status.Worktrees {
-		name := strings.TrimSpace(wt.Branch)
+	branches := make([]string, 0, len(status.Worktrees)+len(status.Linked))
+	seen := make(map[string]bool, len(status.Worktrees)+len(status.Linked))
+	for _, wt := range append(append([]WorktreeInfo{}, status.Worktrees...), status.Linked...) {
+		name := prDataKey(wt)
 		if name == "" || seen[name] {
 			continue
 		}
@@ -2426,8 +4220,8 @@ func worktreesForDisplay(status WorktreeStatus) []WorktreeInfo {
 	for _, wt := range status.Orphaned {
 		orphaned[wt.Path] = true
 	}
-	out := make([]WorktreeInfo, len(status.Worktrees))
-	copy(out, status.Worktrees)
+	out := make([]WorktreeInfo, 0, len(status.Worktrees)+len(status.Linked))
+	out = append(append(out, status.Worktrees...), status.Linked...)
 	sort.SliceStable(out, func(i, j int) bool {
 		iFree := out[i].Available && !orphaned[out[i].Path]
 		jFree := out[j].Available && !orphaned[out[j].Path]
@@ -2448,52 +4242,125 @@ func worktreesForDisplay(status WorktreeStatus) []WorktreeInfo {
 		}
 		return out[i].Path > out[j].Path
 	})
+	if status.GroupByPR {
+		sort.SliceStable(out, func(i, j int) bool {
+			return prStatusSortBucket(out[i]) < prStatusSortBucket(out[j])
+		})
+	} else if len(status.Linked) > 0 {
+		// Group by repo: this repo first, then linked repos in config order.
+		rank := map[string]int{"": 0}
+		for _, wt := range status.Linked {
+			if _, ok := rank[wt.LinkedRepo]; !ok {
+				rank[wt.LinkedRepo] = len(rank)
+			}
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			return rank[out[i].LinkedRepo] < rank[out[j].LinkedRepo]
+		})
+	}
 	return out
 }
 
+const (
+	prBucketCanMerge = iota
+	prBucketAwaitingReview
+	prBucketAwaitingCI
+	prBucketConflict
+	prBucketOtherPR
+	prBucketNoPR
+)
+
+func prStatusSortBucket(wt WorktreeInfo) int {
+	if !wt.HasPR || wt.PRNumber <= 0 {
+		return prBucketNoPR
+	}
+	switch strings.ToLower(strings.TrimSpace(wt.PRStatus)) {
+	case "can-merge":
+		return prBucketCanMerge
+	case "awaiting-review", "awaiting-comments":
+		return prBucketAwaitingReview
+	case "awaiting-ci":
+		return prBucketAwaitingCI
+	case "conflict":
+		return prBucketConflict
+	default:
+		return prBucketOtherPR
+	}
+}
+
+func prStatusGroupLabel(bucket int) string {
+	switch bucket {
+	case prBucketCanMerge:
+		return "Can merge"
+	case prBucketAwaitingReview:
+		return "Awaiting review"
+	case prBucketAwaitingCI:
+		return "Awaiting CI"
+	case prBucketConflict:
+		return "Conflicts"
+	case prBucketOtherPR:
+		return "Other PRs"
+	default:
+		return "No PR"
+	}
+}
+
 func applyPRDataToStatus(status *WorktreeStatus, byBranch map[string]PRData) {
 	if status == nil {
 		return
 	}
 	for i := range status.Worktrees {
-		b := strings.TrimSpace(status.Worktrees[i].Branch)
-		status.Worktrees[i].HasPR = false
-		status.Worktrees[i].PRNumber = 0
-		status.Worktrees[i].PRURL = ""
-		status.Worktrees[i].PRStatus = ""
-		status.Worktrees[i].CIState = PRCINone
-		status.Worktrees[i].CIDone = 0
-		status.Worktrees[i].CITotal = 0
-		status.Worktrees[i].CIFailingNames = ""
-		status.Worktrees[i].Approved = false
-		status.Worktrees[i].ReviewApproved = 0
-		status.Worktrees[i].ReviewRequired = 0
-		status.Worktrees[i].ReviewKnown = false
-		status.Worktrees[i].UnresolvedComments = 0
-		status.Worktrees[i].ResolvedComments = 0
-		status.Worktrees[i].CommentThreadsTotal = 0
-		status.Worktrees[i].CommentsKnown = false
-		if b == "" {
-			continue
-		}
-		if pr, ok := byBranch[b]; ok {
-			status.Worktrees[i].HasPR = true
-			status.Worktrees[i].PRNumber = pr.Number
-			status.Worktrees[i].PRURL = pr.URL
-			status.Worktrees[i].PRStatus = pr.Status
-			status.Worktrees[i].CIState = pr.CIState
-			status.Worktrees[i].CIDone = pr.CICompleted
-			status.Worktrees[i].CITotal = pr.CITotal
-			status.Worktrees[i].CIFailingNames = pr.CIFailingNames
-			status.Worktrees[i].Approved = pr.Approved
-			status.Worktrees[i].ReviewApproved = pr.ReviewApproved
-			status.Worktrees[i].ReviewRequired = pr.ReviewRequired
-			status.Worktrees[i].ReviewKnown = pr.ReviewKnown
-			status.Worktrees[i].UnresolvedComments = pr.UnresolvedComments
-			status.Worktrees[i].ResolvedComments = pr.ResolvedComments
-			status.Worktrees[i].CommentThreadsTotal = pr.CommentThreadsTotal
-			status.Worktrees[i].CommentsKnown = pr.CommentsKnown
-		}
+		applyPRDataToWorktree(&status.Worktrees[i], byBranch)
+	}
+	for i := range status.Linked {
+		applyPRDataToWorktree(&status.Linked[i], byBranch)
+	}
+}
+
+func applyPRDataToWorktree(wt *WorktreeInfo, byBranch map[string]PRData) {
+	b := prDataKey(*wt)
+	wt.HasPR = false
+	wt.PRNumber = 0
+	wt.PRURL = ""
+	wt.PRStatus = ""
+	wt.CIState = PRCINone
+	wt.CIDone = 0
+	wt.CITotal = 0
+	wt.CIFailingNames = ""
+	wt.CIFailingURL = ""
+	wt.LatestCommentURL = ""
+	wt.Reviewers = nil
+	wt.Approved = false
+	wt.ReviewApproved = 0
+	wt.ReviewRequired = 0
+	wt.ReviewKnown = false
+	wt.UnresolvedComments = 0
+	wt.ResolvedComments = 0
+	wt.CommentThreadsTotal = 0
+	wt.CommentsKnown = false
+	if b == "" {
+		return
+	}
+	if pr, ok := byBranch[b]; ok {
+		wt.HasPR = true
+		wt.PRNumber = pr.Number
+		wt.PRURL = pr.URL
+		wt.PRStatus = pr.Status
+		wt.CIState = pr.CIState
+		wt.CIDone = pr.CICompleted
+		wt.CITotal = pr.CITotal
+		wt.CIFailingNames = pr.CIFailingNames
+		wt.CIFailingURL = pr.CIFailingURL
+		wt.LatestCommentURL = pr.LatestCommentURL
+		wt.Reviewers = pr.Reviewers
+		wt.Approved = pr.Approved
+		wt.ReviewApproved = pr.ReviewApproved
+		wt.ReviewRequired = pr.ReviewRequired
+		wt.ReviewKnown = pr.ReviewKnown
+		wt.UnresolvedComments = pr.UnresolvedComments
+		wt.ResolvedComments = pr.ResolvedComments
+		wt.CommentThreadsTotal = pr.CommentThreadsTotal
+		wt.CommentsKnown = pr.CommentsKnown
 	}
 }
 
@@ -2564,7 +4431,7 @@ func findOpenSlotByPath(slots []openSlotState, path string) (openSlotState, bool
 func newSpinner() spinner.Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	s.Style = lipgloss.NewStyle().Foreground(currentTheme().Accent)
 	return s
 }
 
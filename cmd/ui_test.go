@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"errors"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -98,6 +100,162 @@ func TestDraftBranchName(t *testing.T) {
 	}
 }
 
+func TestCompleteBranchName(t *testing.T) {
+	options := []string{"main", "feature/login", "origin/feature/logout"}
+	cases := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{value: "feat", want: "feature/login", ok: true},
+		{value: "origin/f", want: "origin/feature/logout", ok: true},
+		{value: "main", ok: false},
+		{value: "", ok: false},
+	}
+	for _, tc := range cases {
+		got, ok := completeBranchName(tc.value, options)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("completeBranchName(%q) = %q, %v; want %q, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestModeBranchName_EnterOnExistingBranchChecksItOut(t *testing.T) {
+	m := newModel()
+	m.ready = true
+	m.status = WorktreeStatus{GitInstalled: true, InRepo: true}
+	m.mode = modeBranchName
+	m.actionCreate = true
+	m.branchNameOptions = []string{"main", "feature/login"}
+	m.newBranchInput.Focus()
+	m.newBranchInput.SetValue("feature/login")
+
+	if !strings.Contains(m.View(), "already exists") {
+		t.Fatalf("expected existing-branch hint in view")
+	}
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(model)
+	if updated.mode != modeCreating || !updated.creatingExisting || updated.creatingBranch != "feature/login" {
+		t.Fatalf("expected checkout of existing branch, got mode=%v existing=%v branch=%q", updated.mode, updated.creatingExisting, updated.creatingBranch)
+	}
+}
+
+func TestHelpOverlay_TogglesWithQuestionMarkPerMode(t *testing.T) {
+	m := newModel()
+	m.ready = true
+	m.status = WorktreeStatus{GitInstalled: true, InRepo: true}
+	m.mode = modeList
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	updated := updatedModel.(model)
+	if !updated.showHelp {
+		t.Fatalf("expected ? to open the help overlay")
+	}
+	if view := updated.View(); !strings.Contains(view, "Worktree list keys") || !strings.Contains(view, "re-request review") {
+		t.Fatalf("expected list bindings in overlay, got %q", view)
+	}
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	updated = updatedModel.(model)
+	if !updated.showHelp || updated.mode != modeList {
+		t.Fatalf("expected other keys to be swallowed while help is open")
+	}
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(model).showHelp {
+		t.Fatalf("expected esc to close the help overlay")
+	}
+
+	m.mode = modeOpen
+	m.openStage = openStagePickBaseRef
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if view := updatedModel.(model).View(); !strings.Contains(view, "Pick base ref keys") {
+		t.Fatalf("expected base ref bindings in overlay, got %q", view)
+	}
+
+	m.mode = modeNote
+	m.noteInput.Focus()
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	updated = updatedModel.(model)
+	if updated.showHelp || updated.noteInput.Value() != "?" {
+		t.Fatalf("expected ? to be typed into the note, got help=%v value=%q", updated.showHelp, updated.noteInput.Value())
+	}
+}
+
+func TestOrderActionKinds_ListedFirstRestInDefaultOrder(t *testing.T) {
+	got := orderActionKinds(defaultActionKinds, []string{"Shell", "pr", "shell", "use"})
+	want := []actionKind{actionShell, actionUse, actionNewBranch, actionExistingBranch, actionLog}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestModeAction_EnterRunsReorderedAction(t *testing.T) {
+	m := newModel()
+	m.mode = modeAction
+	m.actionOrder = []string{"shell"}
+	m.status = WorktreeStatus{GitInstalled: true, InRepo: true, Worktrees: []WorktreeInfo{{Path: "/wt/1", Branch: "feature/a", Available: true}}}
+	m.listIndex = 0
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(model)
+	if !updated.pendingOpenShell || updated.pendingPath != "/wt/1" {
+		t.Fatalf("expected first entry to open a shell, got shell=%v path=%q", updated.pendingOpenShell, updated.pendingPath)
+	}
+}
+
+func TestModeList_DefaultWorktreeActionRunsOnEnterAndAOpensMenu(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.defaultAction = defaultWorktreeAction(" Shell ")
+	m.status = WorktreeStatus{GitInstalled: true, InRepo: true, Worktrees: []WorktreeInfo{{Path: "/wt/1", Branch: "feature/a", Available: true}}}
+	m.listIndex = 0
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(model)
+	if !updated.pendingOpenShell || updated.pendingPath != "/wt/1" {
+		t.Fatalf("expected enter to open a shell, got shell=%v path=%q", updated.pendingOpenShell, updated.pendingPath)
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if updated := updatedModel.(model); updated.mode != modeAction || updated.pendingPath != "" {
+		t.Fatalf("expected a to open the action menu, got mode=%v path=%q", updated.mode, updated.pendingPath)
+	}
+	if defaultWorktreeAction("menu") != "" || defaultWorktreeAction("pr") != "" {
+		t.Fatalf("expected menu and unknown actions to keep the menu")
+	}
+}
+
+func TestModeAction_ViewLogShowsCommitsAndEscReturns(t *testing.T) {
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q")
+	m := newModel()
+	m.ready = true
+	m.mode = modeAction
+	m.actionOrder = []string{"log"}
+	m.status = WorktreeStatus{GitInstalled: true, InRepo: true, Worktrees: []WorktreeInfo{{Path: repo, Branch: "main", Available: true}}}
+	m.listIndex = 0
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(model)
+	if updated.mode != modeLog || cmd == nil {
+		t.Fatalf("expected the log view to open and load, got mode=%v", updated.mode)
+	}
+	updatedModel, _ = updated.Update(cmd())
+	if view := updatedModel.(model).View(); !strings.Contains(view, "No commits yet.") {
+		t.Fatalf("expected an unborn branch to show no commits, got %q", view)
+	}
+
+	writeTestFile(t, filepath.Join(repo, "a.txt"), "one\n")
+	runTestGit(t, repo, "add", "a.txt")
+	runTestGit(t, repo, "commit", "-q", "-m", "first change")
+	updatedModel, _ = updated.Update(loadWorktreeLogCmd(repo)())
+	if view := updatedModel.(model).View(); !strings.Contains(view, "first change") {
+		t.Fatalf("expected the commit subject in the log, got %q", view)
+	}
+	updatedModel, _ = updatedModel.(model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(model).mode != modeList {
+		t.Fatalf("expected esc to return to the list")
+	}
+}
+
 func TestModeBranchPick_AllowsTypingKAndJInFilter(t *testing.T) {
 	m := newModel()
 	m.mode = modeBranchPick
@@ -242,3 +400,525 @@ func TestOpenScreenPRDataIgnoredForSearchAllBranchList(t *testing.T) {
 		t.Fatalf("expected search-all branch rows to remain without PR data")
 	}
 }
+
+func TestOpenScreenLoadReportsStagesAndCanCancel(t *testing.T) {
+	m := newModel()
+	if view := m.View(); !strings.Contains(view, openLoadStageWorktrees) || !strings.Contains(view, "q to cancel") {
+		t.Fatalf("expected initial stage with cancel hint, got %q", view)
+	}
+
+	updatedModel, cmd := m.Update(openScreenWorktreesMsg{status: WorktreeStatus{InRepo: true}})
+	updated := updatedModel.(model)
+	if updated.openLoadStage != openLoadStageStatus || cmd == nil {
+		t.Fatalf("expected status stage with follow-up load, got %q", updated.openLoadStage)
+	}
+	if view := updated.View(); !strings.Contains(view, openLoadStageStatus) {
+		t.Fatalf("expected status stage in view, got %q", view)
+	}
+
+	_, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if cmd == nil {
+		t.Fatalf("expected q to quit while loading")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected quit message while loading")
+	}
+}
+
+func TestOpenScreenCtrlLRefreshesDirtyStatusOnly(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	m.openStage = openStageMain
+	m.openSlots = []openSlotState{{Path: "/tmp/wt.1", Branch: "feature/a"}}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	updated := updatedModel.(model)
+	if updated.openLoading {
+		t.Fatalf("expected dirty-only refresh to skip full reload")
+	}
+	if cmd == nil {
+		t.Fatalf("expected dirty status command")
+	}
+	if _, ok := cmd().(openScreenDirtyMsg); !ok {
+		t.Fatalf("expected openScreenDirtyMsg from dirty refresh")
+	}
+}
+
+func TestFocusMsg_RefreshesDirtyStatusWhilePollIsPaused(t *testing.T) {
+	m := newModel()
+	m.mode = modeOpen
+	m.openStage = openStageMain
+	m.openLoading = false
+	m.openShowDebug = true
+	m.openSlots = []openSlotState{{Path: "/tmp/wt.1", Branch: "feature/a"}}
+
+	_, cmd := m.Update(tea.FocusMsg{})
+	if cmd == nil {
+		t.Fatalf("expected a refresh on focus")
+	}
+	if _, ok := cmd().(openScreenDirtyMsg); !ok {
+		t.Fatalf("expected the debug view to refresh dirty status only")
+	}
+
+	m.openCreating = true
+	if _, cmd := m.Update(tea.FocusMsg{}); cmd != nil {
+		t.Fatalf("expected no refresh while creating a worktree")
+	}
+}
+
+func TestWorktreesForDisplay_GroupsByPRStatus(t *testing.T) {
+	status := WorktreeStatus{
+		InRepo:    true,
+		GroupByPR: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "no-pr", Available: true, LastUsedUnix: 3},
+			{Path: "/wt/2", Branch: "ci", Available: true, LastUsedUnix: 2, HasPR: true, PRNumber: 2, PRStatus: "awaiting-ci"},
+			{Path: "/wt/3", Branch: "merge", Available: true, LastUsedUnix: 1, HasPR: true, PRNumber: 3, PRStatus: "can-merge"},
+		},
+	}
+	got := worktreesForDisplay(status)
+	order := []string{got[0].Branch, got[1].Branch, got[2].Branch}
+	want := []string{"merge", "ci", "no-pr"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected grouped order %v, got %v", want, order)
+	}
+
+	view := renderSelector(status, 0, 0, nil, nil, "")
+	mergeIdx := strings.Index(view, "Can merge")
+	noPRIdx := strings.Index(view, "No PR")
+	if mergeIdx == -1 || noPRIdx == -1 || mergeIdx > noPRIdx {
+		t.Fatalf("expected group headers in bucket order, got %q", view)
+	}
+}
+
+func TestRenderSelectorFitsBranchLabelsToWidth(t *testing.T) {
+	long := "feature/" + strings.Repeat("very-long-branch-name-", 5)
+	status := WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: long, Available: true},
+			{Path: "/wt/2", Branch: "main", Available: true},
+		},
+	}
+	for _, width := range []int{0, 80, 120, 200} {
+		view := renderSelector(status, 0, width, nil, nil, "")
+		if strings.Contains(view, long) {
+			t.Fatalf("width %d: expected long branch to be ellipsized, got %q", width, view)
+		}
+		lines := strings.Split(strings.TrimRight(view, "\n"), "\n")
+		for _, line := range lines[1:] {
+			if len(line) != len(lines[0]) {
+				t.Fatalf("width %d: expected aligned columns, got %q", width, view)
+			}
+		}
+	}
+	if narrow, wide := renderSelector(status, 0, 80, nil, nil, ""), renderSelector(status, 0, 200, nil, nil, ""); len(narrow) >= len(wide) {
+		t.Fatalf("expected wider terminal to show more of the branch, narrow=%q wide=%q", narrow, wide)
+	}
+}
+
+func TestListModeSpaceMarksAvailableWorktrees(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.status = WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/a", Available: true, LastUsedUnix: 2},
+			{Path: "/wt/2", Branch: "feature/b", Available: false, LastUsedUnix: 1},
+		},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	updated := updatedModel.(model)
+	if !updated.listMarked["/wt/1"] {
+		t.Fatalf("expected available worktree to be marked")
+	}
+	updated.listIndex = 1
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	updated = updatedModel.(model)
+	if updated.listMarked["/wt/2"] {
+		t.Fatalf("expected in-use worktree to stay unmarked")
+	}
+
+	targets := markedWorktrees(updated.status, updated.listMarked)
+	if len(targets) != 1 || targets[0].Branch != "feature/a" {
+		t.Fatalf("expected only feature/a as target, got %+v", targets)
+	}
+	if view := renderSelector(updated.status, 0, 0, nil, updated.listMarked, ""); !strings.Contains(view, "[x] feature/a") {
+		t.Fatalf("expected marked row in selector, got %q", view)
+	}
+}
+
+func TestRenderSelectorMarksOwnLocks(t *testing.T) {
+	status := WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/mine", LockedByMe: true, LastUsedUnix: 2},
+			{Path: "/wt/2", Branch: "feature/theirs", LastUsedUnix: 1},
+		},
+	}
+	view := renderSelector(status, 0, 200, nil, nil, "")
+	if !strings.Contains(view, "feature/mine (yours)") || !strings.Contains(view, "feature/theirs (in use)") {
+		t.Fatalf("expected yours and in-use markers, got %q", view)
+	}
+}
+
+func TestListModeShiftPCopiesPRURL(t *testing.T) {
+	oldCopy := copyToClipboardFn
+	t.Cleanup(func() { copyToClipboardFn = oldCopy })
+	copied := ""
+	copyToClipboardFn = func(s string) { copied = s }
+
+	m := newModel()
+	m.mode = modeList
+	m.status = WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/a", Available: true, PRURL: "https://github.com/o/r/pull/1", LastUsedUnix: 2},
+			{Path: "/wt/2", Branch: "feature/b", Available: true, LastUsedUnix: 1},
+		},
+	}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	updated := updatedModel.(model)
+	if copied != "https://github.com/o/r/pull/1" || cmd == nil {
+		t.Fatalf("expected PR URL copied with a clear timer, got %q", copied)
+	}
+	cleared, _ := updated.Update(clearNoticeMsg{text: updated.warnMsg})
+	if cleared.(model).warnMsg != "" {
+		t.Fatalf("expected notice to clear")
+	}
+
+	updated.listIndex = 1
+	updatedModel, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if got := updatedModel.(model).errMsg; got != "No PR URL for selected worktree." {
+		t.Fatalf("expected missing PR error, got %q", got)
+	}
+}
+
+func TestCanSkipDeleteConfirm_OnlyForCleanNonLastWorktrees(t *testing.T) {
+	repo := initTestRepo(t)
+	wt1 := addTestWorktree(t, repo, 1, "feature/a")
+	wt2 := addTestWorktree(t, repo, 2, "feature/b")
+	writeTestFile(t, filepath.Join(wt2, "scratch.txt"), "wip")
+
+	m := newModel()
+	m.status = WorktreeStatus{
+		InRepo:    true,
+		RepoRoot:  repo,
+		Worktrees: []WorktreeInfo{{Path: repo}, {Path: wt1}, {Path: wt2}},
+	}
+	if m.canSkipDeleteConfirm(wt1, "") {
+		t.Fatalf("expected confirmation by default")
+	}
+	m.confirmDeleteClean = false
+	if !m.canSkipDeleteConfirm(wt1, "") {
+		t.Fatalf("expected clean worktree to skip confirmation")
+	}
+	if m.canSkipDeleteConfirm(wt1, "\nWarning: 1 commit(s) not on main.") {
+		t.Fatalf("expected unpushed commits to still confirm")
+	}
+	if m.canSkipDeleteConfirm(wt2, "") {
+		t.Fatalf("expected unclean worktree to still confirm")
+	}
+	m.status.Worktrees = []WorktreeInfo{{Path: repo}, {Path: wt1}}
+	if m.canSkipDeleteConfirm(wt1, "") {
+		t.Fatalf("expected last managed worktree to still confirm")
+	}
+}
+
+func TestListModeEEditsWorktreeNote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	m := newModel()
+	m.mode = modeList
+	m.status = WorktreeStatus{
+		InRepo:    true,
+		RepoRoot:  repo,
+		Worktrees: []WorktreeInfo{{Path: repo + ".wt/wt.1", Branch: "feature/a", Available: true}},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	for _, r := range "qa" {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(model)
+	if updated.mode != modeList || updated.status.Worktrees[0].Note != "qa" {
+		t.Fatalf("expected note saved and list restored, got mode=%v note=%q", updated.mode, updated.status.Worktrees[0].Note)
+	}
+	if view := renderSelector(updated.status, 0, 200, nil, nil, ""); !strings.Contains(view, "qa") {
+		t.Fatalf("expected note column in selector, got %q", view)
+	}
+}
+
+func TestListModeNJumpsToNewBranchInput(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.status = WorktreeStatus{
+		InRepo:    true,
+		Worktrees: []WorktreeInfo{{Path: "/wt/1", Branch: "feature/a", Available: true}},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	updated := updatedModel.(model)
+	if updated.mode != modeBranchName || !updated.actionCreate {
+		t.Fatalf("expected new-branch input for a new worktree, got mode=%v create=%v", updated.mode, updated.actionCreate)
+	}
+	if !updated.newBranchInput.Focused() {
+		t.Fatalf("expected branch input to be focused")
+	}
+}
+
+func TestStartConfirmSkipsDestructiveFormsWithYes(t *testing.T) {
+	oldYes := assumeYes
+	assumeYes = true
+	t.Cleanup(func() { assumeYes = oldYes })
+
+	m := newModel()
+	m.mode = modeOpen
+	m.openPickConfirmPath = "/tmp/wt.1"
+	m.confirmKind = confirmOpenDebugArchive
+	m.confirmForm = newConfirmForm("Archive?", "", &m.confirmResult)
+
+	updatedModel, cmd := m.startConfirm()
+	updated := updatedModel.(model)
+	if updated.confirmForm != nil || updated.confirmKind != confirmNone {
+		t.Fatalf("expected confirmation to be accepted without a form")
+	}
+	if cmd == nil {
+		t.Fatalf("expected archive command after auto-confirm")
+	}
+
+	m.confirmKind = confirmOpenBaseDefault
+	m.confirmForm = newConfirmForm("Save?", "", &m.confirmResult)
+	updatedModel, _ = m.startConfirm()
+	if updatedModel.(model).confirmForm == nil {
+		t.Fatalf("expected non-destructive prompts to still show")
+	}
+}
+
+func TestCompactLayout_SwitchesOnShortTerminals(t *testing.T) {
+	m := model{status: WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Branch: "feature/a", Available: true, HasPR: true, PRNumber: 12, PRStatus: "open"},
+			{Path: "/wt/2", Branch: "main", Available: true},
+		},
+	}}
+	if m.compactLayout() {
+		t.Fatalf("expected full layout before the terminal size is known")
+	}
+	m.height = 40
+	if m.compactLayout() {
+		t.Fatalf("expected full layout on a tall terminal")
+	}
+	m.height = 8
+	if !m.compactLayout() {
+		t.Fatalf("expected compact layout on a short terminal")
+	}
+	m.height = 40
+	m.compactSelector = true
+	if !m.compactLayout() {
+		t.Fatalf("expected compact_selector to force the compact layout")
+	}
+
+	view := renderCompactSelector(m.status, 0, 80, nil, nil, "")
+	lines := strings.Split(strings.TrimRight(view, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per row without a header, got %q", view)
+	}
+	if !strings.Contains(view, "feature/a") || !strings.Contains(view, "#12") {
+		t.Fatalf("expected branch and PR number in compact view, got %q", view)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "feature/a") && !strings.Contains(line, "#12") {
+			t.Fatalf("expected branch and PR number on one line, got %q", line)
+		}
+	}
+}
+
+func TestWorktreeSummary_CountsByState(t *testing.T) {
+	status := WorktreeStatus{
+		InRepo: true,
+		Worktrees: []WorktreeInfo{
+			{Path: "/wt/1", Available: true, HasPR: true, PRStatus: "open"},
+			{Path: "/wt/2", Available: true, HasPR: true, PRStatus: "merged"},
+			{Path: "/wt/3", HasPR: true, PRStatus: "awaiting-ci"},
+			{Path: "/wt/4"},
+			{Path: "/wt/5", HasPR: true, PRStatus: "draft"},
+		},
+		Orphaned: []WorktreeInfo{{Path: "/wt/5"}},
+	}
+	title, detail := worktreeSummary(status)
+	if title != "5 worktrees" {
+		t.Fatalf("unexpected title %q", title)
+	}
+	if want := "2 free, 2 in use, 1 orphaned — 3 PRs open"; detail != want {
+		t.Fatalf("expected %q, got %q", want, detail)
+	}
+
+	title, detail = worktreeSummary(WorktreeStatus{InRepo: true, Worktrees: []WorktreeInfo{{Path: "/wt/1"}}})
+	if title != "1 worktree" || detail != "0 free, 1 in use" {
+		t.Fatalf("unexpected single-worktree summary %q %q", title, detail)
+	}
+}
+
+func TestBranchPRDataMsg_MergesOnlyRefreshedBranch(t *testing.T) {
+	m := newModel()
+	m.status = WorktreeStatus{InRepo: true, RepoRoot: "/repo", Worktrees: []WorktreeInfo{
+		{Path: "/wt/a", Branch: "a"},
+		{Path: "/wt/b", Branch: "b"},
+	}}
+	m.ghDataByBranch = map[string]PRData{
+		"a": {Number: 1, URL: "https://github.com/o/r/pull/1"},
+		"b": {Number: 2, URL: "https://github.com/o/r/pull/2"},
+	}
+	m.ghPendingByBranch = map[string]bool{"b": true}
+
+	updatedModel, _ := m.Update(branchPRDataMsg{repoRoot: "/repo", branch: "b", byBranch: map[string]PRData{
+		"b": {Number: 3, URL: "https://github.com/o/r/pull/3"},
+	}})
+	updated := updatedModel.(model)
+	if updated.ghPendingByBranch["b"] {
+		t.Fatalf("expected b to stop pending")
+	}
+	if updated.ghDataByBranch["a"].Number != 1 || updated.ghDataByBranch["b"].Number != 3 {
+		t.Fatalf("expected only b to change, got %+v", updated.ghDataByBranch)
+	}
+	if updated.status.Worktrees[1].PRNumber != 3 {
+		t.Fatalf("expected refreshed PR applied to the row, got %+v", updated.status.Worktrees[1])
+	}
+}
+
+func TestStartCreateTarget_ConfirmsSummaryWhenConfigured(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	m := newModel()
+	m.confirmBeforeCreate = true
+	m.openTargetIsNew = true
+	m.openTargetBranch = "feature/x"
+	m.openTargetBaseRef = "origin/main"
+	m.openTargetFetch = true
+
+	updatedModel, _ := m.startCreateTarget(nil)
+	updated := updatedModel.(model)
+	if updated.confirmKind != confirmCreateWorktree || updated.openCreating {
+		t.Fatalf("expected create confirm before creating, got kind=%v creating=%v", updated.confirmKind, updated.openCreating)
+	}
+	summary := updated.createTargetSummary()
+	if !strings.Contains(summary, "Create branch feature/x from origin/main") || !strings.Contains(summary, "Fetch first: yes") {
+		t.Fatalf("unexpected summary %q", summary)
+	}
+}
+
+func TestLinkedRepoRows_GroupedByRepoAndReadOnly(t *testing.T) {
+	m := newModel()
+	m.mode = modeList
+	m.status = WorktreeStatus{
+		InRepo:    true,
+		RepoRoot:  "/code/app",
+		Worktrees: []WorktreeInfo{{Path: "/code/app.wt/wt.1", Branch: "feature/app", Available: true}},
+		Linked: []WorktreeInfo{
+			{Path: "/code/lib.wt/wt.1", Branch: "feature/app", Available: true, LastUsedUnix: 99, LinkedRepo: "/code/lib"},
+		},
+	}
+	applyPRDataToStatus(&m.status, map[string]PRData{
+		"feature/app":              {Number: 1},
+		"/code/lib\x00feature/app": {Number: 2},
+	})
+
+	rows := selectorRows(m.status, nil, nil, "")
+	if len(rows) != 3 || rows[0].Group != "app" || rows[1].Group != "lib" {
+		t.Fatalf("expected app then lib groups, got %+v", rows)
+	}
+	shown := worktreesForDisplay(m.status)
+	if shown[0].PRNumber != 1 || shown[1].PRNumber != 2 {
+		t.Fatalf("expected PR data keyed per repo, got %+v", shown)
+	}
+
+	m.listIndex = 1
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if got := updatedModel.(model).errMsg; !strings.Contains(got, "run wtx there") {
+		t.Fatalf("expected delete refused on linked row, got %q", got)
+	}
+}
+
+func TestListModeTLabelsTabTitle(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("WTX_DISABLE_ITERM", "1")
+	t.Cleanup(func() { setTabTitleLabel("") })
+	m := newModel()
+	m.mode = modeList
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if updatedModel.(model).mode != modeTitleLabel {
+		t.Fatalf("expected title label prompt")
+	}
+	for _, r := range "review\x1b" {
+		updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	updatedModel, _ = updatedModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if updatedModel.(model).mode != modeList {
+		t.Fatalf("expected list restored")
+	}
+	if got := withTabTitleLabel("wtx - main"); got != "wtx - main - review" {
+		t.Fatalf("expected labeled title, got %q", got)
+	}
+}
+
+func TestOpenMarkedWorktreesCmd_MissingAgentLocksNothing(t *testing.T) {
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	if err := SaveConfig(Config{AgentCommand: "missing-agent --flag"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	prev := lookPathFn
+	t.Cleanup(func() { lookPathFn = prev })
+	lookPathFn = func(string) (string, error) { return "", errors.New("not found") }
+
+	// A nil manager would panic if the command got as far as locking.
+	msg := openMarkedWorktreesCmd(nil, nil, []WorktreeInfo{
+		{Path: t.TempDir(), Branch: "feature/a"},
+		{Path: t.TempDir(), Branch: "feature/b"},
+	})()
+	done, ok := msg.(listMultiOpenDoneMsg)
+	if !ok {
+		t.Fatalf("expected listMultiOpenDoneMsg, got %T", msg)
+	}
+	if len(done.started) != 0 || len(done.failed) != 1 || !strings.Contains(done.failed[0], "'missing-agent' not found in PATH") {
+		t.Fatalf("expected one missing-agent failure, got %+v", done)
+	}
+}
+
+func TestConfirmResetRemote_RunsResetOffTheUILoop(t *testing.T) {
+	m := model{
+		confirmKind:   confirmResetRemote,
+		confirmResult: true,
+		resetPath:     "/tmp/wt",
+		resetBranch:   "feature/a",
+	}
+	updated, cmd := m.handleConfirmDone()
+	if cmd == nil {
+		t.Fatalf("expected the reset to run as a command")
+	}
+	if got := updated.(model).warnMsg; !strings.Contains(got, "Resetting feature/a") {
+		t.Fatalf("expected a progress message, got %q", got)
+	}
+	done, ok := cmd().(resetToRemoteDoneMsg)
+	if !ok || done.branch != "feature/a" || done.err == nil {
+		t.Fatalf("expected a reset done message with the missing-manager error, got %+v", done)
+	}
+}
+
+func TestNewBranchBaseRef_ListModeAppliesRules(t *testing.T) {
+	m := model{
+		status:             WorktreeStatus{BaseRef: "origin/main", HasRemote: true},
+		openDefaultBaseRef: "origin/develop",
+		baseRefRules:       []BaseRefRule{{Pattern: "hotfix/*", BaseRef: "origin/main"}},
+	}
+	if got := m.newBranchBaseRef("hotfix/x"); got != "origin/main" {
+		t.Fatalf("expected the hotfix rule's base, got %q", got)
+	}
+	if got := m.newBranchBaseRef("feature/x"); got != "origin/develop" {
+		t.Fatalf("expected the default base, got %q", got)
+	}
+}
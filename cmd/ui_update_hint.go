@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,6 +15,9 @@ type interactiveUpdateHintMsg struct {
 }
 
 func checkInteractiveUpdateHintCmd() tea.Cmd {
+	if automaticUpdateCheckDisabled(os.Args) {
+		return nil
+	}
 	return func() tea.Msg {
 		cur := strings.TrimSpace(currentVersion())
 		ctx, cancel := context.WithTimeout(context.Background(), startupUpdateTimeout)
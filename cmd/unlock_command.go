@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type unlockOptions struct {
+	All   bool
+	Repo  string
+	Force bool
+}
+
+func newUnlockCommand() *cobra.Command {
+	var opts unlockOptions
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Release stale worktree locks owned by you",
+		Long: "Scans ~/.wtx/locks and removes locks owned by the current user whose process is no longer running.\n\n" +
+			"Use --force to also remove locks held by live processes; this asks for confirmation unless --yes is set.",
+		Example: strings.Join([]string{
+			"  wtx unlock --all",
+			"  wtx unlock --repo .",
+			"  wtx unlock --all --force --yes",
+		}, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !opts.All && strings.TrimSpace(opts.Repo) == "" {
+				return usageError(cmd, "specify --all or --repo")
+			}
+			return runUnlock(os.Stdin, os.Stdout, opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Scan locks across all repositories")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Only release locks for this repository path")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Also remove locks held by live processes")
+	return cmd
+}
+
+func runUnlock(r io.Reader, w io.Writer, opts unlockOptions) error {
+	repoFilter := ""
+	if repo := strings.TrimSpace(opts.Repo); repo != "" {
+		repoRoot, err := repoRootForDir(repo, "")
+		if err != nil {
+			return err
+		}
+		repoFilter, err = realPathOrAbs(repoRoot)
+		if err != nil {
+			return err
+		}
+	}
+	locks, err := listLockFiles()
+	if err != nil {
+		return err
+	}
+	stale := make([]lockFileEntry, 0, len(locks))
+	live := make([]lockFileEntry, 0)
+	for _, lock := range locks {
+		if shouldReleaseLock(lock, repoFilter, false) {
+			stale = append(stale, lock)
+		} else if opts.Force && shouldReleaseLock(lock, repoFilter, true) {
+			live = append(live, lock)
+		}
+	}
+	if len(live) > 0 {
+		ok, err := promptYesNo(r, w, fmt.Sprintf("Remove %d lock(s) held by running processes?", len(live)))
+		if err != nil {
+			return err
+		}
+		if ok {
+			stale = append(stale, live...)
+		}
+	}
+	removed := 0
+	for _, lock := range stale {
+		if err := os.Remove(lock.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		removed++
+		fmt.Fprintln(w, describeReleasedLock(lock))
+	}
+	if removed == 0 {
+		fmt.Fprintln(w, "No locks to release.")
+	}
+	return nil
+}
+
+func shouldReleaseLock(lock lockFileEntry, repoFilter string, force bool) bool {
+	if lock.Err != nil {
+		// Unreadable payloads are leftovers from interrupted writes.
+		return repoFilter == "" && force
+	}
+	if repoFilter != "" {
+		lockRepo, err := realPathOrAbs(lock.Payload.RepoRoot)
+		if err != nil || lockRepo != repoFilter {
+			return false
+		}
+	}
+	if !lockOwnerIsCurrentUser(lock.Payload.OwnerID) {
+		return false
+	}
+	if force {
+		return true
+	}
+	return !lockOwnerStillActive(lock.Payload.OwnerID, lock.Payload.PID)
+}
+
+func describeReleasedLock(lock lockFileEntry) string {
+	worktree := strings.TrimSpace(lock.Payload.WorktreePath)
+	if worktree == "" {
+		return "Removed lock " + lock.Path
+	}
+	branch := "-"
+	if _, err := os.Stat(worktree); err == nil {
+		if b := currentBranchInWorktree(worktree); b != "" {
+			branch = b
+		}
+	}
+	return fmt.Sprintf("Removed lock for %s (%s)", worktree, branch)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestLock(t *testing.T, name string, payload map[string]any) string {
+	t.Helper()
+	dir, err := locksDir()
+	if err != nil {
+		t.Fatalf("locksDir: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	return path
+}
+
+func TestRunUnlockRemovesOnlyStaleLocks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	owner := "explicit:unlock-test"
+	stale := writeTestLock(t, "stale.lock", map[string]any{
+		"owner_id":      owner,
+		"pid":           999999,
+		"worktree_path": "/tmp/wtx-missing-a",
+	})
+	live := writeTestLock(t, "live.lock", map[string]any{
+		"owner_id":      owner,
+		"pid":           os.Getpid(),
+		"worktree_path": "/tmp/wtx-missing-b",
+	})
+	foreign := writeTestLock(t, "foreign.lock", map[string]any{
+		"owner_id":      "someone@elsewhere:1:abc",
+		"pid":           999999,
+		"worktree_path": "/tmp/wtx-missing-c",
+	})
+
+	var out bytes.Buffer
+	if err := runUnlock(strings.NewReader(""), &out, unlockOptions{All: true}); err != nil {
+		t.Fatalf("runUnlock: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale lock removed, stat err=%v", err)
+	}
+	if _, err := os.Stat(live); err != nil {
+		t.Fatalf("expected live lock kept: %v", err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Fatalf("expected foreign lock kept: %v", err)
+	}
+	if !strings.Contains(out.String(), "/tmp/wtx-missing-a") {
+		t.Fatalf("expected removed worktree in output, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := runUnlock(strings.NewReader("n\n"), &out, unlockOptions{All: true, Force: true}); err != nil {
+		t.Fatalf("runUnlock force declined: %v", err)
+	}
+	if _, err := os.Stat(live); err != nil {
+		t.Fatalf("expected live lock kept when force is declined: %v", err)
+	}
+
+	out.Reset()
+	if err := runUnlock(strings.NewReader("y\n"), &out, unlockOptions{All: true, Force: true}); err != nil {
+		t.Fatalf("runUnlock force: %v", err)
+	}
+	if _, err := os.Stat(live); !os.IsNotExist(err) {
+		t.Fatalf("expected live lock removed with force, stat err=%v", err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Fatalf("expected foreign lock kept with force: %v", err)
+	}
+}
+
+func TestRunUnlockForceWithYesSkipsPrompt(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	oldYes := assumeYes
+	assumeYes = true
+	t.Cleanup(func() { assumeYes = oldYes })
+
+	live := writeTestLock(t, "live.lock", map[string]any{
+		"owner_id":      "explicit:unlock-test",
+		"pid":           os.Getpid(),
+		"worktree_path": "/tmp/wtx-missing-b",
+	})
+	var out bytes.Buffer
+	if err := runUnlock(strings.NewReader(""), &out, unlockOptions{All: true, Force: true}); err != nil {
+		t.Fatalf("runUnlock: %v", err)
+	}
+	if _, err := os.Stat(live); !os.IsNotExist(err) {
+		t.Fatalf("expected live lock removed with --yes, stat err=%v", err)
+	}
+	if strings.Contains(out.String(), "[y/N]") {
+		t.Fatalf("expected no prompt with --yes, got %q", out.String())
+	}
+}
@@ -31,10 +31,12 @@ const (
 	wtxUpdateCommandFormat = "wtx %s -> %s available. Run: wtx update"
 	releaseArchiveFormat   = "wtx_%s_%s.tar.gz"
 	releaseDownloadFormat  = "https://github.com/%s/releases/download/%s/%s"
+	latestReleaseAPIFormat = "https://api.github.com/repos/%s/releases/latest"
 )
 
 var releaseVersionPattern = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)$`)
 var resolveLatestVersionFn = resolveLatestVersion
+var latestReleaseURL = fmt.Sprintf(latestReleaseAPIFormat, updateRepoPath)
 
 type parsedVersion struct {
 	Major int
@@ -124,8 +126,32 @@ func printUpdateCheckResultTo(w io.Writer, result updateCheckResult, quiet bool)
 	fmt.Fprintf(w, "wtx is up to date (%s)\n", result.CurrentVersion)
 }
 
+// noUpdateCheck is bound to --no-update-check.
+var noUpdateCheck bool
+
+const noUpdateCheckFlag = "--no-update-check"
+
+// automaticUpdateCheckDisabled reports whether --no-update-check or
+// disable_update_check turns off background update checks. args is scanned
+// directly because the invocation check starts before flags are parsed.
+func automaticUpdateCheckDisabled(args []string) bool {
+	if noUpdateCheck {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == noUpdateCheckFlag {
+			return true
+		}
+	}
+	cfg, err := LoadConfig()
+	return err == nil && cfg.DisableUpdateCheck
+}
+
 func maybeStartInvocationUpdateCheck(args []string) {
-	if !shouldRunInvocationUpdateCheck(args) {
+	if !shouldRunInvocationUpdateCheck(args) || automaticUpdateCheckDisabled(args) {
 		return
 	}
 	go func() {
@@ -205,8 +231,45 @@ func shouldCheckForUpdates(lastCheckedUnix int64, now time.Time, interval time.D
 	return now.Sub(lastChecked) >= interval
 }
 
+// resolveLatestVersion prefers the latest published GitHub release, so tags
+// without a release (or drafts and prereleases) never trigger a notice. If the
+// API is unreachable or rate limited it falls back to the highest semver tag.
 func resolveLatestVersion(ctx context.Context) (string, error) {
-	output, err := runCommand(ctx, "git", []string{"ls-remote", "--tags", "--refs", updateRepoGitURL}, nil)
+	if latest, err := fetchLatestReleaseTag(ctx, latestReleaseURL); err == nil {
+		return latest, nil
+	}
+	return resolveLatestTagVersion(ctx)
+}
+
+func fetchLatestReleaseTag(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return "", err
+	}
+	tag := strings.TrimSpace(release.TagName)
+	if !isReleaseVersion(tag) {
+		return "", fmt.Errorf("latest release tag %q is not a semver version", tag)
+	}
+	return tag, nil
+}
+
+func resolveLatestTagVersion(ctx context.Context) (string, error) {
+	output, err := runCommand(ctx, gitBinary(), []string{"ls-remote", "--tags", "--refs", updateRepoGitURL}, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve latest version: %w", err)
 	}
@@ -590,9 +653,9 @@ func writeUpdateState(state updateState) error {
 }
 
 func updateStatePath() (string, error) {
-	home := strings.TrimSpace(os.Getenv("HOME"))
-	if home == "" {
-		return "", errors.New("HOME not set")
+	home, err := wtxHomeDir()
+	if err != nil {
+		return "", err
 	}
-	return filepath.Join(home, ".wtx", updateStateFileName), nil
+	return filepath.Join(home, updateStateFileName), nil
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
 	"strings"
@@ -229,3 +231,61 @@ func TestChecksumLineForFile(t *testing.T) {
 		t.Fatalf("unexpected line: %q", line)
 	}
 }
+
+func TestAutomaticUpdateCheckDisabled(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(configDirOverrideEnv, dir)
+
+	if automaticUpdateCheckDisabled([]string{"wtx"}) {
+		t.Fatalf("expected update check enabled by default")
+	}
+	if !automaticUpdateCheckDisabled([]string{"wtx", "--no-update-check"}) {
+		t.Fatalf("expected --no-update-check to disable the check")
+	}
+	if automaticUpdateCheckDisabled([]string{"wtx", "--", "--no-update-check"}) {
+		t.Fatalf("expected args after -- to be ignored")
+	}
+	if err := SaveConfig(Config{DisableUpdateCheck: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if !automaticUpdateCheckDisabled([]string{"wtx"}) {
+		t.Fatalf("expected disable_update_check to disable the check")
+	}
+}
+
+func TestFetchLatestReleaseTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "release", status: http.StatusOK, body: `{"tag_name":"v1.4.2"}`, want: "v1.4.2"},
+		{name: "non semver tag", status: http.StatusOK, body: `{"tag_name":"nightly"}`, wantErr: true},
+		{name: "rate limited", status: http.StatusForbidden, body: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			got, err := fetchLatestReleaseTag(context.Background(), srv.URL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// worktreeEnvFileName holds per-worktree variables for the agent, in a simple
+// dotenv format.
+const worktreeEnvFileName = ".wtx.env"
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// loadWorktreeEnv reads <worktree>/.wtx.env as KEY=value lines. Blank lines,
+// # comments and a leading "export " are allowed; values may be quoted. Bad
+// lines are skipped and reported together in the error.
+func loadWorktreeEnv(worktreePath string) ([]string, error) {
+	path := filepath.Join(worktreePath, worktreeEnvFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var env []string
+	var errs []error
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, err := parseEnvLine(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", worktreeEnvFileName, n, err))
+			continue
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, errors.Join(errs...)
+}
+
+func parseEnvLine(line string) (string, string, error) {
+	line = strings.TrimPrefix(line, "export ")
+	key, value, ok := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	if !ok {
+		return "", "", fmt.Errorf("expected KEY=value, got %q", line)
+	}
+	if !envKeyPattern.MatchString(key) {
+		return "", "", fmt.Errorf("invalid variable name %q", key)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return key, "", nil
+	}
+	switch quote := value[0]; quote {
+	case '\'', '"':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", "", fmt.Errorf("unterminated quote in %s", key)
+		}
+		value = value[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return key, value, nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return key, value, nil
+}
+
+// withAgentEnv exports env in front of runCmd. The agent command runs through
+// a shell in every launch path, tmux panes included, so the exports land after
+// the WTX_* variables wtx sets on the session.
+func withAgentEnv(runCmd string, env []string) string {
+	if len(env) == 0 {
+		return runCmd
+	}
+	assignments := make([]string, 0, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		assignments = append(assignments, key+"="+shellQuote(value))
+	}
+	return "export " + strings.Join(assignments, " ") + "; " + runCmd
+}
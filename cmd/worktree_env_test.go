@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadWorktreeEnv_ParsesDotenvAndReportsBadLines(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, worktreeEnvFileName), strings.Join([]string{
+		"# agent settings",
+		"PORT=3001 # dev server",
+		"export API_URL=http://localhost:3001",
+		`GREETING="it's \"here\""`,
+		"RAW='$HOME stays'",
+		"not a pair",
+		"1BAD=x",
+	}, "\n"))
+
+	env, err := loadWorktreeEnv(dir)
+	if err == nil || !strings.Contains(err.Error(), ".wtx.env:6") || !strings.Contains(err.Error(), ".wtx.env:7") {
+		t.Fatalf("expected errors for lines 6 and 7, got %v", err)
+	}
+	want := []string{"PORT=3001", "API_URL=http://localhost:3001", `GREETING=it's "here"`, "RAW=$HOME stays"}
+	if strings.Join(env, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected env\n got: %q\nwant: %q", env, want)
+	}
+
+	out, runErr := exec.Command("/bin/sh", "-c", withAgentEnv(`printf '%s|%s' "$GREETING" "$RAW"`, env)).Output()
+	if runErr != nil {
+		t.Fatalf("sh: %v", runErr)
+	}
+	if string(out) != `it's "here"|$HOME stays` {
+		t.Fatalf("expected values to reach the agent verbatim, got %q", out)
+	}
+}
+
+func TestLoadWorktreeEnv_MissingFileIsEmpty(t *testing.T) {
+	env, err := loadWorktreeEnv(t.TempDir())
+	if err != nil || env != nil {
+		t.Fatalf("expected no env and no error, got %q %v", env, err)
+	}
+	if got := withAgentEnv("claude", nil); got != "claude" {
+		t.Fatalf("expected command unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const worktreeLogLimit = 20
+
+type worktreeLogMsg struct {
+	path string
+	log  string
+	err  error
+}
+
+// loadWorktreeLogCmd reads the last worktreeLogLimit commits of the worktree's
+// HEAD. An unborn branch yields an empty log rather than git's error.
+func loadWorktreeLogCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		gitPath := gitBinary()
+		if _, err := gitOutputInDir(path, gitPath, "rev-parse", "--verify", "-q", "HEAD"); err != nil {
+			return worktreeLogMsg{path: path}
+		}
+		out, err := gitOutputInDir(path, gitPath, "log", "--oneline", "--no-decorate", fmt.Sprintf("-%d", worktreeLogLimit))
+		return worktreeLogMsg{path: path, log: out, err: err}
+	}
+}
+
+// startWorktreeLog switches to the log pane for row and loads its commits.
+func (m model) startWorktreeLog(row WorktreeInfo) (tea.Model, tea.Cmd) {
+	m.mode = modeLog
+	m.logPath = row.Path
+	m.logBranch = worktreeBranchLabel(row)
+	m.logViewport = viewport.New(m.width, m.logViewportHeight())
+	m.logViewport.SetContent(secondaryStyle.Render("Loading commits..."))
+	m.errMsg = ""
+	return m, loadWorktreeLogCmd(row.Path)
+}
+
+// logViewportHeight leaves room for the title and the key hint.
+func (m model) logViewportHeight() int {
+	if m.height <= 0 {
+		return worktreeLogLimit
+	}
+	return max(m.height-4, 3)
+}
+
+func (m model) updateWorktreeLog(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case worktreeLogMsg:
+		if m.mode != modeLog || msg.path != m.logPath {
+			return m, nil
+		}
+		switch {
+		case msg.err != nil:
+			m.logViewport.SetContent(errorStyle.Render(msg.err.Error()))
+		case strings.TrimSpace(msg.log) == "":
+			m.logViewport.SetContent(secondaryStyle.Render("No commits yet."))
+		default:
+			m.logViewport.SetContent(msg.log)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			m.mode = modeList
+			m.logPath = ""
+			m.logBranch = ""
+			return m, nil
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.logViewport, cmd = m.logViewport.Update(msg)
+	return m, cmd
+}
+
+func (m model) renderWorktreeLog() string {
+	var b strings.Builder
+	b.WriteString("Recent commits on " + branchInlineStyle.Render(m.logBranch) + ":\n")
+	b.WriteString(m.logViewport.View())
+	b.WriteString("\n\n")
+	b.WriteString(secondaryStyle.Render("Press up/down or pgup/pgdn to scroll, q or esc to go back."))
+	b.WriteString("\n")
+	return b.String()
+}
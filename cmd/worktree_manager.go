@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 )
 
 type WorktreeManager struct {
@@ -40,6 +44,9 @@ func (m *WorktreeManager) ListForStatusBase() WorktreeStatus {
 	gitPath, err := gitPath()
 	if err != nil {
 		status.GitInstalled = false
+		if configuredGitPath() != "" {
+			status.Err = err
+		}
 		return status
 	}
 	status.GitInstalled = true
@@ -51,8 +58,13 @@ func (m *WorktreeManager) ListForStatusBase() WorktreeStatus {
 	}
 	status.InRepo = true
 	status.RepoRoot = repoRoot
+	if primary, name, ok := nestedWorktreeInfo(repoRoot, gitPath); ok {
+		status.PrimaryRoot = primary
+		status.NestedWorktree = name
+	}
 	status.HasRemote = strings.TrimSpace(preferredRemoteName(repoRoot, gitPath)) != ""
 	status.BaseRef = m.ResolveBaseRefForNewBranch()
+	status.BaseRemote = m.cachedRemote(repoRoot)
 
 	worktrees, malformed, err := listWorktrees(repoRoot, gitPath)
 	if err != nil {
@@ -75,7 +87,7 @@ func (m *WorktreeManager) ResolveBaseRefForNewBranch() string {
 	}
 	remote := m.cachedRemote(repoRoot)
 	if remote == "" {
-		remote = preferredRemoteName(repoRoot, gitPath)
+		remote = baseRemoteName(repoRoot, gitPath)
 		m.setCachedRemote(repoRoot, remote)
 	}
 	fallbackBranch := fallbackBaseBranchNoRemote(repoRoot, gitPath)
@@ -114,13 +126,52 @@ func (m *WorktreeManager) CreateWorktree(branch string, baseRef string) (Worktre
 	defer lock.Release()
 
 	baseRef = baseRefForWorktreeAdd(repoRoot, gitPath, baseRef)
-	if err := runCommandInDir(layoutRoot, gitPath, "worktree", "add", "-b", branch, target, baseRef); err != nil {
+	if err := runWorktreeAdd(layoutRoot, gitPath, target, "-b", branch, target, baseRef); err != nil {
+		if errors.Is(err, errWorktreeAddTimeout) {
+			_ = runCommandInDir(repoRoot, gitPath, "branch", "-D", branch)
+		}
 		return WorktreeInfo{}, err
 	}
+	if err := runPostCreateSteps(repoRoot, target, branch); err != nil {
+		return WorktreeInfo{Path: target, Branch: branch}, err
+	}
 
 	return WorktreeInfo{Path: target, Branch: branch}, nil
 }
 
+// WorktreeAddCommand returns the git command wtx would run to add a worktree
+// for branch: a plain checkout when the branch exists locally, otherwise -b
+// from baseRef. The path is relative to the repository so the command can be
+// shared and run from another clone's root.
+func (m *WorktreeManager) WorktreeAddCommand(branch string, baseRef string) (string, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return "", errors.New("branch name required")
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return "", err
+	}
+	layoutRoot := worktreeLayoutRoot(repoRoot, gitPath)
+	target, err := nextWorktreePath(layoutRoot)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(layoutRoot, target); err == nil {
+		target = rel
+	}
+	args := []string{"git", "worktree", "add"}
+	if localBranchExists(repoRoot, gitPath, branch) {
+		args = append(args, target, branch)
+	} else {
+		args = append(args, "-b", branch, target, baseRefForWorktreeAdd(repoRoot, gitPath, baseRef))
+	}
+	for i, arg := range args {
+		args[i] = shellWord(arg)
+	}
+	return strings.Join(args, " "), nil
+}
+
 func (m *WorktreeManager) CreateWorktreeFromBranch(branch string) (WorktreeInfo, error) {
 	branch = strings.TrimSpace(branch)
 	if branch == "" {
@@ -143,22 +194,60 @@ func (m *WorktreeManager) CreateWorktreeFromBranch(branch string) (WorktreeInfo,
 	}
 	defer lock.Release()
 
-	if err := runCommandInDir(layoutRoot, gitPath, "worktree", "add", target, branch); err != nil {
+	if err := runWorktreeAdd(layoutRoot, gitPath, target, target, branch); err != nil {
 		return WorktreeInfo{}, err
 	}
+	if err := runPostCreateSteps(repoRoot, target, branch); err != nil {
+		return WorktreeInfo{Path: target, Branch: branch}, err
+	}
 
 	return WorktreeInfo{Path: target, Branch: branch}, nil
 }
 
+// CreateDetachedWorktree adds a throwaway worktree with a detached HEAD at ref
+// (a tag, SHA, or any other commit-ish).
+func (m *WorktreeManager) CreateDetachedWorktree(ref string) (WorktreeInfo, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return WorktreeInfo{}, errors.New("ref required")
+	}
+
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	sha, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil || sha == "" {
+		return WorktreeInfo{}, fmt.Errorf("unknown ref %q", ref)
+	}
+	layoutRoot := worktreeLayoutRoot(repoRoot, gitPath)
+
+	target, err := nextWorktreePath(layoutRoot)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, target)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	defer lock.Release()
+
+	if err := runWorktreeAdd(layoutRoot, gitPath, target, "--detach", target, sha); err != nil {
+		return WorktreeInfo{}, err
+	}
+	created := WorktreeInfo{Path: target, Branch: "detached", HeadSHA: sha}
+	if err := runPostCreateSteps(repoRoot, target, "detached"); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
 func (m *WorktreeManager) ListLocalBranchesByRecentUse() ([]string, error) {
 	gitPath, repoRoot, err := requireGitContext(m.cwd)
 	if err != nil {
 		return nil, err
 	}
-	limit := defaultMainScreenBranchLimit
-	if cfg, cfgErr := LoadConfig(); cfgErr == nil && cfg.MainScreenBranchLimit > 0 {
-		limit = cfg.MainScreenBranchLimit
-	}
+	limit := configuredMainScreenBranchLimit()
 
 	output, err := commandOutputInDir(repoRoot, gitPath, "for-each-ref",
 		"--sort=-committerdate",
@@ -206,6 +295,55 @@ func (m *WorktreeManager) ListAllLocalBranchesByRecentUse() ([]string, error) {
 	return branches, nil
 }
 
+func (m *WorktreeManager) ListRemoteBaseRefs() ([]string, error) {
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return nil, err
+	}
+	output, err := commandOutputInDir(repoRoot, gitPath, "for-each-ref",
+		"--sort=-committerdate",
+		"--format=%(refname:short)%09%(symref:short)",
+		"refs/remotes/")
+	if err != nil {
+		return nil, err
+	}
+	return parseRemoteBaseRefs(string(output)), nil
+}
+
+// parseRemoteBaseRefs lists remote HEAD targets first, followed by the
+// remaining remote branches in the order git reported them.
+func parseRemoteBaseRefs(output string) []string {
+	heads := []string{}
+	others := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, target, _ := strings.Cut(line, "\t")
+		name = strings.TrimSpace(name)
+		target = strings.TrimSpace(target)
+		if target != "" {
+			heads = append(heads, target)
+			continue
+		}
+		if name == "" || !strings.Contains(name, "/") || strings.HasSuffix(name, "/HEAD") {
+			continue
+		}
+		others = append(others, name)
+	}
+	seen := make(map[string]bool, len(heads)+len(others))
+	refs := make([]string, 0, len(heads)+len(others))
+	for _, ref := range append(heads, others...) {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
 func (m *WorktreeManager) DeleteWorktree(path string, force bool) error {
 	path = strings.TrimSpace(path)
 	if path == "" {
@@ -216,6 +354,10 @@ func (m *WorktreeManager) DeleteWorktree(path string, force bool) error {
 	if err != nil {
 		return err
 	}
+	repoRoot, err = deletableRepoRoot(repoRoot, gitPath, path)
+	if err != nil {
+		return err
+	}
 	if err := ensureManagedWorktreePath(repoRoot, path); err != nil {
 		return err
 	}
@@ -234,6 +376,98 @@ func (m *WorktreeManager) DeleteWorktree(path string, force bool) error {
 	if err := runCommandInDir(repoRoot, gitPath, args...); err != nil {
 		return err
 	}
+	_ = clearAdoptedWorktree(repoRoot, path)
+	if cfg, err := loadConfigForDir(repoRoot); err == nil && cfg.RunGCAfterDelete {
+		startRepoMaintenanceFn(repoRoot, gitPath)
+	}
+	return nil
+}
+
+var startRepoMaintenanceFn = startRepoMaintenance
+
+// startRepoMaintenance runs `git maintenance run --auto` (falling back to
+// `git gc --auto` on older git) without waiting; failures are only logged.
+func startRepoMaintenance(repoRoot string, gitPath string) {
+	go func() {
+		err := runMaintenanceCommand(repoRoot, gitPath, "maintenance", "run", "--auto")
+		if err != nil {
+			logDebug("git maintenance failed; trying gc", "repo", repoRoot, "err", err)
+			err = runMaintenanceCommand(repoRoot, gitPath, "gc", "--auto")
+		}
+		if err != nil {
+			logError("post-delete git maintenance failed", "repo", repoRoot, "err", err)
+		}
+	}()
+}
+
+func runMaintenanceCommand(repoRoot string, gitPath string, args ...string) error {
+	cmd := exec.Command(gitPath, args...)
+	cmd.Dir = repoRoot
+	// Own process group so quitting the TUI doesn't interrupt it.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return commandErrorWithOutput(err, out)
+	}
+	return nil
+}
+
+func (m *WorktreeManager) ArchiveWorktree(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("worktree path required")
+	}
+	_, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return "", err
+	}
+	branch := currentBranchInWorktree(path)
+	if branch == "" {
+		return "", errors.New("cannot archive a worktree without a branch checked out")
+	}
+	if err := m.DeleteWorktree(path, false); err != nil {
+		return "", err
+	}
+	if err := recordArchivedBranch(repoRoot, branch, path); err != nil {
+		return branch, fmt.Errorf("worktree removed but failed to record archived branch: %w", err)
+	}
+	return branch, nil
+}
+
+var errWorktreeAddTimeout = errors.New("git worktree add timed out")
+
+func worktreeAddTimeout() time.Duration {
+	seconds := defaultCreateTimeoutSeconds
+	if cfg, err := LoadConfig(); err == nil && cfg.CreateTimeoutSeconds > 0 {
+		seconds = cfg.CreateTimeoutSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func runWorktreeAdd(dir string, gitPath string, target string, args ...string) error {
+	return runWorktreeAddWithTimeout(dir, gitPath, target, worktreeAddTimeout(), args...)
+}
+
+func runWorktreeAddWithTimeout(dir string, gitPath string, target string, timeout time.Duration, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, gitPath, append([]string{"worktree", "add"}, args...)...)
+	cmd.Dir = dir
+	// Run in its own process group so hooks spawned by git die with it.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 2 * time.Second
+	out, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logError("git worktree add timed out", "dir", dir, "target", target, "timeout", timeout)
+		_ = os.RemoveAll(target)
+		_ = runCommandInDir(dir, gitPath, "worktree", "prune")
+		return fmt.Errorf("%w after %s; a slow post-checkout hook may be blocking (raise create_timeout_seconds in wtx config)", errWorktreeAddTimeout, timeout)
+	}
+	if err != nil {
+		return commandErrorWithOutput(err, out)
+	}
 	return nil
 }
 
@@ -245,33 +479,131 @@ func commandErrorWithOutput(err error, out []byte) error {
 	return err
 }
 
+// commandOutputInDir logs failures at debug level: many callers are probes
+// (show-ref --verify, merge-base --is-ancestor) where a non-zero exit is an
+// answer, not an error. Callers that treat failure as an error log it.
 func commandOutputInDir(dir string, path string, args ...string) ([]byte, error) {
+	start := time.Now()
 	cmd := exec.Command(path, args...)
 	cmd.Dir = dir
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, commandErrorWithOutput(err, out)
+		err = commandErrorWithOutput(err, out)
+		logDebug("command failed", "dir", dir, "cmd", path, "args", args, "duration", time.Since(start), "err", err)
+		return nil, err
 	}
+	logDebug("command finished", "dir", dir, "cmd", path, "args", args, "duration", time.Since(start))
 	return out, nil
 }
 
+// runCommandInDir runs a command that changes something, so unlike
+// commandOutputInDir its failures are logged as errors.
 func runCommandInDir(dir string, path string, args ...string) error {
 	_, err := commandOutputInDir(dir, path, args...)
+	if err != nil {
+		logError("command failed", "dir", dir, "cmd", path, "args", args, "err", err)
+	}
 	return err
 }
 
+// RepairWorktrees runs `git worktree repair` from the primary checkout,
+// passing every managed wt.* directory so links are fixed after either side
+// moves. It returns git's output.
+func (m *WorktreeManager) RepairWorktrees() (string, error) {
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return "", err
+	}
+	primary := worktreeLayoutRoot(repoRoot, gitPath)
+	args := []string{"worktree", "repair"}
+	if entries, err := filepath.Glob(filepath.Join(managedWorktreeRoot(primary), "wt.*")); err == nil {
+		for _, entry := range entries {
+			if info, err := os.Stat(entry); err == nil && info.IsDir() {
+				args = append(args, entry)
+			}
+		}
+	}
+	out, err := commandOutputInDir(primary, gitPath, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// AdoptWorktree brings a worktree created outside <repo>.wt under wtx
+// management in place, so it can be locked and deleted like a managed one.
+func (m *WorktreeManager) AdoptWorktree(path string) (WorktreeInfo, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return WorktreeInfo{}, errors.New("worktree path required")
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	primary := worktreeLayoutRoot(repoRoot, gitPath)
+	if sameRealPath(path, primary) {
+		return WorktreeInfo{}, errors.New("the main checkout can't be adopted")
+	}
+	worktrees, _, err := listWorktrees(repoRoot, gitPath)
+	if err != nil {
+		return WorktreeInfo{}, err
+	}
+	for _, wt := range worktrees {
+		if !sameRealPath(wt.Path, path) {
+			continue
+		}
+		if err := recordAdoptedWorktree(primary, wt.Path); err != nil {
+			return WorktreeInfo{}, err
+		}
+		return wt, nil
+	}
+	return WorktreeInfo{}, fmt.Errorf("%s is not a worktree of %s", path, primary)
+}
+
 func (m *WorktreeManager) CanDeleteWorktree(path string) error {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return errors.New("worktree path required")
 	}
-	_, repoRoot, err := requireGitContext(m.cwd)
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	repoRoot, err = deletableRepoRoot(repoRoot, gitPath, path)
 	if err != nil {
 		return err
 	}
 	return ensureManagedWorktreePath(repoRoot, path)
 }
 
+// UnpushedCommits counts commits on the worktree's HEAD that aren't on its
+// upstream, or on the base ref when the branch has no upstream. It returns
+// the ref compared against.
+func (m *WorktreeManager) UnpushedCommits(worktreePath string) (int, string, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return 0, "", errors.New("worktree path required")
+	}
+	gitPath, _, err := requireGitContext(m.cwd)
+	if err != nil {
+		return 0, "", err
+	}
+	ref, err := gitOutputInDir(worktreePath, gitPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if err != nil || ref == "" {
+		ref = m.ResolveBaseRefForNewBranch()
+	}
+	out, err := gitOutputInDir(worktreePath, gitPath, "rev-list", "--count", ref+"..HEAD")
+	if err != nil {
+		return 0, ref, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, ref, err
+	}
+	return count, ref, nil
+}
+
 func (m *WorktreeManager) CheckoutExistingBranch(worktreePath string, branch string) error {
 	worktreePath = strings.TrimSpace(worktreePath)
 	branch = strings.TrimSpace(branch)
@@ -281,7 +613,53 @@ func (m *WorktreeManager) CheckoutExistingBranch(worktreePath string, branch str
 	if branch == "" {
 		return errors.New("branch name required")
 	}
-	return runCommandInDir(worktreePath, "git", "checkout", branch)
+	if state := worktreeGitState(worktreePath); state != "" {
+		return gitStateSwitchError(filepath.Base(worktreePath), state)
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	if worktrees, _, err := listWorktrees(repoRoot, gitPath); err == nil {
+		if other, ok := worktreeForBranch(worktrees, branch, worktreePath); ok {
+			return &branchCheckedOutError{Branch: branch, Path: other.Path}
+		}
+	}
+	return runCommandInDir(worktreePath, gitPath, "checkout", branch)
+}
+
+// branchCheckedOutError replaces git's "already used by worktree" failure
+// with one naming the worktree that holds the branch.
+type branchCheckedOutError struct {
+	Branch string
+	Path   string
+}
+
+func (e *branchCheckedOutError) Error() string {
+	return fmt.Sprintf("branch %s is already checked out in %s", e.Branch, filepath.Base(e.Path))
+}
+
+// worktreeForBranch finds a worktree other than exceptPath that has branch
+// checked out; git refuses to check a branch out in two worktrees.
+func worktreeForBranch(worktrees []WorktreeInfo, branch string, exceptPath string) (WorktreeInfo, bool) {
+	branch = strings.TrimSpace(branch)
+	except, err := realPathOrAbs(exceptPath)
+	if err != nil {
+		except = filepath.Clean(exceptPath)
+	}
+	for _, wt := range worktrees {
+		if branch == "" || strings.TrimSpace(wt.Branch) != branch {
+			continue
+		}
+		path, err := realPathOrAbs(wt.Path)
+		if err != nil {
+			path = filepath.Clean(wt.Path)
+		}
+		if path != except {
+			return wt, true
+		}
+	}
+	return WorktreeInfo{}, false
 }
 
 func (m *WorktreeManager) CheckoutNewBranch(worktreePath string, branch string, baseRef string, doFetch bool) error {
@@ -294,6 +672,9 @@ func (m *WorktreeManager) CheckoutNewBranch(worktreePath string, branch string,
 	if branch == "" {
 		return errors.New("branch name required")
 	}
+	if state := worktreeGitState(worktreePath); state != "" {
+		return gitStateSwitchError(filepath.Base(worktreePath), state)
+	}
 	gitPath, repoRoot, err := requireGitContext(m.cwd)
 	if err != nil {
 		return err
@@ -346,7 +727,7 @@ func (m *WorktreeManager) FetchRepoBaseRef(baseRef string) error {
 		}
 	}
 
-	remote := preferredRemoteName(repoRoot, gitPath)
+	remote := baseRemoteName(repoRoot, gitPath)
 	fetchRemote, fetchRef, ok := fetchRemoteAndRefForBaseRef(baseRef, remotes, remote)
 	if !ok {
 		return nil
@@ -354,6 +735,204 @@ func (m *WorktreeManager) FetchRepoBaseRef(baseRef string) error {
 	return runCommandInDir(repoRoot, gitPath, "fetch", fetchRemote, fetchRef)
 }
 
+// ResetToRemote fetches the branch and hard-resets the worktree to the remote
+// tip. It refuses to touch worktrees with uncommitted changes.
+func (m *WorktreeManager) ResetToRemote(worktreePath string, branch string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	branch = strings.TrimSpace(branch)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	if branch == "" || branch == "detached" {
+		return errors.New("branch name required")
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	remote := preferredRemoteName(repoRoot, gitPath)
+	if remote == "" {
+		return errors.New("no git remote configured")
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, worktreePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	dirty, err := worktreeDirty(worktreePath)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return errors.New("worktree has uncommitted changes; commit or stash them before resetting")
+	}
+	if err := fetchRemoteBranch(worktreePath, gitPath, remote, branch); err != nil {
+		return err
+	}
+	return runCommandInDir(worktreePath, gitPath, "reset", "--hard", remote+"/"+branch)
+}
+
+const resetRemoteFetchTimeout = 60 * time.Second
+
+// fetchRemoteBranch fetches branch from remote without ever prompting for
+// credentials, giving up after resetRemoteFetchTimeout.
+func fetchRemoteBranch(dir string, gitPath string, remote string, branch string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), resetRemoteFetchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, gitPath, "fetch", remote, branch)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("git fetch %s %s timed out after %s", remote, branch, resetRemoteFetchTimeout)
+		} else {
+			err = commandErrorWithOutput(err, out)
+		}
+		logError("command failed", "dir", dir, "cmd", gitPath, "args", []string{"fetch", remote, branch}, "err", err)
+		return err
+	}
+	return nil
+}
+
+// ResolveStash checks that ref names an existing stash entry and returns it in
+// stash@{N} form. An empty ref means the newest stash, and a bare N is read as
+// stash@{N}.
+func (m *WorktreeManager) ResolveStash(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		ref = "stash@{0}"
+	} else if _, err := strconv.Atoi(ref); err == nil {
+		ref = "stash@{" + ref + "}"
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return "", err
+	}
+	if _, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", "--quiet", ref); err != nil || !strings.HasPrefix(ref, "stash@{") {
+		return "", fmt.Errorf("no stash entry %s", ref)
+	}
+	return ref, nil
+}
+
+// ApplyStash applies a stash entry into a clean worktree without dropping it.
+// If the apply fails or conflicts, the worktree is reset back to clean so it
+// can be retried; the stash is kept either way.
+func (m *WorktreeManager) ApplyStash(worktreePath string, ref string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	gitPath, _, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	dirty, err := worktreeDirty(worktreePath)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return errors.New("worktree has uncommitted changes; refusing to apply a stash on top")
+	}
+	if err := runCommandInDir(worktreePath, gitPath, "stash", "apply", ref); err != nil {
+		_ = runCommandInDir(worktreePath, gitPath, "reset", "--hard", "--quiet")
+		_ = runCommandInDir(worktreePath, gitPath, "clean", "-fdq")
+		return fmt.Errorf("%s did not apply cleanly and was kept: %w", ref, err)
+	}
+	return nil
+}
+
+// DiscardChanges throws away every uncommitted change in a managed worktree,
+// like git reset --hard && git clean -fd. It refuses worktrees outside the
+// layout root and ones another session has locked.
+func (m *WorktreeManager) DiscardChanges(worktreePath string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	managedRoot, err := deletableRepoRoot(repoRoot, gitPath, worktreePath)
+	if err != nil {
+		return err
+	}
+	if err := ensureManagedWorktreePath(managedRoot, worktreePath); err != nil {
+		return fmt.Errorf("refusing to discard changes: %w", err)
+	}
+	lock, err := m.lockMgr.Acquire(repoRoot, worktreePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	if err := runCommandInDir(worktreePath, gitPath, "reset", "--hard", "--quiet"); err != nil {
+		return err
+	}
+	return runCommandInDir(worktreePath, gitPath, "clean", "-fdq")
+}
+
+// SetUpstream points branch at <remote>/<branch> and returns that name. If the
+// remote branch doesn't exist yet the tracking config is written directly, so
+// the first plain `git push` creates it instead of asking for --set-upstream.
+func (m *WorktreeManager) SetUpstream(branch string) (string, error) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "detached" {
+		return "", errors.New("branch name required")
+	}
+	gitPath, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return "", err
+	}
+	remote := preferredRemoteName(repoRoot, gitPath)
+	if remote == "" {
+		return "", errors.New("no git remote configured")
+	}
+	upstream := remote + "/" + branch
+	if _, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+upstream); err == nil {
+		return upstream, runCommandInDir(repoRoot, gitPath, "branch", "--set-upstream-to="+upstream, branch)
+	}
+	if err := runCommandInDir(repoRoot, gitPath, "config", "branch."+branch+".remote", remote); err != nil {
+		return "", err
+	}
+	return upstream, runCommandInDir(repoRoot, gitPath, "config", "branch."+branch+".merge", "refs/heads/"+branch)
+}
+
+// branchUpstreams maps each local branch to its configured upstream, e.g.
+// origin/feature; branches without one are left out.
+func branchUpstreams(repoRoot string, gitPath string) map[string]string {
+	out := map[string]string{}
+	output, err := gitOutputInDir(repoRoot, gitPath, "for-each-ref", "--format=%(refname:short)%09%(upstream:short)", "refs/heads")
+	if err != nil {
+		return out
+	}
+	for _, line := range strings.Split(output, "\n") {
+		branch, upstream, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if ok && branch != "" && strings.TrimSpace(upstream) != "" {
+			out[branch] = strings.TrimSpace(upstream)
+		}
+	}
+	return out
+}
+
+// goneUpstreamBranches returns local branches whose configured upstream is
+// missing from the remote-tracking refs. It reads only the refs left by the
+// last fetch (auto-fetch prunes), so it never touches the network.
+func goneUpstreamBranches(repoRoot string, gitPath string) map[string]bool {
+	out := map[string]bool{}
+	output, err := gitOutputInDir(repoRoot, gitPath, "for-each-ref", "--format=%(refname:short)%09%(upstream:track)", "refs/heads")
+	if err != nil {
+		return out
+	}
+	for _, line := range strings.Split(output, "\n") {
+		branch, track, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if ok && branch != "" && strings.TrimSpace(track) == "[gone]" {
+			out[branch] = true
+		}
+	}
+	return out
+}
+
 func (m *WorktreeManager) AcquireWorktreeLock(worktreePath string) (*WorktreeLock, error) {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
@@ -366,6 +945,20 @@ func (m *WorktreeManager) AcquireWorktreeLock(worktreePath string) (*WorktreeLoc
 	return m.lockMgr.Acquire(repoRoot, worktreePath)
 }
 
+// AcquireWorktreeObserverLock takes an observer lock for launching an agent
+// alongside the worktree's writer; see LockManager.AcquireObserver.
+func (m *WorktreeManager) AcquireWorktreeObserverLock(worktreePath string) (*WorktreeLock, error) {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return nil, errors.New("worktree path required")
+	}
+	_, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return nil, err
+	}
+	return m.lockMgr.AcquireObserver(repoRoot, worktreePath)
+}
+
 func (m *WorktreeManager) UnlockWorktree(worktreePath string) error {
 	worktreePath = strings.TrimSpace(worktreePath)
 	if worktreePath == "" {
@@ -378,6 +971,19 @@ func (m *WorktreeManager) UnlockWorktree(worktreePath string) error {
 	return m.lockMgr.ForceUnlock(repoRoot, worktreePath)
 }
 
+// SetLockLabel labels this window's lock on worktreePath for teammates.
+func (m *WorktreeManager) SetLockLabel(worktreePath string, label string) error {
+	worktreePath = strings.TrimSpace(worktreePath)
+	if worktreePath == "" {
+		return errors.New("worktree path required")
+	}
+	_, repoRoot, err := requireGitContext(m.cwd)
+	if err != nil {
+		return err
+	}
+	return m.lockMgr.SetLabel(repoRoot, worktreePath, label)
+}
+
 func listWorktrees(repoRoot string, gitPath string) ([]WorktreeInfo, []string, error) {
 	output, err := commandOutputInDir(repoRoot, gitPath, "worktree", "list", "--porcelain")
 	if err != nil {
@@ -416,6 +1022,10 @@ func parseWorktrees(output string) ([]WorktreeInfo, []string, error) {
 				continue
 			}
 			current.Branch = shortBranch(strings.Join(fields[1:], " "))
+		case "HEAD":
+			if current != nil && len(fields) > 1 {
+				current.HeadSHA = fields[1]
+			}
 		case "detached":
 			if current == nil {
 				malformed = append(malformed, line)
@@ -496,7 +1106,7 @@ func baseRefForWorktreeAdd(repoRoot string, gitPath string, baseRef string) stri
 	if baseRef == "" || baseRef == "HEAD" {
 		return "HEAD"
 	}
-	remote := preferredRemoteName(repoRoot, gitPath)
+	remote := baseRemoteName(repoRoot, gitPath)
 	if remoteRef, ok := asRemoteRef(repoRoot, gitPath, remote, baseRef); ok {
 		return remoteRef
 	}
@@ -524,9 +1134,12 @@ func defaultBaseRefFromGitHub(repoRoot string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	out, err := commandOutputInDir(repoRoot, ghPath, "repo", "view", owner+"/"+name, "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
+	cmd := exec.Command(ghPath, "repo", "view", owner+"/"+name, "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
+	cmd.Dir = repoRoot
+	cmd.Env = ghCommandEnv(repoRoot)
+	out, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", err
+		return "", commandErrorWithOutput(err, out)
 	}
 	ref := strings.TrimSpace(string(out))
 	if ref == "" {
@@ -554,6 +1167,75 @@ func asRemoteRef(repoRoot string, gitPath string, remote string, ref string) (st
 	return "", false
 }
 
+// remoteDivergedBranches reports which branches have a <remote>/<branch> tip
+// that is not contained in the local branch. It does not fetch.
+func remoteDivergedBranches(repoRoot string, gitPath string, remote string, branches []string) map[string]bool {
+	out := map[string]bool{}
+	if strings.TrimSpace(remote) == "" || len(branches) == 0 {
+		return out
+	}
+	localOut, err := gitOutputInDir(repoRoot, gitPath, "for-each-ref", "--format=%(refname:strip=2)%09%(objectname)", "refs/heads")
+	if err != nil {
+		return out
+	}
+	remoteOut, err := gitOutputInDir(repoRoot, gitPath, "for-each-ref", "--format=%(refname:strip=3)%09%(objectname)", "refs/remotes/"+remote)
+	if err != nil {
+		return out
+	}
+	local := parseRefTips(localOut)
+	remoteTips := parseRefTips(remoteOut)
+	for _, branch := range branches {
+		localTip, ok := local[branch]
+		if !ok {
+			continue
+		}
+		remoteTip, ok := remoteTips[branch]
+		if !ok || remoteTip == localTip {
+			continue
+		}
+		// Local commits on top of the remote tip are just unpushed work.
+		if _, err := gitOutputInDir(repoRoot, gitPath, "merge-base", "--is-ancestor", remoteTip, localTip); err != nil {
+			out[branch] = true
+		}
+	}
+	return out
+}
+
+// worktreeHeadSubjects returns the HEAD commit subject of each path, looked up
+// concurrently. Paths with an unborn HEAD or a git error are left out.
+func worktreeHeadSubjects(gitPath string, paths []string) map[string]string {
+	out := make(map[string]string, len(paths))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, p := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			subject, err := gitOutputInDir(path, gitPath, "log", "-1", "--format=%s")
+			if err != nil || strings.TrimSpace(subject) == "" {
+				return
+			}
+			mu.Lock()
+			out[path] = strings.TrimSpace(subject)
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return out
+}
+
+func parseRefTips(output string) map[string]string {
+	tips := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		name, sha, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || name == "" || name == "HEAD" || sha == "" {
+			continue
+		}
+		tips[name] = sha
+	}
+	return tips
+}
+
 func preferredRemoteName(repoRoot string, gitPath string) string {
 	remotes, err := listGitRemotes(repoRoot, gitPath)
 	if err != nil {
@@ -570,6 +1252,22 @@ func preferredRemoteName(repoRoot string, gitPath string) string {
 	return remotes[0]
 }
 
+// baseRemoteName is the remote base refs resolve against: base_remote when
+// configured and present, otherwise preferredRemoteName.
+func baseRemoteName(repoRoot string, gitPath string) string {
+	if cfg, err := loadConfigForDir(repoRoot); err == nil {
+		if want := strings.TrimSpace(cfg.BaseRemote); want != "" {
+			remotes, _ := listGitRemotes(repoRoot, gitPath)
+			for _, remote := range remotes {
+				if remote == want {
+					return want
+				}
+			}
+		}
+	}
+	return preferredRemoteName(repoRoot, gitPath)
+}
+
 func fetchRemoteAndRefForBaseRef(baseRef string, remotes []string, preferredRemote string) (string, string, bool) {
 	baseRef = strings.TrimSpace(baseRef)
 	if baseRef == "" || baseRef == "HEAD" {
@@ -737,7 +1435,7 @@ func worktreeLayoutRoot(repoRoot string, gitPath string) string {
 	if repoRoot == "" || strings.TrimSpace(gitPath) == "" {
 		return repoRoot
 	}
-	commonDir, err := gitOutputInDir(repoRoot, gitPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	commonDir, err := gitCommonDir(repoRoot, gitPath)
 	if err != nil {
 		return repoRoot
 	}
@@ -748,6 +1446,44 @@ func worktreeLayoutRoot(repoRoot string, gitPath string) string {
 	return repoRoot
 }
 
+// nestedWorktreeInfo returns the primary checkout and worktree name when
+// repoRoot is itself a managed worktree of another checkout.
+func nestedWorktreeInfo(repoRoot string, gitPath string) (string, string, bool) {
+	primary := worktreeLayoutRoot(repoRoot, gitPath)
+	if primary == "" || sameRealPath(primary, repoRoot) {
+		return "", "", false
+	}
+	if err := ensureManagedWorktreePath(primary, repoRoot); err != nil {
+		return "", "", false
+	}
+	return primary, filepath.Base(repoRoot), true
+}
+
+// deletableRepoRoot resolves the primary checkout for delete checks and
+// refuses to remove the worktree wtx is currently running from.
+func deletableRepoRoot(repoRoot string, gitPath string, path string) (string, error) {
+	primary, name, ok := nestedWorktreeInfo(repoRoot, gitPath)
+	if !ok {
+		return repoRoot, nil
+	}
+	if sameRealPath(repoRoot, path) {
+		return "", fmt.Errorf("cannot delete %s while wtx is running inside it; run wtx from %s instead", name, primary)
+	}
+	return primary, nil
+}
+
+func sameRealPath(a string, b string) bool {
+	aReal, err := realPathOrAbs(a)
+	if err != nil {
+		return false
+	}
+	bReal, err := realPathOrAbs(b)
+	if err != nil {
+		return false
+	}
+	return aReal == bReal
+}
+
 func ensureManagedWorktreePath(repoRoot string, worktreePath string) error {
 	managedRoot := managedWorktreeRoot(repoRoot)
 	managedRootReal, err := realPathOrAbs(managedRoot)
@@ -764,11 +1500,28 @@ func ensureManagedWorktreePath(repoRoot string, worktreePath string) error {
 	}
 	rel = filepath.Clean(strings.TrimSpace(rel))
 	if rel == "." || rel == ".." || filepath.IsAbs(rel) || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
-		return fmt.Errorf("cannot delete worktree outside %s", managedRoot)
+		if worktreeAdopted(repoRoot, worktreePath) {
+			return nil
+		}
+		return fmt.Errorf("cannot delete worktree outside %s (run wtx adopt %s to manage it)", managedRoot, worktreePath)
 	}
 	return nil
 }
 
+// displayWorktreePath shortens path to its name under the managed worktree
+// root (e.g. wt.3) when relative is set. The main checkout and paths outside
+// the root stay absolute.
+func displayWorktreePath(repoRoot string, path string, relative bool) string {
+	if !relative || strings.TrimSpace(repoRoot) == "" {
+		return path
+	}
+	rel, err := filepath.Rel(managedWorktreeRoot(repoRoot), path)
+	if err != nil || rel == "." || rel == ".." || filepath.IsAbs(rel) || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
 func managedWorktreeRoot(repoRoot string) string {
 	base := filepath.Base(repoRoot)
 	parent := filepath.Dir(repoRoot)
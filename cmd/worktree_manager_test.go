@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCommandErrorWithOutput_PrefersCommandOutput(t *testing.T) {
@@ -153,3 +158,534 @@ func TestIsExplicitRemoteBaseRef(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWorktreeAddWithTimeout_KillsAndCleansUp(t *testing.T) {
+	dir := t.TempDir()
+	fakeGit := filepath.Join(dir, "git")
+	script := "#!/bin/sh\nif [ \"$2\" = add ]; then mkdir -p \"$3\"; sleep 5; fi\n"
+	if err := os.WriteFile(fakeGit, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake git: %v", err)
+	}
+	target := filepath.Join(dir, "wt.1")
+
+	start := time.Now()
+	err := runWorktreeAddWithTimeout(dir, fakeGit, target, 200*time.Millisecond, target, "main")
+	if !errors.Is(err, errWorktreeAddTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Fatalf("expected hung command to be killed promptly, took %s", elapsed)
+	}
+	if _, statErr := os.Stat(target); !os.IsNotExist(statErr) {
+		t.Fatalf("expected partial worktree dir to be removed, stat err=%v", statErr)
+	}
+}
+
+func TestParseRemoteBaseRefs_ListsRemoteHeadsFirst(t *testing.T) {
+	output := "origin/feature/x\t\norigin\torigin/main\norigin/main\t\nupstream/HEAD\tupstream/develop\nupstream/develop\t\n"
+	got := parseRemoteBaseRefs(output)
+	want := []string{"origin/main", "upstream/develop", "origin/feature/x"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNestedWorktreeInfo_DetectsManagedWorktree(t *testing.T) {
+	repo := initTestRepo(t)
+	base := filepath.Dir(repo)
+	wt := addTestWorktree(t, repo, 1, "feature/a")
+
+	if _, _, ok := nestedWorktreeInfo(repo, "git"); ok {
+		t.Fatalf("expected primary checkout not to be reported as nested")
+	}
+	primary, name, ok := nestedWorktreeInfo(wt, "git")
+	if !ok || name != "wt.1" || !sameRealPath(primary, repo) {
+		t.Fatalf("expected nested wt.1 of %s, got primary=%q name=%q ok=%v", repo, primary, name, ok)
+	}
+	if _, err := deletableRepoRoot(wt, "git", wt); err == nil || !strings.Contains(err.Error(), "running inside it") {
+		t.Fatalf("expected refusal to delete current worktree, got %v", err)
+	}
+	if root, err := deletableRepoRoot(wt, "git", filepath.Join(base, "repo.wt", "wt.2")); err != nil || !sameRealPath(root, repo) {
+		t.Fatalf("expected primary root for sibling delete, got %q, %v", root, err)
+	}
+}
+
+func TestWorktreeAddCommand_MatchesCreatePath(t *testing.T) {
+	repo := initTestRepo(t)
+	runTestGit(t, repo, "branch", "feature/existing")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	tests := []struct {
+		branch  string
+		baseRef string
+		want    string
+	}{
+		{branch: "feature/existing", want: "git worktree add ../repo.wt/wt.1 feature/existing"},
+		{branch: "feature/new", baseRef: "main", want: "git worktree add -b feature/new ../repo.wt/wt.1 main"},
+		{branch: "feature/new", want: "git worktree add -b feature/new ../repo.wt/wt.1 HEAD"},
+		{branch: "it's", baseRef: "main", want: `git worktree add -b 'it'\''s' ../repo.wt/wt.1 main`},
+	}
+	for _, tt := range tests {
+		got, err := mgr.WorktreeAddCommand(tt.branch, tt.baseRef)
+		if err != nil {
+			t.Fatalf("WorktreeAddCommand(%q, %q): %v", tt.branch, tt.baseRef, err)
+		}
+		if got != tt.want {
+			t.Fatalf("WorktreeAddCommand(%q, %q) = %q, want %q", tt.branch, tt.baseRef, got, tt.want)
+		}
+	}
+	if _, err := mgr.WorktreeAddCommand(" ", ""); err == nil {
+		t.Fatalf("expected an error without a branch")
+	}
+}
+
+func TestRepairWorktrees_FixesLinksAfterMove(t *testing.T) {
+	repo := initTestRepo(t)
+	base := filepath.Dir(repo)
+	addTestWorktree(t, repo, 1, "feature/a")
+
+	moved := filepath.Join(base, "moved")
+	if err := os.Rename(repo, moved); err != nil {
+		t.Fatalf("move repo: %v", err)
+	}
+	if err := os.Rename(filepath.Join(base, "repo.wt"), filepath.Join(base, "moved.wt")); err != nil {
+		t.Fatalf("move worktrees: %v", err)
+	}
+	wt := filepath.Join(base, "moved.wt", "wt.1")
+	if err := exec.Command("git", "-C", wt, "status").Run(); err == nil {
+		t.Fatalf("expected broken worktree before repair")
+	}
+
+	mgr := NewWorktreeManager(moved, NewLockManager())
+	if _, err := mgr.RepairWorktrees(); err != nil {
+		t.Fatalf("RepairWorktrees: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", wt, "status").CombinedOutput(); err != nil {
+		t.Fatalf("expected worktree usable after repair: %v\n%s", err, out)
+	}
+}
+
+func TestCreateDetachedWorktree_LabelsShortSHA(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	runTestGit(t, repo, "tag", "v1")
+	runTestGit(t, repo, "commit", "-q", "--allow-empty", "-m", "next")
+	tagSHA, err := exec.Command("git", "-C", repo, "rev-parse", "v1").Output()
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	if _, err := mgr.CreateDetachedWorktree("no-such-ref"); err == nil {
+		t.Fatalf("expected unknown ref to fail")
+	}
+	created, err := mgr.CreateDetachedWorktree("v1")
+	if err != nil {
+		t.Fatalf("CreateDetachedWorktree: %v", err)
+	}
+	worktrees, _, err := listWorktrees(repo, "git")
+	if err != nil {
+		t.Fatalf("listWorktrees: %v", err)
+	}
+	want := "detached @ " + strings.TrimSpace(string(tagSHA))[:7]
+	for _, wt := range worktrees {
+		if sameRealPath(wt.Path, created.Path) {
+			if got := worktreeBranchLabel(wt); got != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+			return
+		}
+	}
+	t.Fatalf("created worktree %s not listed in %+v", created.Path, worktrees)
+}
+
+func TestDeleteWorktree_StartsMaintenanceWhenConfigured(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	configDir := t.TempDir()
+	t.Setenv(configDirOverrideEnv, configDir)
+	oldStart := startRepoMaintenanceFn
+	t.Cleanup(func() { startRepoMaintenanceFn = oldStart })
+	started := 0
+	startRepoMaintenanceFn = func(string, string) { started++ }
+
+	repo := initTestRepo(t)
+	wt1 := addTestWorktree(t, repo, 1, "feature/a")
+	wt2 := addTestWorktree(t, repo, 2, "feature/b")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	writeTestFile(t, filepath.Join(configDir, "config.json"), `{"agent_command":"claude"}`)
+	if err := mgr.DeleteWorktree(wt1, false); err != nil {
+		t.Fatalf("DeleteWorktree: %v", err)
+	}
+	if started != 0 {
+		t.Fatalf("expected no maintenance without run_gc_after_delete")
+	}
+	writeTestFile(t, filepath.Join(configDir, "config.json"), `{"agent_command":"claude","run_gc_after_delete":true}`)
+	if err := mgr.DeleteWorktree(wt2, false); err != nil {
+		t.Fatalf("DeleteWorktree: %v", err)
+	}
+	if started != 1 {
+		t.Fatalf("expected maintenance to start once, got %d", started)
+	}
+}
+
+func TestAdoptWorktree_AllowsDeletingOutsideLayoutRoot(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	base := filepath.Dir(repo)
+	external := filepath.Join(base, "elsewhere")
+	runTestGit(t, repo, "worktree", "add", "-q", "-b", "feature/x", external)
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	if err := mgr.CanDeleteWorktree(external); err == nil {
+		t.Fatalf("expected unadopted external worktree to be refused")
+	}
+	if _, err := mgr.AdoptWorktree(repo); err == nil {
+		t.Fatalf("expected adopting the main checkout to fail")
+	}
+	if _, err := mgr.AdoptWorktree(filepath.Join(base, "missing")); err == nil {
+		t.Fatalf("expected adopting a non-worktree to fail")
+	}
+	wt, err := mgr.AdoptWorktree(external)
+	if err != nil {
+		t.Fatalf("AdoptWorktree: %v", err)
+	}
+	if wt.Branch != "feature/x" {
+		t.Fatalf("expected adopted branch feature/x, got %q", wt.Branch)
+	}
+	if err := mgr.DeleteWorktree(external, false); err != nil {
+		t.Fatalf("DeleteWorktree: %v", err)
+	}
+	if worktreeAdopted(repo, external) {
+		t.Fatalf("expected adoption to be forgotten after delete")
+	}
+}
+
+func TestApplyStash_KeepsStashAndCleansUpOnConflict(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	writeTestFile(t, filepath.Join(repo, "a.txt"), "wip\n")
+	runTestGit(t, repo, "stash", "-q")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	if _, err := mgr.ResolveStash("stash@{3}"); err == nil {
+		t.Fatalf("expected missing stash entry to fail")
+	}
+	ref, err := mgr.ResolveStash("0")
+	if err != nil || ref != "stash@{0}" {
+		t.Fatalf("ResolveStash: %q %v", ref, err)
+	}
+
+	clean := addTestWorktree(t, repo, 1, "feature/wip")
+	if err := mgr.ApplyStash(clean, ref); err != nil {
+		t.Fatalf("ApplyStash: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(clean, "a.txt")); string(data) != "wip\n" {
+		t.Fatalf("expected stashed change in new worktree, got %q", data)
+	}
+
+	conflicting := addTestWorktree(t, repo, 2, "feature/other")
+	writeTestFile(t, filepath.Join(conflicting, "a.txt"), "other\n")
+	runTestGit(t, conflicting, "commit", "-q", "-am", "other")
+	if err := mgr.ApplyStash(conflicting, ref); err == nil {
+		t.Fatalf("expected conflicting stash apply to fail")
+	}
+	if dirty, err := worktreeDirty(conflicting); err != nil || dirty {
+		t.Fatalf("expected worktree reset to clean after failed apply, dirty=%v err=%v", dirty, err)
+	}
+	if out, err := exec.Command("git", "-C", repo, "stash", "list").Output(); err != nil || strings.Count(string(out), "stash@{") != 1 {
+		t.Fatalf("expected stash to be kept, got %q %v", out, err)
+	}
+}
+
+func TestDiscardChanges_ResetsManagedWorktreeOnly(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	managed := addTestWorktree(t, repo, 1, "feature/a")
+	external := filepath.Join(filepath.Dir(repo), "elsewhere")
+	runTestGit(t, repo, "worktree", "add", "-q", "-b", "feature/b", external)
+	for _, wt := range []string{managed, external} {
+		writeTestFile(t, filepath.Join(wt, "a.txt"), "changed\n")
+		writeTestFile(t, filepath.Join(wt, "scratch", "tmp.txt"), "x\n")
+	}
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	if err := mgr.DiscardChanges(external); err == nil {
+		t.Fatalf("expected unmanaged worktree to be refused")
+	}
+	if dirty, _ := worktreeDirty(external); !dirty {
+		t.Fatalf("expected unmanaged worktree to keep its changes")
+	}
+	if err := mgr.DiscardChanges(managed); err != nil {
+		t.Fatalf("DiscardChanges: %v", err)
+	}
+	if dirty, err := worktreeDirty(managed); err != nil || dirty {
+		t.Fatalf("expected managed worktree to be clean, dirty=%v err=%v", dirty, err)
+	}
+}
+
+func TestCheckoutExistingBranch_NamesWorktreeHoldingBranch(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	first := addTestWorktree(t, repo, 1, "feature/a")
+	second := addTestWorktree(t, repo, 2, "feature/b")
+	mgr := NewWorktreeManager(repo, NewLockManager())
+
+	err := mgr.CheckoutExistingBranch(second, "feature/a")
+	var checkedOut *branchCheckedOutError
+	if !errors.As(err, &checkedOut) {
+		t.Fatalf("expected branchCheckedOutError, got %v", err)
+	}
+	if err.Error() != "branch feature/a is already checked out in wt.1" {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	if err := mgr.CheckoutExistingBranch(first, "feature/a"); err != nil {
+		t.Fatalf("expected checking out the worktree's own branch to pass, got %v", err)
+	}
+}
+
+func TestWorktreeGitState_BlocksBranchSwitchMidRebase(t *testing.T) {
+	t.Setenv(stateDirOverrideEnv, t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	runTestGit(t, repo, "branch", "feature/b")
+	wt := addTestWorktree(t, repo, 1, "feature/a")
+	if state := worktreeGitState(wt); state != "" {
+		t.Fatalf("expected no operation in progress, got %q", state)
+	}
+	gitDir, ok := worktreeGitDir(wt)
+	if !ok {
+		t.Fatalf("expected linked worktree git dir")
+	}
+	if err := os.Mkdir(filepath.Join(gitDir, "rebase-merge"), 0o755); err != nil {
+		t.Fatalf("mkdir rebase-merge: %v", err)
+	}
+	if state := worktreeGitState(wt); state != "rebasing" {
+		t.Fatalf("expected rebasing, got %q", state)
+	}
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	err := mgr.CheckoutExistingBranch(wt, "feature/b")
+	if err == nil || !strings.Contains(err.Error(), "wt.1 is rebasing; run git rebase --continue or --abort") {
+		t.Fatalf("expected rebase explanation, got %v", err)
+	}
+	if err := mgr.CheckoutNewBranch(wt, "feature/c", "", false); err == nil {
+		t.Fatalf("expected new-branch checkout to be refused mid-rebase")
+	}
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=wtx", "-c", "user.email=wtx@example.test"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// initTestRepo creates a repo with one commit on main in a fresh temp dir,
+// leaving room for managed worktrees next to it.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	runTestGit(t, repo, "init", "-q", "-b", "main")
+	writeTestFile(t, filepath.Join(repo, "a.txt"), "one\n")
+	runTestGit(t, repo, "add", "a.txt")
+	runTestGit(t, repo, "commit", "-q", "-m", "init")
+	return repo
+}
+
+// addTestWorktree adds wt.<n> on a new branch at the managed layout path.
+func addTestWorktree(t *testing.T, repo string, n int, branch string) string {
+	t.Helper()
+	wt := filepath.Join(filepath.Dir(repo), filepath.Base(repo)+".wt", "wt."+strconv.Itoa(n))
+	runTestGit(t, repo, "worktree", "add", "-q", "-b", branch, wt)
+	return wt
+}
+
+func TestRemoteDivergedBranchesAndResetToRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	origin := filepath.Join(base, "origin.git")
+	runTestGit(t, base, "init", "-q", "--bare", origin)
+	local := filepath.Join(base, "local")
+	runTestGit(t, base, "clone", "-q", origin, local)
+	runTestGit(t, local, "checkout", "-q", "-b", "feature/a")
+	runTestGit(t, local, "commit", "-q", "--allow-empty", "-m", "one")
+	runTestGit(t, local, "push", "-q", "origin", "feature/a")
+
+	runTestGit(t, local, "commit", "-q", "--allow-empty", "-m", "unpushed")
+	if diverged := remoteDivergedBranches(local, "git", "origin", []string{"feature/a"}); diverged["feature/a"] {
+		t.Fatalf("expected unpushed local commits not to count as diverged")
+	}
+
+	other := filepath.Join(base, "other")
+	runTestGit(t, base, "clone", "-q", "-b", "feature/a", origin, other)
+	runTestGit(t, other, "commit", "-q", "--allow-empty", "-m", "remote change")
+	runTestGit(t, other, "push", "-q", "origin", "feature/a")
+	runTestGit(t, local, "fetch", "-q", "origin")
+	if diverged := remoteDivergedBranches(local, "git", "origin", []string{"feature/a"}); !diverged["feature/a"] {
+		t.Fatalf("expected remote push to mark branch diverged")
+	}
+
+	mgr := NewWorktreeManager(local, NewLockManager())
+	if err := mgr.ResetToRemote(local, "feature/a"); err != nil {
+		t.Fatalf("ResetToRemote: %v", err)
+	}
+	if diverged := remoteDivergedBranches(local, "git", "origin", []string{"feature/a"}); diverged["feature/a"] {
+		t.Fatalf("expected branch to match remote after reset")
+	}
+
+	if err := os.WriteFile(filepath.Join(local, "dirty.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := mgr.ResetToRemote(local, "feature/a"); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+		t.Fatalf("expected dirty worktree to be refused, got %v", err)
+	}
+}
+
+func TestUnpushedCommits_ComparesAgainstBaseWithoutUpstream(t *testing.T) {
+	repo := initTestRepo(t)
+	wt := addTestWorktree(t, repo, 1, "feature/a")
+
+	mgr := NewWorktreeManager(repo, NewLockManager())
+	if count, ref, err := mgr.UnpushedCommits(wt); err != nil || count != 0 || ref != "main" {
+		t.Fatalf("expected 0 commits against main, got %d %q %v", count, ref, err)
+	}
+	runTestGit(t, wt, "commit", "-q", "--allow-empty", "-m", "one")
+	runTestGit(t, wt, "commit", "-q", "--allow-empty", "-m", "two")
+	if count, ref, err := mgr.UnpushedCommits(wt); err != nil || count != 2 || ref != "main" {
+		t.Fatalf("expected 2 commits against main, got %d %q %v", count, ref, err)
+	}
+}
+
+func TestWorktreeHeadSubjects_SkipsUnbornHead(t *testing.T) {
+	base := t.TempDir()
+	repo := filepath.Join(base, "repo")
+	empty := filepath.Join(base, "empty")
+	for _, dir := range []string{repo, empty} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		runTestGit(t, dir, "init", "-q")
+	}
+	runTestGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Add the thing")
+
+	subjects := worktreeHeadSubjects("git", []string{repo, empty})
+	if subjects[repo] != "Add the thing" {
+		t.Fatalf("expected HEAD subject, got %q", subjects[repo])
+	}
+	if _, ok := subjects[empty]; ok {
+		t.Fatalf("expected unborn HEAD to be skipped, got %q", subjects[empty])
+	}
+}
+
+func TestSetUpstream_TracksRemoteBranchOrWritesConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	origin := filepath.Join(base, "origin.git")
+	runTestGit(t, base, "init", "-q", "--bare", origin)
+	local := filepath.Join(base, "local")
+	runTestGit(t, base, "clone", "-q", origin, local)
+	runTestGit(t, local, "commit", "-q", "--allow-empty", "-m", "init")
+	runTestGit(t, local, "branch", "pushed")
+	runTestGit(t, local, "push", "-q", "origin", "pushed")
+	runTestGit(t, local, "branch", "fresh")
+
+	if got := branchUpstreams(local, "git"); got["pushed"] != "" || got["fresh"] != "" {
+		t.Fatalf("expected no upstreams yet, got %v", got)
+	}
+	mgr := NewWorktreeManager(local, NewLockManager())
+	for _, branch := range []string{"pushed", "fresh"} {
+		upstream, err := mgr.SetUpstream(branch)
+		if err != nil || upstream != "origin/"+branch {
+			t.Fatalf("SetUpstream(%s) = %q, %v", branch, upstream, err)
+		}
+	}
+	got := branchUpstreams(local, "git")
+	if got["pushed"] != "origin/pushed" || got["fresh"] != "origin/fresh" {
+		t.Fatalf("expected both branches to track origin, got %v", got)
+	}
+}
+
+func TestGoneUpstreamBranches_AfterRemoteDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	origin := filepath.Join(base, "origin.git")
+	runTestGit(t, base, "init", "-q", "--bare", origin)
+	local := filepath.Join(base, "local")
+	runTestGit(t, base, "clone", "-q", origin, local)
+	runTestGit(t, local, "commit", "-q", "--allow-empty", "-m", "init")
+	runTestGit(t, local, "push", "-q", "origin", "HEAD")
+	for _, branch := range []string{"merged", "open"} {
+		runTestGit(t, local, "branch", branch)
+		runTestGit(t, local, "push", "-q", "-u", "origin", branch)
+	}
+	runTestGit(t, local, "branch", "local-only")
+
+	if got := goneUpstreamBranches(local, "git"); len(got) != 0 {
+		t.Fatalf("expected no gone upstreams yet, got %v", got)
+	}
+	runTestGit(t, origin, "branch", "-D", "merged")
+	runTestGit(t, local, "fetch", "-q", "--prune")
+	got := goneUpstreamBranches(local, "git")
+	if !got["merged"] || got["open"] || got["local-only"] {
+		t.Fatalf("expected only merged to be gone, got %v", got)
+	}
+}
+
+func TestDisplayWorktreePath(t *testing.T) {
+	repo := filepath.Join(string(filepath.Separator), "code", "proj")
+	managed := filepath.Join(string(filepath.Separator), "code", "proj.wt", "wt.3")
+	tests := []struct {
+		name     string
+		path     string
+		relative bool
+		want     string
+	}{
+		{name: "absolute by default", path: managed, want: managed},
+		{name: "managed worktree", path: managed, relative: true, want: "wt.3"},
+		{name: "main checkout", path: repo, relative: true, want: repo},
+		{name: "outside root", path: "/elsewhere/wt", relative: true, want: "/elsewhere/wt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWorktreePath(repo, tt.path, tt.relative); got != tt.want {
+				t.Fatalf("displayWorktreePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseRefForWorktreeAdd_PrefersConfiguredBaseRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	repo := initTestRepo(t)
+	for _, remote := range []string{"origin", "upstream"} {
+		runTestGit(t, repo, "remote", "add", remote, "https://example.com/"+remote+".git")
+		runTestGit(t, repo, "update-ref", "refs/remotes/"+remote+"/main", "HEAD")
+	}
+
+	if got := baseRefForWorktreeAdd(repo, "git", "main"); got != "origin/main" {
+		t.Fatalf("expected origin by default, got %q", got)
+	}
+	if err := SaveConfig(Config{AgentCommand: defaultAgentCommand, BaseRemote: "upstream"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got := baseRefForWorktreeAdd(repo, "git", "main"); got != "upstream/main" {
+		t.Fatalf("expected configured base remote, got %q", got)
+	}
+	if err := SaveConfig(Config{AgentCommand: defaultAgentCommand, BaseRemote: "missing"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	if got := baseRefForWorktreeAdd(repo, "git", "main"); got != "origin/main" {
+		t.Fatalf("expected fallback when base remote is absent, got %q", got)
+	}
+}
@@ -1,6 +1,9 @@
 package cmd
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 type WorktreeOrchestrator struct {
 	mgr     *WorktreeManager
@@ -16,6 +19,10 @@ func (o *WorktreeOrchestrator) Status() WorktreeStatus {
 	if o == nil || o.mgr == nil {
 		return WorktreeStatus{}
 	}
+	start := time.Now()
+	defer func() {
+		logDebug("worktree status loaded", "duration", time.Since(start))
+	}()
 	status := o.mgr.ListForStatusBase()
 	if status.Err != nil || !status.InRepo || strings.TrimSpace(status.RepoRoot) == "" || o.lockMgr == nil {
 		return status
@@ -25,6 +32,7 @@ func (o *WorktreeOrchestrator) Status() WorktreeStatus {
 	for _, wt := range status.Worktrees {
 		exists, err := worktreePathExists(wt.Path)
 		if err != nil {
+			logError("worktree path check failed", "path", wt.Path, "err", err)
 			status.Err = err
 			return status
 		}
@@ -43,18 +51,57 @@ func (o *WorktreeOrchestrator) Status() WorktreeStatus {
 		lastUsed := worktreeLastUsedUnix(status.RepoRoot, wt.Path)
 		available, err := o.lockMgr.IsAvailable(status.RepoRoot, wt.Path)
 		if err != nil {
+			logError("lock availability check failed", "path", wt.Path, "err", err)
 			status.Err = err
 			return status
 		}
+		logDebug("worktree lock state", "path", wt.Path, "branch", wt.Branch, "available", available)
 		for i := range status.Worktrees {
 			if status.Worktrees[i].Path == wt.Path {
 				status.Worktrees[i].Available = available
 				status.Worktrees[i].LastUsedUnix = lastUsed
+				status.Worktrees[i].LockedByMe = o.lockMgr.OwnedByCurrentSession(status.RepoRoot, wt.Path)
+				if !available || status.Worktrees[i].LockedByMe {
+					status.Worktrees[i].LockLabel = o.lockMgr.Label(status.RepoRoot, wt.Path)
+				}
 				break
 			}
 		}
 	}
 	status.Orphaned = orphaned
+	gitPath := gitBinary()
+	paths := make([]string, 0, len(status.Worktrees))
+	for _, wt := range status.Worktrees {
+		if !isOrphanedPath(status, wt.Path) {
+			paths = append(paths, wt.Path)
+		}
+	}
+	subjects := worktreeHeadSubjects(gitPath, paths)
+	upstreams := branchUpstreams(status.RepoRoot, gitPath)
+	notes, err := worktreeNotes(status.RepoRoot, paths)
+	if err != nil {
+		logError("worktree notes load failed", "repo", status.RepoRoot, "err", err)
+	}
+	for i := range status.Worktrees {
+		status.Worktrees[i].HeadSubject = subjects[status.Worktrees[i].Path]
+		status.Worktrees[i].Note = notes[status.Worktrees[i].Path]
+		status.Worktrees[i].Upstream = upstreams[status.Worktrees[i].Branch]
+		if !isOrphanedPath(status, status.Worktrees[i].Path) {
+			status.Worktrees[i].GitState = worktreeGitState(status.Worktrees[i].Path)
+		}
+	}
+	if status.HasRemote {
+		branches := make([]string, 0, len(status.Worktrees))
+		for _, wt := range status.Worktrees {
+			branches = append(branches, wt.Branch)
+		}
+		diverged := remoteDivergedBranches(status.RepoRoot, gitPath, preferredRemoteName(status.RepoRoot, gitPath), branches)
+		gone := goneUpstreamBranches(status.RepoRoot, gitPath)
+		for i := range status.Worktrees {
+			status.Worktrees[i].RemoteDiverged = diverged[status.Worktrees[i].Branch]
+			status.Worktrees[i].RemoteBranchGone = gone[status.Worktrees[i].Branch]
+		}
+	}
 	return status
 }
 
@@ -73,10 +120,36 @@ func (o *WorktreeOrchestrator) PRDataForStatusWithError(status WorktreeStatus, f
 		}
 		branches = append(branches, b)
 	}
-	if force {
-		return o.prMgr.PRDataByBranchForce(status.RepoRoot, branches)
+	byBranch, err := o.PRDataForBranchesWithError(status.RepoRoot, branches, force)
+	if len(status.Linked) == 0 {
+		return byBranch, err
+	}
+	merged := make(map[string]PRData, len(byBranch))
+	for branch, data := range byBranch {
+		merged[branch] = data
+	}
+	linkedBranches := map[string][]string{}
+	roots := []string{}
+	for _, wt := range status.Linked {
+		b := strings.TrimSpace(wt.Branch)
+		if b == "" || b == "detached" {
+			continue
+		}
+		if _, ok := linkedBranches[wt.LinkedRepo]; !ok {
+			roots = append(roots, wt.LinkedRepo)
+		}
+		linkedBranches[wt.LinkedRepo] = append(linkedBranches[wt.LinkedRepo], b)
+	}
+	for _, root := range roots {
+		data, linkedErr := o.PRDataForBranchesWithError(root, linkedBranches[root], force)
+		if linkedErr != nil && err == nil {
+			err = linkedErr
+		}
+		for branch, pr := range data {
+			merged[prDataKey(WorktreeInfo{Branch: branch, LinkedRepo: root})] = pr
+		}
 	}
-	return o.prMgr.PRDataByBranch(status.RepoRoot, branches)
+	return merged, err
 }
 
 func (o *WorktreeOrchestrator) PRDataForBranchesWithError(repoRoot string, branches []string, force bool) (map[string]PRData, error) {
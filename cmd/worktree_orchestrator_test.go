@@ -1,6 +1,9 @@
 package cmd
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+)
 
 func TestResolveOpenTargetSlot_ExistingBranchUsesAttachedWorktree(t *testing.T) {
 	o := &WorktreeOrchestrator{}
@@ -62,3 +65,56 @@ func TestResolveOpenTargetSlot_NoSlotAvailable(t *testing.T) {
 		t.Fatalf("expected no slot")
 	}
 }
+
+func TestResolveOpenTargetSlot_SkipsSlotMidRebase(t *testing.T) {
+	o := &WorktreeOrchestrator{}
+	slots := []openSlotState{
+		{Path: "/wt/1", Branch: "main", GitState: "rebasing"},
+		{Path: "/wt/2", Branch: "dev"},
+	}
+
+	got, ok := o.ResolveOpenTargetSlot(slots, "feat/cli", false)
+	if !ok || got.Path != "/wt/2" {
+		t.Fatalf("expected the idle slot /wt/2, got %q (ok=%v)", got.Path, ok)
+	}
+	if _, ok := o.ResolveOpenTargetSlot(slots[:1], "feat/new", true); ok {
+		t.Fatalf("expected no slot when the only one is mid-rebase")
+	}
+}
+
+func TestStatusWithLinked_AddsLinkedRepoWorktrees(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(configDirOverrideEnv, t.TempDir())
+	app := initTestRepo(t)
+	lib := initTestRepo(t)
+	libWt := addTestWorktree(t, lib, 1, "feature/lib")
+	if err := SaveConfig(Config{LinkedRepos: []string{lib, app, lib, filepath.Join(t.TempDir(), "missing")}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	lockMgr := NewLockManager()
+	status := NewWorktreeOrchestrator(NewWorktreeManager(app, lockMgr), lockMgr, nil).StatusWithLinked()
+	if status.Err != nil {
+		t.Fatalf("status: %v", status.Err)
+	}
+	found := false
+	for _, wt := range status.Linked {
+		if !sameRealPath(wt.LinkedRepo, lib) {
+			t.Fatalf("expected only lib worktrees in Linked, got %+v", wt)
+		}
+		if sameRealPath(wt.Path, libWt) {
+			if found || wt.Branch != "feature/lib" || !wt.Available {
+				t.Fatalf("unexpected linked worktree %+v", wt)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s in linked worktrees, got %+v", libWt, status.Linked)
+	}
+	for _, wt := range status.Worktrees {
+		if wt.LinkedRepo != "" {
+			t.Fatalf("expected current repo rows untagged, got %+v", wt)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package cmd
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 type WorktreeInfo struct {
 	Path                string
 	Branch              string
@@ -13,6 +20,9 @@ type WorktreeInfo struct {
 	CIDone              int
 	CITotal             int
 	CIFailingNames      string
+	CIFailingURL        string
+	LatestCommentURL    string
+	Reviewers           []string
 	Approved            bool
 	ReviewApproved      int
 	ReviewRequired      int
@@ -21,6 +31,33 @@ type WorktreeInfo struct {
 	ResolvedComments    int
 	CommentThreadsTotal int
 	CommentsKnown       bool
+	// RemoteDiverged is set when <remote>/<branch> has commits the local
+	// branch does not, e.g. after a force-push or someone else's push.
+	RemoteDiverged bool
+	// HeadSubject is the subject of the worktree's HEAD commit; empty for an
+	// unborn HEAD.
+	HeadSubject string
+	// Upstream is the branch's tracking ref, e.g. origin/feature; empty when
+	// unset.
+	Upstream string
+	// LockedByMe is set when the lock belongs to this terminal or tmux
+	// session rather than someone else's.
+	LockedByMe bool
+	// LockLabel is the label the lock holder gave, e.g. via --label.
+	LockLabel string
+	// Note is the user's free-form note for this worktree directory.
+	Note string
+	// HeadSHA is the worktree's HEAD commit from `git worktree list`.
+	HeadSHA string
+	// GitState names an operation in progress, e.g. "rebasing" or
+	// "merging"; branch switches are refused until it is finished.
+	GitState string
+	// RemoteBranchGone is set when the branch tracks an upstream that no
+	// longer exists, typically deleted after its PR merged.
+	RemoteBranchGone bool
+	// LinkedRepo is the root of the linked repo this worktree belongs to;
+	// empty for the current repo.
+	LinkedRepo string
 }
 
 type WorktreeStatus struct {
@@ -34,4 +71,91 @@ type WorktreeStatus struct {
 	Orphaned     []WorktreeInfo
 	Malformed    []string
 	Err          error
+	GroupByPR    bool
+	// BaseRemote is the remote BaseRef lives on, empty without one.
+	BaseRemote string
+	// Linked holds worktrees from linked_repos; only the list screen loads them.
+	Linked []WorktreeInfo
+	// PrimaryRoot and NestedWorktree are set when wtx runs from inside one of
+	// its own managed worktrees (e.g. repo.wt/wt.2) instead of the primary.
+	PrimaryRoot    string
+	NestedWorktree string
+}
+
+// worktreeBranchLabel labels detached worktrees with their commit since they
+// have no branch name.
+func worktreeBranchLabel(wt WorktreeInfo) string {
+	if wt.Branch == "detached" && len(wt.HeadSHA) >= 7 {
+		return "detached @ " + wt.HeadSHA[:7]
+	}
+	return wt.Branch
+}
+
+// worktreeGitStates maps marker files in a worktree's git dir to the
+// in-progress operation they mean and the command that ends it.
+var worktreeGitStates = []struct {
+	marker string
+	state  string
+	finish string
+}{
+	{"rebase-merge", "rebasing", "git rebase --continue or --abort"},
+	{"rebase-apply", "rebasing", "git rebase --continue or --abort"},
+	{"MERGE_HEAD", "merging", "git merge --continue or --abort"},
+	{"CHERRY_PICK_HEAD", "cherry-picking", "git cherry-pick --continue or --abort"},
+	{"REVERT_HEAD", "reverting", "git revert --continue or --abort"},
+	{"BISECT_LOG", "bisecting", "git bisect reset"},
+}
+
+// worktreeGitState names the operation in progress in the worktree, such as
+// "rebasing", or returns "" when there is none.
+func worktreeGitState(path string) string {
+	gitDir, ok := worktreeGitDir(path)
+	if !ok {
+		return ""
+	}
+	for _, s := range worktreeGitStates {
+		if _, err := os.Stat(filepath.Join(gitDir, s.marker)); err == nil {
+			return s.state
+		}
+	}
+	return ""
+}
+
+// gitStateSwitchError explains why the worktree called name can't switch
+// branches while state is in progress.
+func gitStateSwitchError(name string, state string) error {
+	finish := "finish or abort it"
+	for _, s := range worktreeGitStates {
+		if s.state == state {
+			finish = s.finish
+			break
+		}
+	}
+	return fmt.Errorf("%s is %s; run %s before switching branches", name, state, finish)
+}
+
+// worktreeGitDir resolves a worktree's git dir: .git itself in the primary
+// checkout, or the gitdir a linked worktree's .git file points to.
+func worktreeGitDir(path string) (string, bool) {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return dotGit, true
+	}
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", false
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(path, dir)
+	}
+	return dir, true
 }
@@ -1,6 +1,10 @@
 package ui
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 type WorktreeRow struct {
 	BranchLabel     string
@@ -11,12 +15,16 @@ type WorktreeRow struct {
 	CommentsLabel   string
 	UnresolvedLabel string
 	PRStatusLabel   string
+	Note            string
+	Group           string
 	Disabled        bool
 }
 
-func RenderWorktreeSelector(rows []WorktreeRow, cursor int, styles Styles) string {
+// RenderWorktreeSelector renders rows fitted to width; width <= 0 (no
+// WindowSizeMsg yet) falls back to the default branch column.
+func RenderWorktreeSelector(rows []WorktreeRow, cursor int, width int, styles Styles) string {
+	branchWidth := selectorBranchWidth(rows, width)
 	const (
-		branchWidth     = 40
 		prWidth         = 12
 		ciWidth         = 24
 		approvalWidth   = 12
@@ -24,11 +32,18 @@ func RenderWorktreeSelector(rows []WorktreeRow, cursor int, styles Styles) strin
 		unresolvedWidth = 10
 		prStateWidth    = 17
 	)
+	noteWidth := selectorNoteWidth(rows, branchWidth, width)
 	var b strings.Builder
 	header := formatWorktreeLine("Branch", "PR", "CI", "Approval", "Comments", "Unresolved", "PR Status", branchWidth, prWidth, ciWidth, approvalWidth, commentsWidth, unresolvedWidth, prStateWidth)
 	b.WriteString(styles.Header("  " + header))
 	b.WriteString("\n")
+	group := ""
 	for i, row := range rows {
+		if row.Group != "" && row.Group != group {
+			b.WriteString(styles.Header("  " + row.Group))
+			b.WriteString("\n")
+		}
+		group = row.Group
 		rowStyle := styles.Normal
 		rowSelectedStyle := styles.Selected
 		if row.Disabled {
@@ -56,11 +71,106 @@ func RenderWorktreeSelector(rows []WorktreeRow, cursor int, styles Styles) strin
 		} else {
 			b.WriteString("  " + rowStyle(line))
 		}
+		if noteWidth > 0 && row.Note != "" {
+			b.WriteString(" " + styles.Secondary(PadOrTrim(row.Note, noteWidth)))
+		}
 		b.WriteString("\n")
 	}
 	return b.String()
 }
 
+// RenderCompactWorktreeSelector renders one line per row with only the
+// branch, PR status, and PR number, for terminals too short for the full
+// table. Group headers are left out so the row count matches the cursor.
+func RenderCompactWorktreeSelector(rows []WorktreeRow, cursor int, width int, styles Styles) string {
+	const (
+		prWidth       = 12
+		prStatusWidth = 17
+	)
+	branchWidth := defaultBranchWidth
+	if width > 0 {
+		branchWidth = max(width-2-prStatusWidth-prWidth-3, minBranchWidth)
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		rowStyle := styles.Normal
+		rowSelectedStyle := styles.Selected
+		if row.Disabled {
+			rowStyle = styles.Disabled
+			rowSelectedStyle = styles.DisabledSelected
+		}
+		line := PadOrTrim(row.BranchLabel, branchWidth) + " " +
+			PadOrTrim(row.PRStatusLabel, prStatusWidth) + " " +
+			PadOrTrim(row.PRLabel, prWidth)
+		if i == cursor {
+			b.WriteString("  " + rowSelectedStyle(line))
+		} else {
+			b.WriteString("  " + rowStyle(line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+const (
+	defaultBranchWidth = 40
+	minBranchWidth     = 12
+	maxBranchWidth     = 72
+	// Indent, six column separators, and the non-branch columns.
+	selectorFixedWidth = 2 + 6 + 12 + 24 + 12 + 10 + 10 + 17
+)
+
+func selectorBranchWidth(rows []WorktreeRow, width int) int {
+	if width <= 0 {
+		return defaultBranchWidth
+	}
+	longest := len("Branch")
+	for _, row := range rows {
+		if w := lipgloss.Width(row.BranchLabel); w > longest {
+			longest = w
+		}
+	}
+	branchWidth := width - selectorFixedWidth - 1
+	if branchWidth > longest {
+		branchWidth = max(longest, defaultBranchWidth)
+	}
+	if branchWidth > maxBranchWidth {
+		branchWidth = maxBranchWidth
+	}
+	if branchWidth < minBranchWidth {
+		branchWidth = minBranchWidth
+	}
+	return branchWidth
+}
+
+const (
+	defaultNoteWidth = 24
+	minNoteWidth     = 8
+	maxNoteWidth     = 40
+)
+
+// selectorNoteWidth sizes the trailing notes column from the space left after
+// the fixed columns; 0 hides it.
+func selectorNoteWidth(rows []WorktreeRow, branchWidth int, width int) int {
+	longest := 0
+	for _, row := range rows {
+		if w := lipgloss.Width(row.Note); w > longest {
+			longest = w
+		}
+	}
+	if longest == 0 {
+		return 0
+	}
+	if width <= 0 {
+		return min(longest, defaultNoteWidth)
+	}
+	avail := width - branchWidth - selectorFixedWidth - 2
+	if avail < minNoteWidth {
+		return 0
+	}
+	return min(longest, avail, maxNoteWidth)
+}
+
 func formatWorktreeLine(branch string, pr string, ci string, approval string, comments string, unresolved string, prState string, branchWidth int, prWidth int, ciWidth int, approvalWidth int, commentsWidth int, unresolvedWidth int, prStateWidth int) string {
 	return PadOrTrim(branch, branchWidth) + " " +
 		PadOrTrim(pr, prWidth) + " " +